@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	cmdcommon "boscoin.io/sebak/cmd/sebak/common"
+	"boscoin.io/sebak/lib/archive"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/voting"
+)
+
+var (
+	flagRepair bool
+)
+
+func init() {
+	var dbCmd = &cobra.Command{
+		Use:   "db",
+		Short: "manage the storage of node",
+		Run: func(c *cobra.Command, args []string) {
+			if len(args) < 1 {
+				c.Usage()
+			}
+		},
+	}
+
+	var exportCmd = &cobra.Command{
+		Use:   "export <archive file>",
+		Short: "export the whole chain into the archive file",
+		Args:  cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			if len(flagNetworkID) < 1 {
+				cmdcommon.PrintFlagsError(c, "--network-id", fmt.Errorf("--network-id must be provided"))
+			}
+
+			st, err := openSnapshotStorage()
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "--storage", err)
+			}
+			defer st.Close()
+
+			f, err := os.Create(args[0])
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "<archive file>", err)
+			}
+			defer f.Close()
+
+			header, err := archive.Export(st, f, []byte(flagNetworkID))
+			if err != nil {
+				cmdcommon.PrintError(c, fmt.Errorf("failed to export archive: %v", err))
+			}
+
+			log.Info("archive exported", "start", header.Start, "end", header.End)
+		},
+	}
+
+	var importCmd = &cobra.Command{
+		Use:   "import <archive file>",
+		Short: "import the chain from the archive file; the blocks are validated like the synced blocks",
+		Args:  cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			if len(flagNetworkID) < 1 {
+				cmdcommon.PrintFlagsError(c, "--network-id", fmt.Errorf("--network-id must be provided"))
+			}
+
+			conf := common.Config{
+				NetworkID:              []byte(flagNetworkID),
+				TxsLimit:               common.DefaultTransactionsInBallotLimit,
+				OpsInBallotLimit:       common.DefaultOperationsInBallotLimit,
+				CongressAccountAddress: flagCongressAddress,
+			}
+
+			if v, err := strconv.ParseUint(flagOperationsLimit, 10, 64); err != nil {
+				cmdcommon.PrintFlagsError(c, "--operations-limit", err)
+			} else {
+				conf.OpsLimit = int(v)
+			}
+
+			var validators []string
+			if vs, err := parseFlagValidators(flagValidators); err != nil {
+				cmdcommon.PrintFlagsError(c, "--validators", err)
+			} else {
+				for _, v := range vs {
+					validators = append(validators, v.Address())
+				}
+			}
+
+			var policy voting.ThresholdPolicy
+			if v, err := strconv.ParseUint(flagThreshold, 10, 64); err != nil {
+				cmdcommon.PrintFlagsError(c, "--threshold", err)
+			} else if policy, err = consensus.NewDefaultVotingThresholdPolicy(int(v)); err != nil {
+				cmdcommon.PrintFlagsError(c, "--threshold", err)
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "<archive file>", err)
+			}
+			defer f.Close()
+
+			st, err := openSnapshotStorage()
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "--storage", err)
+			}
+			defer st.Close()
+
+			last, err := archive.Import(st, f, conf, validators, policy, log)
+			if err != nil {
+				cmdcommon.PrintError(c, fmt.Errorf("failed to import archive after block %d: %v", last, err))
+			}
+
+			log.Info("archive imported", "last", last)
+		},
+	}
+
+	var checkCmd = &cobra.Command{
+		Use:   "check",
+		Short: "check the indices, the hash chain and the total balance of storage",
+		Args:  cobra.NoArgs,
+		Run: func(c *cobra.Command, args []string) {
+			st, err := openSnapshotStorage()
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "--storage", err)
+			}
+			defer st.Close()
+
+			problems, err := block.Check(st, flagRepair)
+			if err != nil {
+				cmdcommon.PrintError(c, fmt.Errorf("failed to check storage: %v", err))
+			}
+
+			var unrepaired int
+			for _, p := range problems {
+				if !p.Repaired {
+					unrepaired++
+				}
+				log.Error(
+					"problem found",
+					"kind", p.Kind,
+					"key", p.Key,
+					"message", p.Message,
+					"repairable", p.Repairable(),
+					"repaired", p.Repaired,
+				)
+			}
+
+			log.Info("storage checked", "problems", len(problems), "repaired", len(problems)-unrepaired)
+			if unrepaired > 0 {
+				st.Close()
+				os.Exit(1)
+			}
+		},
+	}
+	checkCmd.Flags().StringVar(&flagStorageConfigString, "storage", flagStorageConfigString, "storage uri; file://<path> or bolt://<path>")
+	checkCmd.Flags().BoolVar(&flagRepair, "repair", flagRepair, "repair the broken indices")
+
+	for _, sc := range []*cobra.Command{exportCmd, importCmd} {
+		sc.Flags().StringVar(&flagStorageConfigString, "storage", flagStorageConfigString, "storage uri; file://<path> or bolt://<path>")
+		sc.Flags().StringVar(&flagNetworkID, "network-id", flagNetworkID, "network id")
+	}
+	importCmd.Flags().StringVar(&flagOperationsLimit, "operations-limit", flagOperationsLimit, "operations limit in a transaction")
+	importCmd.Flags().StringVar(&flagCongressAddress, "set-congress-address", flagCongressAddress, "set congress address")
+	importCmd.Flags().StringVar(&flagValidators, "validators", flagValidators, "initial validators: <public address> [ <public address>...]")
+	importCmd.Flags().StringVar(&flagThreshold, "threshold", flagThreshold, "threshold")
+
+	dbCmd.AddCommand(exportCmd)
+	dbCmd.AddCommand(importCmd)
+	dbCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(dbCmd)
+}
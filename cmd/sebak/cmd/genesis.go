@@ -46,7 +46,7 @@ func init() {
 	}
 
 	genesisCmd.Flags().StringVar(&flagBalance, "balance", flagBalance, "initial balance of genesis block")
-	genesisCmd.Flags().StringVar(&flagStorageConfigString, "storage", flagStorageConfigString, "storage uri")
+	genesisCmd.Flags().StringVar(&flagStorageConfigString, "storage", flagStorageConfigString, "storage uri; memory://, file://<path> or bolt://<path>")
 	genesisCmd.Flags().StringVar(&flagNetworkID, "network-id", flagNetworkID, "network id")
 
 	rootCmd.AddCommand(genesisCmd)
@@ -150,7 +150,7 @@ func makeGenesisBlock(genesisKP, commonKP keypair.KP, networkID string, balance
 	return "", nil
 }
 
-func checkExistingAccounts(st *storage.LevelDBBackend, networkID, genesisAddress, commonAddress string, balance common.Amount) (created bool, err error) {
+func checkExistingAccounts(st storage.Backend, networkID, genesisAddress, commonAddress string, balance common.Amount) (created bool, err error) {
 	// check network id
 	var bt block.BlockTransaction
 	if bt, err = runner.GetGenesisTransaction(st); err != nil {
@@ -16,6 +16,7 @@ import (
 	"golang.org/x/net/http2"
 
 	cmdcommon "boscoin.io/sebak/cmd/sebak/common"
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/consensus"
@@ -61,6 +62,9 @@ var (
 	flagTLSCertFile                string = common.GetENVValue("SEBAK_TLS_CERT", "sebak.crt")
 	flagTLSKeyFile                 string = common.GetENVValue("SEBAK_TLS_KEY", "sebak.key")
 	flagUnfreezingPeriod           string = common.GetENVValue("SEBAK_UNFREEZING_PERIOD", strconv.FormatUint(common.UnfreezingPeriod, 10))
+	flagBlockVersionV1Height       string = common.GetENVValue("SEBAK_BLOCK_VERSION_V1_HEIGHT", strconv.FormatUint(common.BlockVersionV1Height, 10))
+	flagBlockVersionV2Height       string = common.GetENVValue("SEBAK_BLOCK_VERSION_V2_HEIGHT", strconv.FormatUint(common.BlockVersionV2Height, 10))
+	flagProposerSelector           string = common.GetENVValue("SEBAK_PROPOSER_SELECTOR", common.ProposerSelector)
 	flagValidators                 string = common.GetENVValue("SEBAK_VALIDATORS", "")
 	flagVerbose                    bool   = common.GetENVValue("SEBAK_VERBOSE", "0") == "1"
 	flagCongressAddress            string = common.GetENVValue("SEBAK_CONGRESS_ADDR", "")
@@ -86,6 +90,7 @@ var (
 	flagNTPServer       string              = common.GetENVValue("SEBAK_NTP_SERVER", "time.bora.net")
 	flagTimeSyncCommand string              = common.GetENVValue("SEBAK_TIME_SYNC_COMMAND", "")
 	flagStopConsensus   bool                = common.GetENVValue("SEBAK_STOP_CONSENSUS", "0") == "1"
+	flagPrune           string              = common.GetENVValue("SEBAK_PRUNE", "0")
 )
 
 var (
@@ -102,6 +107,7 @@ var (
 	syncCheckInterval       time.Duration
 	syncFetchTimeout        time.Duration
 	syncPoolSize            uint64
+	pruneBlocks             uint64
 	syncRetryInterval       time.Duration
 	threshold               int
 	timeoutACCEPT           time.Duration
@@ -181,7 +187,7 @@ func init() {
 	nodeCmd.Flags().StringVar(&flagBindURL, "bind", flagBindURL, "bind to listen on")
 	nodeCmd.Flags().StringVar(&flagJSONRPCBindURL, "jsonrpc-bind", flagJSONRPCBindURL, "bind to listen on for jsonrpc")
 	nodeCmd.Flags().StringVar(&flagPublishURL, "publish", flagPublishURL, "endpoint url for other nodes")
-	nodeCmd.Flags().StringVar(&flagStorageConfigString, "storage", flagStorageConfigString, "storage uri")
+	nodeCmd.Flags().StringVar(&flagStorageConfigString, "storage", flagStorageConfigString, "storage uri; memory://, file://<path> or bolt://<path>")
 	nodeCmd.Flags().StringVar(&flagTLSCertFile, "tls-cert", flagTLSCertFile, "tls certificate file")
 	nodeCmd.Flags().StringVar(&flagTLSKeyFile, "tls-key", flagTLSKeyFile, "tls key file")
 	nodeCmd.Flags().StringVar(&flagValidators, "validators", flagValidators, "set validator: <endpoint url>?address=<public address>[&alias=<alias>] [ <validator>...]")
@@ -193,6 +199,9 @@ func init() {
 	nodeCmd.Flags().StringVar(&flagBlockTime, "block-time", flagBlockTime, "block creation time")
 	nodeCmd.Flags().StringVar(&flagBlockTimeDelta, "block-time-delta", flagBlockTimeDelta, "variation period of block time")
 	nodeCmd.Flags().StringVar(&flagUnfreezingPeriod, "unfreezing-period", flagUnfreezingPeriod, "how long freezing must last")
+	nodeCmd.Flags().StringVar(&flagBlockVersionV1Height, "block-version-v1-height", flagBlockVersionV1Height, "block height, from which the blocks have the Merkle root of transactions; same in the network")
+	nodeCmd.Flags().StringVar(&flagBlockVersionV2Height, "block-version-v2-height", flagBlockVersionV2Height, "block height, from which the blocks have the state root; same in the network")
+	nodeCmd.Flags().StringVar(&flagProposerSelector, "proposer-selector", flagProposerSelector, "proposer selector, {sequential, reputation, random}; same in the network")
 	nodeCmd.Flags().StringVar(&flagOperationsLimit, "operations-limit", flagOperationsLimit, "operations limit in a transaction")
 	nodeCmd.Flags().StringVar(&flagTransactionsLimit, "transactions-limit", flagTransactionsLimit, "transactions limit in a ballot")
 	nodeCmd.Flags().StringVar(&flagOperationsInBallotLimit, "operations-in-ballot-limit", flagOperationsInBallotLimit, "operations limit in a ballot")
@@ -227,6 +236,7 @@ func init() {
 	nodeCmd.Flags().StringVar(&flagNTPServer, "ntp", flagNTPServer, "ntp server for time sync")
 	nodeCmd.Flags().StringVar(&flagTimeSyncCommand, "time-sync-command", flagTimeSyncCommand, "command for syncing local time")
 	nodeCmd.Flags().BoolVar(&flagStopConsensus, "stop-consensus", flagStopConsensus, "consensus will not start(testing only)")
+	nodeCmd.Flags().StringVar(&flagPrune, "prune", flagPrune, "keep the transactions and operations of only the latest N blocks (0= no pruning)")
 
 	rootCmd.AddCommand(nodeCmd)
 }
@@ -474,10 +484,47 @@ func parseFlagsNode() {
 		cmdcommon.PrintFlagsError(nodeCmd, "--unfreezing-period", err)
 	}
 
+	if common.BlockVersionV1Height, err = strconv.ParseUint(flagBlockVersionV1Height, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--block-version-v1-height", err)
+	} else if common.BlockVersionV1Height < common.FirstProposedBlockHeight {
+		cmdcommon.PrintFlagsError(
+			nodeCmd,
+			"--block-version-v1-height",
+			fmt.Errorf("must not be less than %d", common.FirstProposedBlockHeight),
+		)
+	}
+
+	if common.BlockVersionV2Height, err = strconv.ParseUint(flagBlockVersionV2Height, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--block-version-v2-height", err)
+	} else if common.BlockVersionV2Height < common.BlockVersionV1Height {
+		cmdcommon.PrintFlagsError(
+			nodeCmd,
+			"--block-version-v2-height",
+			fmt.Errorf("must not be less than --block-version-v1-height"),
+		)
+	}
+
+	switch flagProposerSelector {
+	case consensus.SequentialSelectorName, consensus.ReputationSelectorName, consensus.RandomSelectorName:
+		common.ProposerSelector = flagProposerSelector
+	default:
+		cmdcommon.PrintFlagsError(nodeCmd, "--proposer-selector", fmt.Errorf("'%s'", flagProposerSelector))
+	}
+
 	if syncPoolSize, err = strconv.ParseUint(flagSyncPoolSize, 10, 64); err != nil {
 		cmdcommon.PrintFlagsError(nodeCmd, "--sync-pool-size", err)
 	}
 
+	if pruneBlocks, err = strconv.ParseUint(flagPrune, 10, 64); err != nil {
+		cmdcommon.PrintFlagsError(nodeCmd, "--prune", err)
+	} else if pruneBlocks > 0 && pruneBlocks < common.MinimumPruneBlocks {
+		cmdcommon.PrintFlagsError(
+			nodeCmd,
+			"--prune",
+			fmt.Errorf("must be 0 or not less than %d", common.MinimumPruneBlocks),
+		)
+	}
+
 	syncRetryInterval = getTimeDuration(flagSyncRetryInterval, sync.RetryInterval, "--sync-retry-interval")
 	syncFetchTimeout = getTimeDuration(flagSyncFetchTimeout, sync.FetchTimeout, "--sync-fetch-timeout")
 	syncCheckInterval = getTimeDuration(flagSyncCheckInterval, sync.CheckBlockHeightInterval, "--sync-check-interval")
@@ -653,6 +700,9 @@ func parseFlagsNode() {
 	parsedFlags = append(parsedFlags, "\n\toperations-limit", flagOperationsLimit)
 	parsedFlags = append(parsedFlags, "\n\toperations-in-ballot-limit", flagOperationsInBallotLimit)
 	parsedFlags = append(parsedFlags, "\n\ttxpool-limit", flagTxPoolLimit)
+	parsedFlags = append(parsedFlags, "\n\tblock-version-v1-height", common.BlockVersionV1Height)
+	parsedFlags = append(parsedFlags, "\n\tblock-version-v2-height", common.BlockVersionV2Height)
+	parsedFlags = append(parsedFlags, "\n\tproposer-selector", common.ProposerSelector)
 	parsedFlags = append(parsedFlags, "\n\trate-limit-api", rateLimitRuleAPI)
 	parsedFlags = append(parsedFlags, "\n\trate-limit-node", rateLimitRuleNode)
 	parsedFlags = append(parsedFlags, "\n\thttp-cache-adapter", httpCacheAdapter)
@@ -662,6 +712,7 @@ func parseFlagsNode() {
 	parsedFlags = append(parsedFlags, "\n\tntp", flagNTPServer)
 	parsedFlags = append(parsedFlags, "\n\ttime-sync-command", flagTimeSyncCommand)
 	parsedFlags = append(parsedFlags, "\n\tstop-cosnensus", flagStopConsensus)
+	parsedFlags = append(parsedFlags, "\n\tprune", pruneBlocks)
 
 	// create current Node
 	localNode, err = node.NewLocalNode(kp, bindEndpoint, "")
@@ -756,6 +807,11 @@ func runNode() error {
 		return err
 	}
 
+	if err = block.Migrate(st); err != nil {
+		log.Crit("failed to migrate storage", "error", err)
+		return err
+	}
+
 	// get the initial balance of geness account
 	initialBalance, err := runner.GetGenesisBalance(st)
 	if err != nil {
@@ -788,6 +844,7 @@ func runNode() error {
 		WatcherMode:            flagWatcherMode,
 		DiscoveryEndpoints:     discoveryEndpoints,
 		StopConsensus:          flagStopConsensus,
+		PruneBlocks:            pruneBlocks,
 	}
 	connectionManager := network.NewValidatorConnectionManager(localNode, nt, policy, conf)
 
@@ -804,6 +861,7 @@ func runNode() error {
 	c.CheckBlockHeightInterval = syncCheckInterval
 	c.CheckPrevBlockInterval = syncCheckPrevBlock
 	c.WatchInterval = watchInterval
+	c.Policy = policy
 
 	syncer := c.NewSyncer()
 
@@ -891,6 +949,16 @@ func runNode() error {
 				return errors.DiscoveryPolicyDoesNotMatch
 			}
 
+			if err = nodeInfo.Policy.CheckNetworkParameters(); err != nil {
+				log.Crit(
+					err.Error(),
+					"endpoint", endpoint,
+					"error", err,
+					"remote-policy", nodeInfo.Policy,
+				)
+				return err
+			}
+
 			var validator *node.Validator
 			validator, err = node.NewValidator(
 				nodeInfo.Node.Address,
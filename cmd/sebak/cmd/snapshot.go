@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cmdcommon "boscoin.io/sebak/cmd/sebak/common"
+	"boscoin.io/sebak/lib/snapshot"
+	"boscoin.io/sebak/lib/storage"
+)
+
+var (
+	flagTrustedBlock string
+)
+
+func init() {
+	var snapshotCmd = &cobra.Command{
+		Use:   "snapshot",
+		Short: "export and import the state snapshot",
+		Run: func(c *cobra.Command, args []string) {
+			if len(args) < 1 {
+				c.Usage()
+			}
+		},
+	}
+
+	var exportCmd = &cobra.Command{
+		Use:   "export <snapshot file>",
+		Short: "export the state at the latest block into the snapshot file",
+		Args:  cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			st, err := openSnapshotStorage()
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "--storage", err)
+			}
+			defer st.Close()
+
+			s, err := snapshot.Export(st)
+			if err != nil {
+				cmdcommon.PrintError(c, fmt.Errorf("failed to export snapshot: %v", err))
+			}
+
+			f, err := os.Create(args[0])
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "<snapshot file>", err)
+			}
+			defer f.Close()
+
+			if err = s.Write(f); err != nil {
+				cmdcommon.PrintFlagsError(c, "<snapshot file>", err)
+			}
+
+			log.Info(
+				"snapshot exported",
+				"height", s.Body.Block.Height,
+				"block", s.Body.Block.Hash,
+				"hash", s.Hash,
+				"accounts", len(s.Body.Accounts),
+			)
+		},
+	}
+
+	var importCmd = &cobra.Command{
+		Use:   "import <snapshot file>",
+		Short: "seed the empty storage from the snapshot file; the node syncs from the next block of snapshot",
+		Args:  cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			if len(flagTrustedBlock) < 1 {
+				cmdcommon.PrintFlagsError(c, "--trusted-block", fmt.Errorf("--trusted-block must be provided"))
+			}
+			if len(flagNetworkID) < 1 {
+				cmdcommon.PrintFlagsError(c, "--network-id", fmt.Errorf("--network-id must be provided"))
+			}
+
+			f, err := os.Open(args[0])
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "<snapshot file>", err)
+			}
+			defer f.Close()
+
+			s, err := snapshot.Read(f)
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "<snapshot file>", err)
+			}
+
+			st, err := openSnapshotStorage()
+			if err != nil {
+				cmdcommon.PrintFlagsError(c, "--storage", err)
+			}
+			defer st.Close()
+
+			if err = snapshot.Import(st, s, flagTrustedBlock, []byte(flagNetworkID)); err != nil {
+				cmdcommon.PrintError(c, fmt.Errorf("failed to import snapshot: %v", err))
+			}
+
+			log.Info(
+				"snapshot imported",
+				"height", s.Body.Block.Height,
+				"block", s.Body.Block.Hash,
+				"hash", s.Hash,
+				"accounts", len(s.Body.Accounts),
+			)
+		},
+	}
+
+	for _, sc := range []*cobra.Command{exportCmd, importCmd} {
+		sc.Flags().StringVar(&flagStorageConfigString, "storage", flagStorageConfigString, "storage uri; file://<path> or bolt://<path>")
+	}
+	importCmd.Flags().StringVar(&flagNetworkID, "network-id", flagNetworkID, "network id")
+	importCmd.Flags().StringVar(&flagTrustedBlock, "trusted-block", flagTrustedBlock, "hash of the trusted block, which the snapshot is made at")
+
+	snapshotCmd.AddCommand(exportCmd)
+	snapshotCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+func openSnapshotStorage() (storage.Backend, error) {
+	storageConfig, err := storage.NewConfigFromString(flagStorageConfigString)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.NewStorage(storageConfig)
+}
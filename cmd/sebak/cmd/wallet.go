@@ -24,4 +24,7 @@ func init() {
 	rootCmd.AddCommand(walletCmd)
 	walletCmd.AddCommand(wallet.PaymentCmd)
 	walletCmd.AddCommand(wallet.UnfreezeRequestCmd)
+	walletCmd.AddCommand(wallet.BuildCmd)
+	walletCmd.AddCommand(wallet.SignCmd)
+	walletCmd.AddCommand(wallet.SubmitCmd)
 }
@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cmdcommon "boscoin.io/sebak/cmd/sebak/common"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+var (
+	BuildCmd *cobra.Command
+)
+
+func init() {
+	BuildCmd = &cobra.Command{
+		Use:   "build <sender address> <receiver address> <amount>",
+		Short: "Build the unsigned transaction envelope, which can be signed offline by 'wallet sign'",
+		Args:  cobra.ExactArgs(3),
+		Run: func(c *cobra.Command, args []string) {
+			var err error
+			var amount common.Amount
+			var sender keypair.KP
+			var receiver keypair.KP
+			var endpoint *common.Endpoint
+
+			// Sender's public key; the secret seed is not needed to build
+			if sender, err = keypair.Parse(args[0]); err != nil {
+				cmdcommon.PrintFlagsError(c, "<sender address>", err)
+			} else if _, err = sender.Sign([]byte("witness")); err == nil {
+				cmdcommon.PrintFlagsError(c, "<sender address>", fmt.Errorf("Provided key is a secret seed, not an address"))
+			}
+
+			// Receiver's public key
+			if receiver, err = keypair.Parse(args[1]); err != nil {
+				cmdcommon.PrintFlagsError(c, "<receiver address>", err)
+			} else if _, err = receiver.Sign([]byte("witness")); err == nil {
+				cmdcommon.PrintFlagsError(c, "<receiver address>", fmt.Errorf("Provided key is a secret seed, not an address"))
+			}
+
+			// Amount
+			if amount, err = cmdcommon.ParseAmountFromString(args[2]); err != nil {
+				cmdcommon.PrintFlagsError(c, "<amount>", err)
+			}
+			if flagFreeze == true && (amount%common.Unit) != 0 {
+				cmdcommon.PrintFlagsError(c, "<amount>",
+					fmt.Errorf("Amount should be an exact multiple of %v when --freeze is provided", common.Unit))
+			}
+
+			var fee common.Amount
+			if len(flagFee) > 0 {
+				if fee, err = cmdcommon.ParseAmountFromString(flagFee); err != nil {
+					cmdcommon.PrintFlagsError(c, "--fee", err)
+				}
+			}
+
+			if endpoint, err = common.ParseEndpoint(flagEndpoint); err != nil {
+				cmdcommon.PrintFlagsError(c, "--endpoint", err)
+			}
+
+			client := newNetworkClient(endpoint)
+
+			var nodeInfo node.NodeInfo
+			if nodeInfo, err = getNodeInfo(client); err != nil {
+				log.Fatal("Could not fetch node info: ", err)
+				os.Exit(1)
+			}
+
+			// The envelope keeps the network id of node, so it can not be
+			// signed for the other network
+			networkID := nodeInfo.Policy.NetworkID
+			if len(flagNetworkID) > 0 && flagNetworkID != networkID {
+				cmdcommon.PrintFlagsError(c, "--network-id", fmt.Errorf("network id of endpoint is '%s'", networkID))
+			}
+
+			var senderAccount block.BlockAccount
+			if senderAccount, err = getSenderDetails(client, sender); err != nil {
+				log.Fatal("Could not fetch sender account: ", err)
+				os.Exit(1)
+			}
+
+			var tx transaction.Transaction
+			if flagFreeze {
+				tx = MakeTransactionCreateAccount(sender, receiver, amount, senderAccount.SequenceID, true)
+			} else if flagCreateAccount {
+				tx = MakeTransactionCreateAccount(sender, receiver, amount, senderAccount.SequenceID, false)
+			} else {
+				tx = MakeTransactionPayment(sender, receiver, amount, senderAccount.SequenceID)
+			}
+
+			// By default, the fee follows the current base fee of node
+			if len(flagFee) > 0 {
+				tx.B.Fee = fee
+			} else if !flagFreeze && nodeInfo.Block.BaseFee > 0 {
+				tx.B.Fee = tx.MinimumFee(nodeInfo.Block.BaseFee)
+			}
+			tx.H.Hash = tx.B.MakeHashString()
+
+			if _, err = senderAccount.GetBalance().Sub(tx.TotalAmount(true)); err != nil {
+				fmt.Printf("Attempting to draft %v GON (+ %v fees), but sender account only have %v GON\n",
+					amount, tx.B.Fee, senderAccount.GetBalance())
+				os.Exit(1)
+			}
+
+			if err = writeEnvelope(transaction.NewEnvelope([]byte(networkID), tx), flagOutput); err != nil {
+				log.Fatal("Could not write envelope: ", err)
+				os.Exit(1)
+			}
+		},
+	}
+	BuildCmd.Flags().StringVar(&flagEndpoint, "endpoint", flagEndpoint, "endpoint to fetch the sender account and node info from")
+	BuildCmd.Flags().StringVar(&flagNetworkID, "network-id", flagNetworkID, "network id; if given, it must be the network id of endpoint")
+	BuildCmd.Flags().BoolVar(&flagCreateAccount, "create", flagCreateAccount, "Whether or not the account should be created")
+	BuildCmd.Flags().BoolVar(&flagFreeze, "freeze", flagFreeze, "When present, the payment is a frozen account creation. Imply --create.")
+	BuildCmd.Flags().StringVar(&flagFee, "fee", flagFee, "fee of transaction; by default, the base fee of endpoint")
+	BuildCmd.Flags().StringVar(&flagOutput, "output", flagOutput, "file to write the envelope; by default, it is printed")
+}
@@ -0,0 +1,65 @@
+package wallet
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+var (
+	flagOutput string
+	flagFee    string
+)
+
+// readEnvelope reads the transaction envelope from file.
+func readEnvelope(path string) (e transaction.Envelope, err error) {
+	var b []byte
+	if b, err = ioutil.ReadFile(path); err != nil {
+		return
+	}
+
+	return transaction.NewEnvelopeFromJSON(b)
+}
+
+// writeEnvelope writes the transaction envelope to file, or to stdout if
+// `path` is empty.
+func writeEnvelope(e transaction.Envelope, path string) (err error) {
+	var b []byte
+	if b, err = e.Serialize(); err != nil {
+		return
+	}
+
+	if len(path) < 1 {
+		fmt.Println(string(b))
+		return
+	}
+
+	return ioutil.WriteFile(path, b, 0600)
+}
+
+// getNodeInfo fetches the node information of the endpoint.
+func getNodeInfo(conn *network.HTTP2NetworkClient) (nodeInfo node.NodeInfo, err error) {
+	var retBody []byte
+	if retBody, err = conn.GetNodeInfo(); err != nil {
+		return
+	}
+
+	return node.NewNodeInfoFromJSON(retBody)
+}
+
+func newNetworkClient(endpoint *common.Endpoint) *network.HTTP2NetworkClient {
+	// Keep-alive ignores timeout/idle timeout
+	connection, err := common.NewHTTP2Client(0, 0, true)
+	if err != nil {
+		log.Fatal("Error while creating network client: ", err)
+		os.Exit(1)
+	}
+
+	return network.NewHTTP2NetworkClient(endpoint, connection)
+}
@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cmdcommon "boscoin.io/sebak/cmd/sebak/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+var (
+	SignCmd *cobra.Command
+)
+
+func init() {
+	SignCmd = &cobra.Command{
+		Use:   "sign <envelope file> <secret seed>",
+		Short: "Sign the transaction envelope offline",
+		Args:  cobra.ExactArgs(2),
+		Run: func(c *cobra.Command, args []string) {
+			var err error
+			var kp keypair.KP
+			var envelope transaction.Envelope
+
+			if envelope, err = readEnvelope(args[0]); err != nil {
+				cmdcommon.PrintFlagsError(c, "<envelope file>", err)
+			}
+
+			if kp, err = keypair.Parse(args[1]); err != nil {
+				cmdcommon.PrintFlagsError(c, "<secret seed>", err)
+			} else if _, ok := kp.(*keypair.Full); !ok {
+				cmdcommon.PrintFlagsError(c, "<secret seed>", fmt.Errorf("Provided key is an address, not a secret seed"))
+			}
+
+			// The network id must be given explicitly, not from the envelope
+			if len(flagNetworkID) == 0 {
+				cmdcommon.PrintFlagsError(c, "--network-id", fmt.Errorf("A --network-id needs to be provided"))
+			}
+
+			if err = envelope.Sign(kp, []byte(flagNetworkID)); err != nil {
+				cmdcommon.PrintFlagsError(c, "--network-id", err)
+			}
+
+			if flagVerbose == true {
+				fmt.Fprintln(os.Stderr, envelope.Transaction)
+			}
+
+			if err = writeEnvelope(envelope, flagOutput); err != nil {
+				log.Fatal("Could not write envelope: ", err)
+				os.Exit(1)
+			}
+		},
+	}
+	SignCmd.Flags().StringVar(&flagNetworkID, "network-id", flagNetworkID, "network id")
+	SignCmd.Flags().StringVar(&flagOutput, "output", flagOutput, "file to write the signed envelope; by default, it is printed")
+	SignCmd.Flags().BoolVar(&flagVerbose, "verbose", flagVerbose, "Print the signed transaction")
+}
@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	cmdcommon "boscoin.io/sebak/cmd/sebak/common"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+var (
+	SubmitCmd *cobra.Command
+)
+
+func init() {
+	SubmitCmd = &cobra.Command{
+		Use:   "submit <envelope file>",
+		Short: "Submit the signed transaction envelope",
+		Args:  cobra.ExactArgs(1),
+		Run: func(c *cobra.Command, args []string) {
+			var err error
+			var endpoint *common.Endpoint
+			var envelope transaction.Envelope
+
+			if envelope, err = readEnvelope(args[0]); err != nil {
+				cmdcommon.PrintFlagsError(c, "<envelope file>", err)
+			}
+			if !envelope.IsSigned() {
+				cmdcommon.PrintFlagsError(c, "<envelope file>", fmt.Errorf("envelope is not signed"))
+			}
+
+			if endpoint, err = common.ParseEndpoint(flagEndpoint); err != nil {
+				cmdcommon.PrintFlagsError(c, "--endpoint", err)
+			}
+
+			client := newNetworkClient(endpoint)
+
+			var nodeInfo node.NodeInfo
+			if nodeInfo, err = getNodeInfo(client); err != nil {
+				log.Fatal("Could not fetch node info: ", err)
+				os.Exit(1)
+			}
+			if nodeInfo.Policy.NetworkID != envelope.NetworkID {
+				cmdcommon.PrintFlagsError(c, "<envelope file>", errors.EnvelopeNetworkIDMismatch)
+			}
+
+			var retBody []byte
+			if retBody, err = client.SendTransaction(envelope.Transaction); err != nil {
+				log.Fatal("Network error: ", err, " body: ", string(retBody))
+				os.Exit(1)
+			}
+
+			fmt.Println(string(retBody))
+		},
+	}
+	SubmitCmd.Flags().StringVar(&flagEndpoint, "endpoint", flagEndpoint, "endpoint to send the transaction to (https / memory address)")
+}
@@ -0,0 +1,183 @@
+// Package archive reads and writes the blocks of chain in the portable
+// archive; it is used to back up the chain and to move it to the other node.
+//
+// The archive starts with the magic bytes and the version, and the rest is
+// gzip compressed; it has the `Header` and the `Item`s of blocks in height
+// order. Each record is JSON, which is prefixed by its length in 4 bytes big
+// endian.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+const (
+	ArchiveVersionV1 uint32 = 1
+
+	// ArchiveVersion is the version of the new archive.
+	ArchiveVersion = ArchiveVersionV1
+)
+
+var (
+	magic = []byte("SEBAKARC")
+
+	// MaxRecordSize is the maximum size of one record in bytes.
+	MaxRecordSize uint32 = 64 * 1024 * 1024
+)
+
+type Header struct {
+	Version   uint32 `json:"version"`
+	NetworkID []byte `json:"network_id"`
+	Start     uint64 `json:"start"` // height of the first block
+	End       uint64 `json:"end"`   // height of the last block
+	Created   string `json:"created"`
+}
+
+// Item is the block with its transactions, proposer transaction and
+// certificate. The genesis block does not have the proposer transaction and
+// the certificate.
+type Item struct {
+	Block               block.Block                 `json:"block"`
+	Transactions        []transaction.Transaction   `json:"transactions"`
+	ProposerTransaction *ballot.ProposerTransaction `json:"proposer_transaction"`
+	Certificate         *ballot.Certificate         `json:"certificate"`
+}
+
+func invalidArchive(reason string) error {
+	return errors.InvalidArchive.Clone().SetData("error", reason)
+}
+
+type Writer struct {
+	gz *gzip.Writer
+}
+
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	if _, err := w.Write(magic); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(w, binary.BigEndian, header.Version); err != nil {
+		return nil, err
+	}
+
+	aw := &Writer{gz: gzip.NewWriter(w)}
+	if err := aw.writeRecord(header); err != nil {
+		return nil, err
+	}
+
+	return aw, nil
+}
+
+func (w *Writer) writeRecord(v interface{}) (err error) {
+	var b []byte
+	if b, err = json.Marshal(v); err != nil {
+		return
+	}
+	if uint32(len(b)) > MaxRecordSize {
+		return invalidArchive("too big record")
+	}
+
+	if err = binary.Write(w.gz, binary.BigEndian, uint32(len(b))); err != nil {
+		return
+	}
+	_, err = w.gz.Write(b)
+
+	return
+}
+
+func (w *Writer) Write(item Item) error {
+	return w.writeRecord(item)
+}
+
+// Close flushes the compressed records; the underlying writer is not closed.
+func (w *Writer) Close() error {
+	return w.gz.Close()
+}
+
+type Reader struct {
+	gz     *gzip.Reader
+	header Header
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+
+	m := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, m); err != nil || !bytes.Equal(m, magic) {
+		return nil, invalidArchive("not archive")
+	}
+
+	var version uint32
+	if err := binary.Read(br, binary.BigEndian, &version); err != nil {
+		return nil, invalidArchive("version not found")
+	} else if version != ArchiveVersionV1 {
+		return nil, invalidArchive("unknown version")
+	}
+
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, invalidArchive(err.Error())
+	}
+
+	ar := &Reader{gz: gz}
+	if err = ar.readRecord(&ar.header); err != nil {
+		if err == io.EOF {
+			err = invalidArchive("header not found")
+		}
+		return nil, err
+	}
+	if ar.header.Version != version {
+		return nil, invalidArchive("different version in header")
+	}
+
+	return ar, nil
+}
+
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// readRecord reads the next record; at the end of archive, it returns
+// `io.EOF`.
+func (r *Reader) readRecord(v interface{}) error {
+	var size uint32
+	if err := binary.Read(r.gz, binary.BigEndian, &size); err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return invalidArchive(err.Error())
+	}
+	if size > MaxRecordSize {
+		return invalidArchive("too big record")
+	}
+
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r.gz, b); err != nil {
+		return invalidArchive(err.Error())
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return invalidArchive(err.Error())
+	}
+
+	return nil
+}
+
+// Read returns the next `Item`; at the end of archive, it returns `io.EOF`.
+func (r *Reader) Read() (item Item, err error) {
+	err = r.readRecord(&item)
+	return
+}
+
+func (r *Reader) Close() error {
+	return r.gz.Close()
+}
@@ -0,0 +1,346 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node/runner"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/sync"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/voting"
+)
+
+type testArchiveHelper struct {
+	st         storage.Backend
+	conf       common.Config
+	kps        []*keypair.Full
+	validators []string
+	policy     voting.ThresholdPolicy
+}
+
+// Prepare makes the blocks after genesis, which have only the proposer
+// transaction and are agreed by the validators; the blocks are saved by
+// `sync.BlockValidator`.
+func (p *testArchiveHelper) Prepare(t *testing.T, n int) {
+	p.st = block.InitTestBlockchain()
+	p.conf = common.NewTestConfig()
+	p.conf.CommonAccountAddress = block.CommonKP.Address()
+
+	for i := 0; i < 4; i++ {
+		kp := keypair.Random()
+		p.kps = append(p.kps, kp)
+		p.validators = append(p.validators, kp.Address())
+	}
+
+	var err error
+	p.policy, err = consensus.NewDefaultVotingThresholdPolicy(67)
+	require.NoError(t, err)
+
+	v := sync.NewBlockValidator(p.st, transaction.NewPool(p.conf), p.conf, sync.WithCertificate(p.validators, p.policy))
+
+	prev := block.GetLatestBlock(p.st)
+	for i := 0; i < n; i++ {
+		kp := p.kps[i%len(p.kps)]
+		basis := voting.Basis{Height: prev.Height, BlockHash: prev.Hash, TotalTxs: prev.TotalTxs, TotalOps: prev.TotalOps}
+
+		blt := ballot.NewBallot(kp.Address(), kp.Address(), basis, nil)
+		opi, _ := ballot.NewInflationFromBallot(*blt, p.conf.CommonAccountAddress, p.conf.InitialBalance)
+		opc, _ := ballot.NewCollectTxFeeFromBallot(*blt, p.conf.CommonAccountAddress)
+		ptx, err := ballot.NewProposerTransactionFromBallot(*blt, opc, opi)
+		require.NoError(t, err)
+		ptx.Sign(kp, p.conf.NetworkID)
+
+		// the state root after the proposer transaction
+		bs, err := p.st.OpenBatch()
+		require.NoError(t, err)
+		require.NoError(t, runner.ProcessProposerTransaction(bs, ptx, common.NopLogger()))
+		root, err := runner.CommitState(bs, prev.StateRoot, nil, ptx)
+		require.NoError(t, err)
+		bs.Discard()
+
+		blk := block.NewBlockWithVersion(
+			block.GetBlockVersion(prev.Height+1),
+			kp.Address(),
+			voting.Basis{
+				Height:    prev.Height + 1,
+				BlockHash: prev.Hash,
+				TotalTxs:  prev.TotalTxs + 1,
+				TotalOps:  prev.TotalOps + uint64(len(ptx.B.Operations)),
+			},
+			ptx.GetHash(),
+			nil,
+			common.NowISO8601(),
+			root,
+		)
+
+		var ballots []ballot.Ballot
+		for _, vkp := range p.kps {
+			b := ballot.NewBallot(vkp.Address(), kp.Address(), basis, nil)
+			b.SetProposerTransaction(ptx)
+			b.SetVote(ballot.StateACCEPT, voting.YES)
+			b.Sign(vkp, p.conf.NetworkID)
+			ballots = append(ballots, *b)
+		}
+		c := ballot.NewCertificate(*blk, ballots)
+
+		si := &sync.SyncInfo{Height: blk.Height, Block: blk, Ptx: &ptx, Certificate: &c}
+		require.NoError(t, v.Validate(context.Background(), si))
+
+		prev = *blk
+	}
+}
+
+func (p *testArchiveHelper) Done() {
+	p.st.Close()
+}
+
+func TestArchiveWriteRead(t *testing.T) {
+	header := Header{Version: ArchiveVersion, NetworkID: []byte("showme"), Start: 1, End: 3, Created: common.NowISO8601()}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, header)
+	require.NoError(t, err)
+
+	for height := header.Start; height <= header.End; height++ {
+		require.NoError(t, w.Write(Item{Block: block.Block{Header: block.Header{Height: height}}}))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := NewReader(&buf)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Equal(t, header, r.Header())
+
+	for height := header.Start; height <= header.End; height++ {
+		item, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, height, item.Block.Height)
+	}
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestArchiveReadInvalid(t *testing.T) {
+	header := Header{Version: ArchiveVersion, NetworkID: []byte("showme")}
+
+	{ // not archive
+		_, err := NewReader(bytes.NewBufferString("findme"))
+		require.Equal(t, errors.InvalidArchive.Code, err.(*errors.Error).Code)
+	}
+
+	{ // unknown version
+		var buf bytes.Buffer
+		buf.Write(magic)
+		binary.Write(&buf, binary.BigEndian, ArchiveVersion+1)
+
+		_, err := NewReader(&buf)
+		require.Equal(t, errors.InvalidArchive.Code, err.(*errors.Error).Code)
+	}
+
+	{ // broken record
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, header)
+		require.NoError(t, err)
+		binary.Write(w.gz, binary.BigEndian, uint32(100))
+		w.gz.Write([]byte("{}"))
+		require.NoError(t, w.Close())
+
+		r, err := NewReader(&buf)
+		require.NoError(t, err)
+		_, err = r.Read()
+		require.Equal(t, errors.InvalidArchive.Code, err.(*errors.Error).Code)
+	}
+}
+
+func TestArchiveExportImport(t *testing.T) {
+	p := &testArchiveHelper{}
+	p.Prepare(t, 5)
+	defer p.Done()
+
+	latest := block.GetLatestBlock(p.st)
+
+	var buf bytes.Buffer
+	header, err := Export(p.st, &buf, p.conf.NetworkID)
+	require.NoError(t, err)
+	require.Equal(t, common.GenesisBlockHeight, header.Start)
+	require.Equal(t, latest.Height, header.End)
+
+	b := buf.Bytes()
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	last, err := Import(st, bytes.NewReader(b), p.conf, p.validators, p.policy, nil)
+	require.NoError(t, err)
+	require.Equal(t, latest.Height, last)
+	require.Equal(t, latest.Hash, block.GetLatestBlock(st).Hash)
+
+	for height := common.GenesisBlockHeight; height <= latest.Height; height++ {
+		expected, err := block.GetBlockByHeight(p.st, height)
+		require.NoError(t, err)
+		imported, err := block.GetBlockByHeight(st, height)
+		require.NoError(t, err)
+		require.Equal(t, expected.Hash, imported.Hash)
+		require.Equal(t, expected.StateRoot, imported.StateRoot)
+
+		if height == common.GenesisBlockHeight {
+			continue
+		}
+		exists, err := block.ExistsTransactionPool(st, imported.ProposerTransaction)
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+
+	{ // accounts
+		expected, err := block.GetBlockAccount(p.st, block.CommonKP.Address())
+		require.NoError(t, err)
+		imported, err := block.GetBlockAccount(st, block.CommonKP.Address())
+		require.NoError(t, err)
+		require.Equal(t, expected.Balance, imported.Balance)
+	}
+
+	{ // imported again over the same blocks
+		last, err := Import(st, bytes.NewReader(b), p.conf, p.validators, p.policy, nil)
+		require.NoError(t, err)
+		require.Equal(t, latest.Height, last)
+	}
+
+	{ // different network id
+		conf := p.conf
+		conf.NetworkID = []byte("findme")
+
+		st := storage.NewTestStorage()
+		defer st.Close()
+
+		_, err := Import(st, bytes.NewReader(b), conf, p.validators, p.policy, nil)
+		require.Equal(t, errors.InvalidArchive.Code, err.(*errors.Error).Code)
+	}
+}
+
+func TestArchiveImportInvalidBlock(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	p := &testArchiveHelper{}
+	p.Prepare(t, 3)
+	defer p.Done()
+
+	var buf bytes.Buffer
+	header, err := Export(p.st, &buf, p.conf.NetworkID)
+	require.NoError(t, err)
+
+	// change the state root of the last block
+	changed := changeArchiveItem(t, buf.Bytes(), func(item *Item) {
+		item.Block.StateRoot = "findme"
+	})
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	last, err := Import(st, changed, p.conf, p.validators, p.policy, nil)
+	require.Equal(t, errors.HashDoesNotMatch, err)
+	require.Equal(t, header.End-1, last)
+
+	exists, err := block.ExistsBlockByHeight(st, header.End)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+// changeArchiveItem writes the archive again after changing the item of the
+// last block.
+func changeArchiveItem(t *testing.T, b []byte, change func(*Item)) *bytes.Buffer {
+	r, err := NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+
+	var changed bytes.Buffer
+	w, err := NewWriter(&changed, r.Header())
+	require.NoError(t, err)
+	for {
+		item, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		if item.Block.Height == r.Header().End {
+			change(&item)
+		}
+		require.NoError(t, w.Write(item))
+	}
+	require.NoError(t, w.Close())
+
+	return &changed
+}
+
+func TestArchiveImportInvalidCertificate(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	p := &testArchiveHelper{}
+	p.Prepare(t, 3)
+	defer p.Done()
+
+	var buf bytes.Buffer
+	header, err := Export(p.st, &buf, p.conf.NetworkID)
+	require.NoError(t, err)
+	b := buf.Bytes()
+
+	{ // certificate is missing
+		changed := changeArchiveItem(t, b, func(item *Item) {
+			item.Certificate = nil
+		})
+
+		st := storage.NewTestStorage()
+		defer st.Close()
+
+		last, err := Import(st, changed, p.conf, p.validators, p.policy, nil)
+		require.Equal(t, errors.InvalidArchive.Code, err.(*errors.Error).Code)
+		require.Equal(t, header.End-1, last)
+	}
+
+	{ // certificate is signed by the unknown validators
+		changed := changeArchiveItem(t, b, func(item *Item) {
+			var ballots []ballot.Ballot
+			for _, b := range item.Certificate.Ballots {
+				b.Sign(keypair.Random(), p.conf.NetworkID)
+				ballots = append(ballots, b)
+			}
+			c := ballot.NewCertificate(item.Block, ballots)
+			item.Certificate = &c
+		})
+
+		st := storage.NewTestStorage()
+		defer st.Close()
+
+		last, err := Import(st, changed, p.conf, p.validators, p.policy, nil)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+		require.Equal(t, header.End-1, last)
+
+		exists, err := block.ExistsBlockByHeight(st, header.End)
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+}
+
+func TestArchiveExportPruned(t *testing.T) {
+	p := &testArchiveHelper{}
+	p.Prepare(t, 3)
+	defer p.Done()
+
+	require.NoError(t, block.SavePrunedBlockHeight(p.st, 2))
+
+	var buf bytes.Buffer
+	_, err := Export(p.st, &buf, p.conf.NetworkID)
+	require.Equal(t, errors.BlockTransactionPruned, err)
+}
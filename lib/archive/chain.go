@@ -0,0 +1,286 @@
+package archive
+
+import (
+	"context"
+	"io"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node/runner"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/sync"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// Export writes the blocks from genesis to the latest block into the
+// archive. The storage, which is pruned, can not be exported.
+func Export(st storage.Backend, w io.Writer, networkID []byte) (header Header, err error) {
+	var sst storage.Backend
+	if sst, err = st.OpenSnapshot(); err != nil {
+		return
+	}
+	defer sst.Release()
+
+	if block.GetLowestFullBlockHeight(sst) > common.GenesisBlockHeight {
+		err = errors.BlockTransactionPruned
+		return
+	}
+
+	var exists bool
+	if exists, err = block.ExistsBlockByHeight(sst, common.GenesisBlockHeight); err != nil {
+		return
+	} else if !exists {
+		err = errors.BlockNotFound
+		return
+	}
+
+	header = Header{
+		Version:   ArchiveVersion,
+		NetworkID: networkID,
+		Start:     common.GenesisBlockHeight,
+		End:       block.GetLatestBlock(sst).Height,
+		Created:   common.NowISO8601(),
+	}
+
+	var aw *Writer
+	if aw, err = NewWriter(w, header); err != nil {
+		return
+	}
+
+	for height := header.Start; height <= header.End; height++ {
+		var item Item
+		if item, err = exportItem(sst, height); err != nil {
+			return
+		}
+		if err = aw.Write(item); err != nil {
+			return
+		}
+	}
+
+	err = aw.Close()
+	return
+}
+
+func exportItem(st storage.Backend, height uint64) (item Item, err error) {
+	if item.Block, err = block.GetBlockByHeight(st, height); err != nil {
+		return
+	}
+
+	for _, hash := range item.Block.Transactions {
+		var tx transaction.Transaction
+		if tx, err = getTransaction(st, hash); err != nil {
+			return
+		}
+		item.Transactions = append(item.Transactions, tx)
+	}
+
+	if len(item.Block.ProposerTransaction) > 0 {
+		var tx transaction.Transaction
+		if tx, err = getTransaction(st, item.Block.ProposerTransaction); err != nil {
+			return
+		}
+		item.ProposerTransaction = &ballot.ProposerTransaction{Transaction: tx}
+	}
+
+	var exists bool
+	if exists, err = ballot.ExistsCertificate(st, item.Block.Hash); err != nil || !exists {
+		return
+	}
+
+	var c ballot.Certificate
+	if c, err = ballot.GetCertificate(st, item.Block.Hash); err != nil {
+		return
+	}
+	item.Certificate = &c
+
+	return
+}
+
+func getTransaction(st storage.Backend, hash string) (tx transaction.Transaction, err error) {
+	var tp block.TransactionPool
+	if tp, err = block.GetTransactionPool(st, hash); err != nil {
+		if err == errors.StorageRecordDoesNotExist {
+			err = errors.TransactionNotFound
+		}
+		return
+	}
+
+	tx = tp.Transaction()
+	return
+}
+
+// Import reads the blocks from the archive and saves them; the blocks are
+// validated by `sync.BlockValidator` like the synced blocks. The blocks, which
+// already exist in the storage, must be same with the archive, so the archive
+// can be imported over the storage, which has the part of chain.
+//
+// The genesis block is made again from the genesis transaction of archive.
+// The certificates of the other blocks are checked by the validators, which
+// are calculated from the initial validators, and the threshold policy; the
+// block since `block.BlockVersionV1` must have the certificate.
+func Import(st storage.Backend, r io.Reader, conf common.Config, validators []string, policy voting.ThresholdPolicy, logger logging.Logger) (last uint64, err error) {
+	if logger == nil {
+		logger = common.NopLogger()
+	}
+
+	var ar *Reader
+	if ar, err = NewReader(r); err != nil {
+		return
+	}
+	defer ar.Close()
+
+	if string(ar.Header().NetworkID) != string(conf.NetworkID) {
+		err = invalidArchive("different network id")
+		return
+	}
+
+	var validator *sync.BlockValidator
+	for {
+		var item Item
+		if item, err = ar.Read(); err == io.EOF {
+			err = nil
+			return
+		} else if err != nil {
+			return
+		}
+
+		height := item.Block.Height
+		if height == common.GenesisBlockHeight {
+			if err = importGenesis(st, item, conf.NetworkID); err != nil {
+				return
+			}
+			last = height
+			continue
+		}
+
+		var exists bool
+		if exists, err = block.ExistsBlockByHeight(st, height); err != nil {
+			return
+		} else if exists {
+			var blk block.Block
+			if blk, err = block.GetBlockByHeight(st, height); err != nil {
+				return
+			} else if blk.Hash != item.Block.Hash {
+				err = invalidArchive("different block exists")
+				return
+			}
+			last = height
+			continue
+		}
+
+		// `sync.BlockValidator` waits for the previous block
+		if exists, err = block.ExistsBlockByHeight(st, height-1); err != nil {
+			return
+		} else if !exists {
+			err = invalidArchive("previous block not found")
+			return
+		}
+
+		if item.Certificate == nil && item.Block.Version >= block.BlockVersionV1 {
+			err = invalidArchive("certificate not found")
+			return
+		}
+
+		if validator == nil {
+			if validator, err = newValidator(st, conf, validators, policy, logger); err != nil {
+				return
+			}
+		}
+
+		if err = validator.Validate(context.Background(), newSyncInfo(item)); err != nil {
+			return
+		}
+		last = height
+
+		logger.Debug("block imported", "height", height, "hash", item.Block.Hash)
+	}
+}
+
+func newValidator(st storage.Backend, conf common.Config, validators []string, policy voting.ThresholdPolicy, logger logging.Logger) (*sync.BlockValidator, error) {
+	commonAccount, err := runner.GetCommonAccount(st)
+	if err != nil {
+		return nil, err
+	}
+	conf.CommonAccountAddress = commonAccount.Address
+
+	return sync.NewBlockValidator(st, transaction.NewPool(conf), conf, sync.WithCertificate(validators, policy)), nil
+}
+
+func newSyncInfo(item Item) *sync.SyncInfo {
+	blk := item.Block
+
+	var bts []*block.BlockTransaction
+	for _, tx := range item.Transactions {
+		bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+		bts = append(bts, &bt)
+	}
+
+	return &sync.SyncInfo{
+		Height:      blk.Height,
+		Block:       &blk,
+		Bts:         bts,
+		Ptx:         item.ProposerTransaction,
+		Certificate: item.Certificate,
+	}
+}
+
+// importGenesis makes the genesis block from the genesis transaction of
+// archive; the new genesis block must be same with the archive.
+func importGenesis(st storage.Backend, item Item, networkID []byte) (err error) {
+	var exists bool
+	if exists, err = block.ExistsBlockByHeight(st, common.GenesisBlockHeight); err != nil {
+		return
+	} else if exists {
+		if block.GetGenesis(st).Hash != item.Block.Hash {
+			return invalidArchive("different genesis block exists")
+		}
+		return
+	}
+
+	if len(item.Transactions) != 1 || len(item.Transactions[0].B.Operations) != 2 {
+		return invalidArchive("wrong genesis block")
+	}
+
+	var accounts []*block.BlockAccount
+	for _, op := range item.Transactions[0].B.Operations {
+		casted, ok := op.B.(operation.CreateAccount)
+		if !ok {
+			return invalidArchive("wrong genesis block")
+		}
+		accounts = append(accounts, block.NewBlockAccount(casted.Target, casted.Amount))
+	}
+
+	var bs storage.Backend
+	if bs, err = st.OpenBatch(); err != nil {
+		return
+	}
+
+	for _, ba := range accounts {
+		if err = ba.Save(bs); err != nil {
+			bs.Discard()
+			return
+		}
+	}
+
+	var blk *block.Block
+	if blk, err = block.MakeGenesisBlock(bs, *accounts[0], *accounts[1], networkID); err != nil {
+		bs.Discard()
+		return
+	}
+	if blk.Hash != item.Block.Hash {
+		bs.Discard()
+		return invalidArchive("wrong genesis block")
+	}
+
+	if err = bs.Commit(); err != nil {
+		bs.Discard()
+	}
+
+	return
+}
@@ -0,0 +1,138 @@
+package ballot
+
+import (
+	"fmt"
+	"sort"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// Certificate is the set of signed ACCEPT ballots, which agreed the block. With
+// the validators and the threshold, anyone can confirm the block was agreed by
+// the validators. the storage should support,
+//  * find by `Block`
+//
+// models
+//  * 'block'
+// 	- 'certificate-<Certificate.Block>': `Certificate`
+
+type Certificate struct {
+	Block   string   `json:"block"` // hash of block
+	Height  uint64   `json:"height"`
+	Ballots []Ballot `json:"ballots"`
+}
+
+func NewCertificate(blk block.Block, ballots []Ballot) Certificate {
+	sorted := make([]Ballot, len(ballots))
+	copy(sorted, ballots)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Source() < sorted[j].Source()
+	})
+
+	return Certificate{
+		Block:   blk.Hash,
+		Height:  blk.Height,
+		Ballots: sorted,
+	}
+}
+
+func (c Certificate) String() string {
+	return string(common.MustMarshalJSON(c))
+}
+
+// Verify checks the ballots of certificate agreed the given block; the ballots
+// must be the ACCEPT YES ballots for the block, and be signed by the different
+// validators over the threshold.
+func (c Certificate) Verify(blk block.Block, validators []string, threshold int, networkID []byte) error {
+	if c.Block != blk.Hash || c.Height != blk.Height {
+		return errors.InvalidCertificate.Clone().SetData("error", "different block")
+	}
+
+	isValidator := map[string]bool{}
+	for _, address := range validators {
+		isValidator[address] = true
+	}
+
+	signed := map[string]bool{}
+	for _, b := range c.Ballots {
+		if b.State() != StateACCEPT || b.Vote() != voting.YES {
+			return errors.InvalidCertificate.Clone().SetData("error", "not ACCEPT YES ballot")
+		}
+		if !isValidator[b.Source()] {
+			return errors.InvalidCertificate.Clone().SetData("error", "unknown validator")
+		}
+		if signed[b.Source()] {
+			return errors.InvalidCertificate.Clone().SetData("error", "duplicated validator")
+		}
+		if err := b.VerifySource(networkID); err != nil {
+			return errors.InvalidCertificate.Clone().SetData("error", err.Error())
+		}
+		if !c.isBallotForBlock(b, blk) {
+			return errors.InvalidCertificate.Clone().SetData("error", "ballot is not for the block")
+		}
+
+		signed[b.Source()] = true
+	}
+
+	if threshold < 1 || len(signed) < threshold {
+		return errors.InvalidCertificate.Clone().SetData("error", "not enough ballots")
+	}
+
+	return nil
+}
+
+// isBallotForBlock checks the ballot is voted for the block; the block is made
+// from the voting basis of the previous block.
+func (c Certificate) isBallotForBlock(b Ballot, blk block.Block) bool {
+	basis := b.VotingBasis()
+	if basis.Height+1 != blk.Height || basis.Round != blk.Round || basis.BlockHash != blk.PrevBlockHash {
+		return false
+	}
+	if b.Proposer() != blk.Proposer {
+		return false
+	}
+	if b.ProposerTransaction().GetHash() != blk.ProposerTransaction {
+		return false
+	}
+
+	if len(b.Transactions()) != len(blk.Transactions) {
+		return false
+	}
+	for i, hash := range b.Transactions() {
+		if blk.Transactions[i] != hash {
+			return false
+		}
+	}
+
+	return true
+}
+
+func GetCertificateKey(blockHash string) string {
+	return fmt.Sprintf("%s%s", common.BlockPrefixCertificate, blockHash)
+}
+
+func (c Certificate) Save(st storage.Backend) (err error) {
+	key := GetCertificateKey(c.Block)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	} else if exists {
+		return st.Set(key, c)
+	}
+
+	return st.New(key, c)
+}
+
+func ExistsCertificate(st storage.Backend, blockHash string) (bool, error) {
+	return st.Has(GetCertificateKey(blockHash))
+}
+
+func GetCertificate(st storage.Backend, blockHash string) (c Certificate, err error) {
+	err = st.Get(GetCertificateKey(blockHash), &c)
+	return
+}
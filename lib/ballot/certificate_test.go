@@ -0,0 +1,141 @@
+package ballot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestCertificateVerify(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	var kps []*keypair.Full
+	var validators []string
+	for i := 0; i < 4; i++ {
+		kp := keypair.Random()
+		kps = append(kps, kp)
+		validators = append(validators, kp.Address())
+	}
+
+	proposer := validators[0]
+	basis := voting.Basis{Round: 1, Height: 3, BlockHash: "prev-block", TotalTxs: 5, TotalOps: 5}
+	txs := []string{"tx0", "tx1"}
+
+	var ptx ProposerTransaction
+	ptx.H.Hash = ptx.B.MakeHashString()
+
+	newBallot := func(kp *keypair.Full, state State, vote voting.Hole) Ballot {
+		b := NewBallot(kp.Address(), proposer, basis, txs)
+		b.SetProposerTransaction(ptx)
+		b.SetVote(state, vote)
+		b.Sign(kp, conf.NetworkID)
+		return *b
+	}
+
+	next := basis
+	next.Height++
+	blk := *block.NewBlock(proposer, next, ptx.GetHash(), txs, common.NowISO8601())
+
+	var ballots []Ballot
+	for _, kp := range kps[:3] {
+		ballots = append(ballots, newBallot(kp, StateACCEPT, voting.YES))
+	}
+
+	with := func(b Ballot) []Ballot {
+		return []Ballot{ballots[0], ballots[1], b}
+	}
+
+	{ // valid
+		c := NewCertificate(blk, ballots)
+		require.Equal(t, blk.Hash, c.Block)
+		require.Equal(t, blk.Height, c.Height)
+		require.NoError(t, c.Verify(blk, validators, 3, conf.NetworkID))
+	}
+
+	{ // under threshold
+		c := NewCertificate(blk, ballots[:2])
+		err := c.Verify(blk, validators, 3, conf.NetworkID)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // unknown validator
+		c := NewCertificate(blk, with(newBallot(keypair.Random(), StateACCEPT, voting.YES)))
+		err := c.Verify(blk, validators, 3, conf.NetworkID)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // duplicated validator
+		c := NewCertificate(blk, with(ballots[0]))
+		err := c.Verify(blk, validators, 3, conf.NetworkID)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // not ACCEPT YES
+		c := NewCertificate(blk, with(newBallot(kps[3], StateSIGN, voting.YES)))
+		err := c.Verify(blk, validators, 3, conf.NetworkID)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // wrong signature
+		b := newBallot(kps[3], StateACCEPT, voting.YES)
+		b.H.Signature = ballots[0].H.Signature
+		c := NewCertificate(blk, with(b))
+		err := c.Verify(blk, validators, 3, conf.NetworkID)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // different block
+		other := *block.NewBlock(proposer, next, ptx.GetHash(), txs[:1], common.NowISO8601())
+		c := NewCertificate(other, ballots)
+		err := c.Verify(blk, validators, 3, conf.NetworkID)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+}
+
+func TestCertificateSave(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	basis := voting.Basis{Round: 0, Height: 1, BlockHash: "prev-block", TotalTxs: 1}
+
+	var ptx ProposerTransaction
+	ptx.H.Hash = ptx.B.MakeHashString()
+
+	b := NewBallot(kp.Address(), kp.Address(), basis, []string{})
+	b.SetProposerTransaction(ptx)
+	b.SetVote(StateACCEPT, voting.YES)
+	b.Sign(kp, conf.NetworkID)
+
+	next := basis
+	next.Height++
+	blk := *block.NewBlock(kp.Address(), next, ptx.GetHash(), []string{}, common.NowISO8601())
+
+	exists, err := ExistsCertificate(st, blk.Hash)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	c := NewCertificate(blk, []Ballot{*b})
+	require.NoError(t, c.Save(st))
+	// saving again overwrites
+	require.NoError(t, c.Save(st))
+
+	exists, err = ExistsCertificate(st, blk.Hash)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	fetched, err := GetCertificate(st, blk.Hash)
+	require.NoError(t, err)
+	require.Equal(t, c.Block, fetched.Block)
+	require.Equal(t, 1, len(fetched.Ballots))
+	require.Equal(t, b.GetHash(), fetched.Ballots[0].GetHash())
+	require.NoError(t, fetched.Verify(blk, []string{kp.Address()}, 1, conf.NetworkID))
+}
@@ -0,0 +1,120 @@
+package ballot
+
+import (
+	"fmt"
+	"sort"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// Evidence is the pair of conflicting ballots, which are signed by the same
+// validator, see `IsConflicting`. the storage should support,
+//  * find by `Hash`
+//  * get list by the height of `VotingBasis`
+//
+// models
+//  * 'height'
+// 	- 'evidence-<VotingBasis.Height>-<Evidence.Hash>': `Evidence`
+
+type Evidence struct {
+	Hash        string       `json:"hash"`
+	Source      string       `json:"source"`
+	VotingBasis voting.Basis `json:"voting_basis"`
+	State       State        `json:"state"`
+	Ballots     []Ballot     `json:"ballots"`
+	Detected    string       `json:"detected"`
+}
+
+// IsConflicting checks the ballots are signed by the same source at the same
+// voting basis and state, but have the different vote or proposal. The expired
+// ballot does not conflict, because the validator can give up the voting by
+// timeout after it voted.
+func IsConflicting(a, b Ballot) bool {
+	if a.Source() != b.Source() || a.State() != b.State() {
+		return false
+	}
+	if a.VotingBasis().Index() != b.VotingBasis().Index() {
+		return false
+	}
+	if a.Vote() == voting.EXP || b.Vote() == voting.EXP {
+		return false
+	}
+
+	if a.Vote() != b.Vote() || a.Proposer() != b.Proposer() {
+		return true
+	}
+
+	if len(a.Transactions()) != len(b.Transactions()) {
+		return true
+	}
+	for i, hash := range a.Transactions() {
+		if b.Transactions()[i] != hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+func NewEvidence(a, b Ballot) Evidence {
+	ballots := []Ballot{a, b}
+	sort.Slice(ballots, func(i, j int) bool {
+		return ballots[i].GetHash() < ballots[j].GetHash()
+	})
+
+	return Evidence{
+		Hash:        common.MustMakeObjectHashString([]string{ballots[0].GetHash(), ballots[1].GetHash()}),
+		Source:      a.Source(),
+		VotingBasis: a.VotingBasis(),
+		State:       a.State(),
+		Ballots:     ballots,
+		Detected:    common.NowISO8601(),
+	}
+}
+
+func (e Evidence) String() string {
+	return string(common.MustMarshalJSON(e))
+}
+
+func GetEvidenceKey(height uint64, hash string) string {
+	return fmt.Sprintf("%s%020d-%s", common.BallotEvidencePrefixHeight, height, hash)
+}
+
+// Save stores `Evidence`; the same evidence is stored only once, so `Save`
+// returns `false` if it already exists.
+func (e Evidence) Save(st storage.Backend) (saved bool, err error) {
+	key := GetEvidenceKey(e.VotingBasis.Height, e.Hash)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil || exists {
+		return
+	}
+
+	if err = st.New(key, e); err != nil {
+		return
+	}
+
+	return true, nil
+}
+
+func GetEvidences(st storage.Backend, options storage.ListOptions) (
+	func() (Evidence, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(common.BallotEvidencePrefixHeight, options)
+
+	return (func() (Evidence, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return Evidence{}, false, item.Key
+			}
+
+			var e Evidence
+			common.MustUnmarshalJSON(item.Value, &e)
+			return e, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
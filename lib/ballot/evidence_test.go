@@ -0,0 +1,95 @@
+package ballot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestIsConflicting(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	proposer := keypair.Random().Address()
+	basis := voting.Basis{Round: 0, Height: 1, BlockHash: "hahaha", TotalTxs: 1}
+
+	newBallot := func(proposer string, basis voting.Basis, txs []string, state State, vote voting.Hole) Ballot {
+		b := NewBallot(kp.Address(), proposer, basis, txs)
+		b.SetVote(state, vote)
+		b.Sign(kp, conf.NetworkID)
+		return *b
+	}
+
+	a := newBallot(proposer, basis, []string{"tx0"}, StateSIGN, voting.YES)
+
+	// same vote
+	require.False(t, IsConflicting(a, newBallot(proposer, basis, []string{"tx0"}, StateSIGN, voting.YES)))
+
+	// different vote
+	require.True(t, IsConflicting(a, newBallot(proposer, basis, []string{"tx0"}, StateSIGN, voting.NO)))
+
+	// different proposer
+	require.True(t, IsConflicting(a, newBallot(keypair.Random().Address(), basis, []string{"tx0"}, StateSIGN, voting.YES)))
+
+	// different transactions
+	require.True(t, IsConflicting(a, newBallot(proposer, basis, []string{"tx1"}, StateSIGN, voting.YES)))
+
+	// expired
+	require.False(t, IsConflicting(a, newBallot(proposer, basis, []string{"tx0"}, StateSIGN, voting.EXP)))
+
+	// different state
+	require.False(t, IsConflicting(a, newBallot(proposer, basis, []string{"tx0"}, StateACCEPT, voting.NO)))
+
+	// different round
+	nextRound := basis
+	nextRound.Round++
+	require.False(t, IsConflicting(a, newBallot(proposer, nextRound, []string{"tx1"}, StateSIGN, voting.YES)))
+
+	// different source
+	other := NewBallot(keypair.Random().Address(), proposer, basis, []string{"tx0"})
+	other.SetVote(StateSIGN, voting.NO)
+	require.False(t, IsConflicting(a, *other))
+}
+
+func TestEvidenceSave(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	basis := voting.Basis{Round: 0, Height: 1, BlockHash: "hahaha", TotalTxs: 1}
+
+	a := NewBallot(kp.Address(), kp.Address(), basis, []string{"tx0"})
+	a.SetVote(StateSIGN, voting.YES)
+	a.Sign(kp, conf.NetworkID)
+	b := NewBallot(kp.Address(), kp.Address(), basis, []string{"tx0"})
+	b.SetVote(StateSIGN, voting.NO)
+	b.Sign(kp, conf.NetworkID)
+
+	e := NewEvidence(*a, *b)
+	require.Equal(t, e.Hash, NewEvidence(*b, *a).Hash)
+
+	saved, err := e.Save(st)
+	require.NoError(t, err)
+	require.True(t, saved)
+
+	saved, err = e.Save(st)
+	require.NoError(t, err)
+	require.False(t, saved)
+
+	iterFunc, closeFunc := GetEvidences(st, nil)
+	defer closeFunc()
+
+	fetched, hasNext, _ := iterFunc()
+	require.True(t, hasNext)
+	require.Equal(t, e.Hash, fetched.Hash)
+	require.Equal(t, kp.Address(), fetched.Source)
+	require.Equal(t, 2, len(fetched.Ballots))
+
+	_, hasNext, _ = iterFunc()
+	require.False(t, hasNext)
+}
@@ -5,6 +5,7 @@ import (
 
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
 )
 
 // BlockAccount is account model in block. the storage should support,
@@ -17,6 +18,7 @@ import (
 // 	- 'ba-address-<BlockAccount.Address>': `BlockAccount`
 //  * 'created'
 // 	- 'ba-created-<sequential uuid1>': `BlockAccouna.Address`
+// 	- 'ba-created-address-<BlockAccount.Address>': 'ba-created-<sequential uuid1>'
 
 type BlockAccount struct {
 	Address    string        `json:"address"`
@@ -26,6 +28,12 @@ type BlockAccount struct {
 	Linked   string      `json:"linked"`
 	CodeHash []byte      `json:"code_hash"`
 	RootHash common.Hash `json:"root_hash"`
+	// Signers and Thresholds are set by `ManageSigners` operation; if
+	// `Signers` is empty, only the address itself can sign.
+	Signers    []operation.Signer   `json:"signers,omitempty"`
+	Thresholds operation.Thresholds `json:"thresholds"`
+	// DataEntries is the number of `BlockAccountData` of account.
+	DataEntries uint64 `json:"data_entries"`
 }
 
 func NewBlockAccount(address string, balance common.Amount) *BlockAccount {
@@ -45,6 +53,50 @@ func (b *BlockAccount) IsFrozen() bool {
 	return b.Linked != ""
 }
 
+// HasSigners returns true if the account is controlled by the signers of
+// `ManageSigners` operation.
+func (b *BlockAccount) HasSigners() bool {
+	return len(b.Signers) > 0
+}
+
+// SignerWeight returns the weight of signer. Without signers, the address of
+// account has weight 1.
+func (b *BlockAccount) SignerWeight(address string) uint32 {
+	if !b.HasSigners() {
+		if address == b.Address {
+			return 1
+		}
+		return 0
+	}
+
+	for _, s := range b.Signers {
+		if s.Address == address {
+			return s.Weight
+		}
+	}
+
+	return 0
+}
+
+// RequiredThreshold returns the minimum sum of signer weights to authorize the
+// given operations. At least one valid signature is always required.
+func (b *BlockAccount) RequiredThreshold(ops ...operation.Operation) uint32 {
+	var threshold uint32 = 1
+	for _, op := range ops {
+		if t := b.Thresholds.RequiredThreshold(op.H.Type); t > threshold {
+			threshold = t
+		}
+	}
+
+	return threshold
+}
+
+// DataReserve returns the amount of balance, which is locked by the data
+// entries of account.
+func (b *BlockAccount) DataReserve() (common.Amount, error) {
+	return common.BaseReserve.MultUint64(b.DataEntries)
+}
+
 func (b *BlockAccount) IncreaseSequenceID() {
 	b.SequenceID += 1
 }
@@ -53,7 +105,7 @@ func (b *BlockAccount) String() string {
 	return string(common.MustMarshalJSON(b))
 }
 
-func (b *BlockAccount) Save(st *storage.LevelDBBackend) (err error) {
+func (b *BlockAccount) Save(st storage.Backend) (err error) {
 	key := GetBlockAccountKey(b.Address)
 
 	var exists bool
@@ -68,6 +120,9 @@ func (b *BlockAccount) Save(st *storage.LevelDBBackend) (err error) {
 		err = st.New(key, b)
 		createdKey := GetBlockAccountCreatedKey(common.GetUniqueIDFromUUID())
 		err = st.New(createdKey, b.Address)
+		if err == nil {
+			err = st.New(GetBlockAccountCreatedAddressKey(b.Address), createdKey)
+		}
 	}
 	if err != nil {
 		return err
@@ -83,6 +138,26 @@ func (b *BlockAccount) Save(st *storage.LevelDBBackend) (err error) {
 	return
 }
 
+// Remove removes the `BlockAccount` and it's 'created' index. The history of
+// account like `BlockAccountSequenceID` is kept.
+func (b *BlockAccount) Remove(st storage.Backend) (err error) {
+	// the 'created-address' index of the account, which was saved before
+	// the index, is made by `Migrate`.
+	var createdKey string
+	if err = st.Get(GetBlockAccountCreatedAddressKey(b.Address), &createdKey); err != nil {
+		return
+	}
+
+	if err = st.Remove(GetBlockAccountKey(b.Address)); err != nil {
+		return
+	}
+	if err = st.Remove(createdKey); err != nil {
+		return
+	}
+
+	return st.Remove(GetBlockAccountCreatedAddressKey(b.Address))
+}
+
 func GetBlockAccountKey(address string) string {
 	return fmt.Sprintf("%s%s", common.BlockAccountPrefixAddress, address)
 }
@@ -91,11 +166,15 @@ func GetBlockAccountCreatedKey(created string) string {
 	return fmt.Sprintf("%s%s", common.BlockAccountPrefixCreated, created)
 }
 
-func ExistsBlockAccount(st *storage.LevelDBBackend, address string) (exists bool, err error) {
+func GetBlockAccountCreatedAddressKey(address string) string {
+	return fmt.Sprintf("%s%s", common.BlockAccountPrefixCreatedAddress, address)
+}
+
+func ExistsBlockAccount(st storage.Backend, address string) (exists bool, err error) {
 	return st.Has(GetBlockAccountKey(address))
 }
 
-func GetBlockAccount(st *storage.LevelDBBackend, address string) (b *BlockAccount, err error) {
+func GetBlockAccount(st storage.Backend, address string) (b *BlockAccount, err error) {
 	if err = st.Get(GetBlockAccountKey(address), &b); err != nil {
 		return
 	}
@@ -103,7 +182,7 @@ func GetBlockAccount(st *storage.LevelDBBackend, address string) (b *BlockAccoun
 	return
 }
 
-func GetBlockAccountAddressesByCreated(st *storage.LevelDBBackend, options storage.ListOptions) (func() (string, bool, []byte), func()) {
+func GetBlockAccountAddressesByCreated(st storage.Backend, options storage.ListOptions) (func() (string, bool, []byte), func()) {
 	iterFunc, closeFunc := st.GetIterator(common.BlockAccountPrefixCreated, options)
 
 	return (func() (string, bool, []byte) {
@@ -120,7 +199,7 @@ func GetBlockAccountAddressesByCreated(st *storage.LevelDBBackend, options stora
 		})
 }
 
-func GetBlockAccountsByCreated(st *storage.LevelDBBackend, options storage.ListOptions) (func() (*BlockAccount, bool, []byte), func()) {
+func GetBlockAccountsByCreated(st storage.Backend, options storage.ListOptions) (func() (*BlockAccount, bool, []byte), func()) {
 	iterFunc, closeFunc := GetBlockAccountAddressesByCreated(st, options)
 
 	return (func() (*BlockAccount, bool, []byte) {
@@ -143,7 +222,7 @@ func GetBlockAccountsByCreated(st *storage.LevelDBBackend, options storage.ListO
 }
 
 func LoadBlockAccountsInsideIterator(
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	iterFunc func() (storage.IterItem, bool),
 	closeFunc func(),
 ) (
@@ -231,7 +310,7 @@ func (b *BlockAccountSequenceID) String() string {
 	return string(common.MustMarshalJSON(b))
 }
 
-func (b *BlockAccountSequenceID) Save(st *storage.LevelDBBackend) (err error) {
+func (b *BlockAccountSequenceID) Save(st storage.Backend) (err error) {
 	key := GetBlockAccountSequenceIDKey(b.Address, b.SequenceID)
 
 	var exists bool
@@ -255,7 +334,7 @@ func (b *BlockAccountSequenceID) Save(st *storage.LevelDBBackend) (err error) {
 	return
 }
 
-func GetBlockAccountSequenceID(st *storage.LevelDBBackend, address string, sequenceID uint64) (b BlockAccountSequenceID, err error) {
+func GetBlockAccountSequenceID(st storage.Backend, address string, sequenceID uint64) (b BlockAccountSequenceID, err error) {
 	if err = st.Get(GetBlockAccountSequenceIDKey(address, sequenceID), &b); err != nil {
 		return
 	}
@@ -263,7 +342,7 @@ func GetBlockAccountSequenceID(st *storage.LevelDBBackend, address string, seque
 	return
 }
 
-func GetBlockAccountSequenceIDByAddress(st *storage.LevelDBBackend, address string, options storage.ListOptions) (func() (BlockAccountSequenceID, bool, []byte), func()) {
+func GetBlockAccountSequenceIDByAddress(st storage.Backend, address string, options storage.ListOptions) (func() (BlockAccountSequenceID, bool, []byte), func()) {
 	prefix := GetBlockAccountSequenceIDByAddressKeyPrefix(address)
 	iterFunc, closeFunc := st.GetIterator(prefix, options)
 
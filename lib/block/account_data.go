@@ -0,0 +1,96 @@
+package block
+
+import (
+	"fmt"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// BlockAccountData is the data entry of account, which is set by `ManageData`
+// operation. the storage should support,
+//  * find by `Address` and `Key`
+//  * get list by `Address` and `Key` order
+//
+// models
+//  * 'address' and 'key'
+// 	- 'bad-<BlockAccountData.Address>-<BlockAccountData.Key>': `BlockAccountData`
+
+type BlockAccountData struct {
+	Address string `json:"address"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+}
+
+func NewBlockAccountData(address, key, value string) *BlockAccountData {
+	return &BlockAccountData{
+		Address: address,
+		Key:     key,
+		Value:   value,
+	}
+}
+
+func (d *BlockAccountData) String() string {
+	return string(common.MustMarshalJSON(d))
+}
+
+func (d *BlockAccountData) Save(st storage.Backend) (err error) {
+	key := GetBlockAccountDataKey(d.Address, d.Key)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		err = st.Set(key, d)
+	} else {
+		err = st.New(key, d)
+	}
+
+	return
+}
+
+func GetBlockAccountDataKey(address, key string) string {
+	return fmt.Sprintf("%s%s", GetBlockAccountDataKeyPrefix(address), key)
+}
+
+func GetBlockAccountDataKeyPrefix(address string) string {
+	return fmt.Sprintf("%s%s-", common.BlockAccountDataPrefixAddress, address)
+}
+
+func ExistsBlockAccountData(st storage.Backend, address, key string) (bool, error) {
+	return st.Has(GetBlockAccountDataKey(address, key))
+}
+
+func GetBlockAccountData(st storage.Backend, address, key string) (d *BlockAccountData, err error) {
+	if err = st.Get(GetBlockAccountDataKey(address, key), &d); err != nil {
+		return
+	}
+
+	return
+}
+
+func RemoveBlockAccountData(st storage.Backend, address, key string) error {
+	return st.Remove(GetBlockAccountDataKey(address, key))
+}
+
+func GetBlockAccountDataByAddress(st storage.Backend, address string, options storage.ListOptions) (
+	func() (*BlockAccountData, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(GetBlockAccountDataKeyPrefix(address), options)
+
+	return (func() (*BlockAccountData, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return &BlockAccountData{}, false, item.Key
+			}
+
+			var d BlockAccountData
+			common.MustUnmarshalJSON(item.Value, &d)
+			return &d, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
@@ -0,0 +1,65 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+)
+
+func TestBlockAccountDataSaveAndGet(t *testing.T) {
+	st := storage.NewTestStorage()
+
+	address := keypair.Random().Address()
+	d := NewBlockAccountData(address, "kyc", "showme")
+	require.NoError(t, d.Save(st))
+
+	exists, err := ExistsBlockAccountData(st, address, "kyc")
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	fetched, err := GetBlockAccountData(st, address, "kyc")
+	require.NoError(t, err)
+	require.Equal(t, d, fetched)
+
+	// update
+	d.Value = "findme"
+	require.NoError(t, d.Save(st))
+	fetched, err = GetBlockAccountData(st, address, "kyc")
+	require.NoError(t, err)
+	require.Equal(t, "findme", fetched.Value)
+
+	require.NoError(t, RemoveBlockAccountData(st, address, "kyc"))
+	exists, err = ExistsBlockAccountData(st, address, "kyc")
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestBlockAccountDataByAddress(t *testing.T) {
+	st := storage.NewTestStorage()
+
+	address := keypair.Random().Address()
+	another := keypair.Random().Address()
+
+	keys := []string{"a", "b", "c"}
+	for _, key := range keys {
+		NewBlockAccountData(address, key, "showme").Save(st)
+		NewBlockAccountData(another, key, "showme").Save(st)
+	}
+
+	var fetched []string
+	iterFunc, closeFunc := GetBlockAccountDataByAddress(st, address, nil)
+	for {
+		d, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		require.Equal(t, address, d.Address)
+		fetched = append(fetched, d.Key)
+	}
+	closeFunc()
+
+	require.Equal(t, keys, fetched)
+}
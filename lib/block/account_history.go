@@ -0,0 +1,185 @@
+package block
+
+import (
+	"fmt"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// BlockAccountHistory is the balance and sequence ID of account after the
+// block of `Height` is finished; it is saved only in the block, which changes
+// the account. When the account is merged, the history is saved with
+// `Merged`. the storage should support,
+//   - get the latest one until the given height
+//
+// models
+//   - 'address' and 'height'
+//   - 'bah-<BlockAccountHistory.Address>-<BlockAccountHistory.Height>': `BlockAccountHistory`
+type BlockAccountHistory struct {
+	Address    string        `json:"address"`
+	Height     uint64        `json:"height"`
+	Balance    common.Amount `json:"balance"`
+	SequenceID uint64        `json:"sequence_id"`
+	Merged     bool          `json:"merged"`
+}
+
+func NewBlockAccountHistory(ba *BlockAccount, height uint64) *BlockAccountHistory {
+	return &BlockAccountHistory{
+		Address:    ba.Address,
+		Height:     height,
+		Balance:    ba.Balance,
+		SequenceID: ba.SequenceID,
+	}
+}
+
+func GetBlockAccountHistoryKey(address string, height uint64) string {
+	return fmt.Sprintf("%s%s-%020d", common.BlockAccountHistoryPrefixAddress, address, height)
+}
+
+func GetBlockAccountHistoryKeyPrefix(address string) string {
+	return fmt.Sprintf("%s%s-", common.BlockAccountHistoryPrefixAddress, address)
+}
+
+func (h *BlockAccountHistory) String() string {
+	return string(common.MustMarshalJSON(h))
+}
+
+func (h *BlockAccountHistory) Save(st storage.Backend) (err error) {
+	key := GetBlockAccountHistoryKey(h.Address, h.Height)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		err = st.Set(key, h)
+	} else {
+		err = st.New(key, h)
+	}
+
+	return
+}
+
+// SaveBlockAccountHistories saves the `BlockAccountHistory` of the given
+// accounts at the height. The account, which does not exist, is merged, so
+// the history is saved with `Merged` if it has the previous history.
+func SaveBlockAccountHistories(st storage.Backend, height uint64, addresses []string) (err error) {
+	for _, address := range addresses {
+		var exists bool
+		if exists, err = ExistsBlockAccount(st, address); err != nil {
+			return
+		} else if !exists {
+			if err = saveMergedBlockAccountHistory(st, address, height); err != nil {
+				return
+			}
+			continue
+		}
+
+		var ba *BlockAccount
+		if ba, err = GetBlockAccount(st, address); err != nil {
+			return
+		}
+		if err = NewBlockAccountHistory(ba, height).Save(st); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func saveMergedBlockAccountHistory(st storage.Backend, address string, height uint64) (err error) {
+	var previous BlockAccountHistory
+	if previous, err = getBlockAccountHistoryAt(st, address, height); err != nil {
+		if err == errors.BlockAccountDoesNotExists {
+			err = nil
+		}
+		return
+	} else if previous.Merged {
+		return
+	}
+
+	h := &BlockAccountHistory{Address: address, Height: height, Merged: true}
+	return h.Save(st)
+}
+
+func getBlockAccountHistoryStartKey() string {
+	return fmt.Sprintf("%s-block-account-history-start", common.InternalPrefix)
+}
+
+// SaveBlockAccountHistoryStart saves the height, from which
+// `BlockAccountHistory` is available. The storage, which was made before
+// `BlockAccountHistory` or from the snapshot, does not have the history of the
+// previous blocks.
+func SaveBlockAccountHistoryStart(st storage.Backend, height uint64) (err error) {
+	key := getBlockAccountHistoryStartKey()
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	} else if exists {
+		return st.Set(key, height)
+	}
+
+	return st.New(key, height)
+}
+
+// GetBlockAccountHistoryStart returns the height, from which
+// `BlockAccountHistory` is available; without it, the history is available
+// from the genesis.
+func GetBlockAccountHistoryStart(st storage.Backend) (height uint64, err error) {
+	var exists bool
+	if exists, err = st.Has(getBlockAccountHistoryStartKey()); err != nil {
+		return
+	} else if !exists {
+		return common.GenesisBlockHeight, nil
+	}
+
+	err = st.Get(getBlockAccountHistoryStartKey(), &height)
+	return
+}
+
+// GetBlockAccountHistoryAt returns the `BlockAccountHistory` of the latest
+// block, which changed the account until the given height. The merged
+// account does not exist and the history before
+// `GetBlockAccountHistoryStart` is not available.
+func GetBlockAccountHistoryAt(st storage.Backend, address string, height uint64) (h BlockAccountHistory, err error) {
+	var start uint64
+	if start, err = GetBlockAccountHistoryStart(st); err != nil {
+		return
+	} else if height < start {
+		err = errors.BlockAccountHistoryNotAvailable
+		return
+	}
+
+	if h, err = getBlockAccountHistoryAt(st, address, height); err != nil {
+		return
+	} else if h.Merged {
+		err = errors.BlockAccountDoesNotExists
+	}
+
+	return
+}
+
+func getBlockAccountHistoryAt(st storage.Backend, address string, height uint64) (h BlockAccountHistory, err error) {
+	// the cursor is excluded in the reverse order
+	options := storage.NewDefaultListOptions(
+		true,
+		[]byte(GetBlockAccountHistoryKey(address, height+1)),
+		1,
+	)
+
+	iterFunc, closeFunc := st.GetIterator(GetBlockAccountHistoryKeyPrefix(address), options)
+	defer closeFunc()
+
+	item, hasNext := iterFunc()
+	if !hasNext {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	common.MustUnmarshalJSON(item.Value, &h)
+	return
+}
@@ -0,0 +1,133 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+)
+
+func TestBlockAccountHistoryAt(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	ba := TestMakeBlockAccount()
+	other := TestMakeBlockAccount()
+
+	for _, height := range []uint64{9, 2, 5} {
+		ba.Balance++
+		ba.SequenceID = height
+		require.NoError(t, NewBlockAccountHistory(ba, height).Save(st))
+	}
+	require.NoError(t, NewBlockAccountHistory(other, 3).Save(st))
+
+	_, err := GetBlockAccountHistoryAt(st, ba.Address, 1)
+	require.Equal(t, errors.BlockAccountDoesNotExists, err)
+
+	for height, expected := range map[uint64]uint64{2: 2, 4: 2, 5: 5, 8: 5, 9: 9, 100: 9} {
+		h, err := GetBlockAccountHistoryAt(st, ba.Address, height)
+		require.NoError(t, err)
+		require.Equal(t, ba.Address, h.Address)
+		require.Equal(t, expected, h.Height)
+		require.Equal(t, expected, h.SequenceID)
+	}
+
+	h, err := GetBlockAccountHistoryAt(st, other.Address, 100)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), h.Height)
+	require.Equal(t, other.Balance, h.Balance)
+
+	// saved again in same height
+	ba.Balance++
+	require.NoError(t, NewBlockAccountHistory(ba, 9).Save(st))
+	h, err = GetBlockAccountHistoryAt(st, ba.Address, 9)
+	require.NoError(t, err)
+	require.Equal(t, ba.Balance, h.Balance)
+}
+
+func TestBlockAccountHistoryMerged(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	ba := TestMakeBlockAccount()
+	ba.MustSave(st)
+	require.NoError(t, SaveBlockAccountHistories(st, 2, []string{ba.Address}))
+
+	// the account, which does not have history, is skipped
+	other := TestMakeBlockAccount()
+	require.NoError(t, SaveBlockAccountHistories(st, 3, []string{other.Address}))
+	_, err := GetBlockAccountHistoryAt(st, other.Address, 3)
+	require.Equal(t, errors.BlockAccountDoesNotExists, err)
+
+	require.NoError(t, ba.Remove(st))
+	require.NoError(t, SaveBlockAccountHistories(st, 5, []string{ba.Address}))
+
+	h, err := GetBlockAccountHistoryAt(st, ba.Address, 4)
+	require.NoError(t, err)
+	require.Equal(t, ba.Balance, h.Balance)
+
+	for _, height := range []uint64{5, 100} {
+		_, err := GetBlockAccountHistoryAt(st, ba.Address, height)
+		require.Equal(t, errors.BlockAccountDoesNotExists, err)
+	}
+
+	// the merged account created again
+	ba.MustSave(st)
+	require.NoError(t, SaveBlockAccountHistories(st, 7, []string{ba.Address}))
+	h, err = GetBlockAccountHistoryAt(st, ba.Address, 7)
+	require.NoError(t, err)
+	require.False(t, h.Merged)
+}
+
+func TestBlockAccountHistoryStart(t *testing.T) {
+	st := InitTestBlockchain()
+	defer st.Close()
+
+	genesis := GetGenesis(st)
+	for i := 0; i < 3; i++ {
+		blk := TestMakeNewBlockWithPrevBlock(GetLatestBlock(st), []string{})
+		require.NoError(t, blk.Save(st))
+	}
+	latest := GetLatestBlock(st)
+
+	ba := TestMakeBlockAccount()
+	ba.MustSave(st)
+
+	// the history is available from the genesis
+	h, err := GetBlockAccountHistoryAt(st, GenesisKP.Address(), latest.Height)
+	require.NoError(t, err)
+	require.Equal(t, genesis.Height, h.Height)
+
+	// the storage was made before `BlockAccountHistory`
+	var keys []string
+	iterFunc, closeFunc := st.GetIterator(common.BlockAccountHistoryPrefixAddress, nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+		keys = append(keys, string(item.Key))
+	}
+	closeFunc()
+	for _, key := range keys {
+		require.NoError(t, st.Remove(key))
+	}
+
+	require.NoError(t, Migrate(st))
+
+	start, err := GetBlockAccountHistoryStart(st)
+	require.NoError(t, err)
+	require.Equal(t, latest.Height, start)
+
+	for _, address := range []string{GenesisKP.Address(), ba.Address} {
+		h, err := GetBlockAccountHistoryAt(st, address, latest.Height)
+		require.NoError(t, err)
+		require.Equal(t, latest.Height, h.Height)
+
+		_, err = GetBlockAccountHistoryAt(st, address, latest.Height-1)
+		require.Equal(t, errors.BlockAccountHistoryNotAvailable, err)
+	}
+}
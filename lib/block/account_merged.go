@@ -0,0 +1,88 @@
+package block
+
+import (
+	"fmt"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// BlockAccountMerged is the account, which was merged by `AccountMerge`
+// operation; the account, which is created again at the same address, starts
+// from `SequenceID`, so the transactions of the merged account can not be
+// replayed. the storage should support,
+//  * find by `Address`
+//  * get list by `Address` order
+//
+// models
+//  * 'address'
+// 	- 'bam-<BlockAccountMerged.Address>': `BlockAccountMerged`
+
+type BlockAccountMerged struct {
+	Address    string `json:"address"`
+	SequenceID uint64 `json:"sequence_id"` // next sequence ID of the merged account
+}
+
+func NewBlockAccountMerged(ba *BlockAccount) *BlockAccountMerged {
+	return &BlockAccountMerged{
+		Address:    ba.Address,
+		SequenceID: ba.SequenceID,
+	}
+}
+
+func (m *BlockAccountMerged) String() string {
+	return string(common.MustMarshalJSON(m))
+}
+
+func (m *BlockAccountMerged) Save(st storage.Backend) (err error) {
+	key := GetBlockAccountMergedKey(m.Address)
+
+	var exists bool
+	if exists, err = st.Has(key); err != nil {
+		return
+	}
+
+	if exists {
+		err = st.Set(key, m)
+	} else {
+		err = st.New(key, m)
+	}
+
+	return
+}
+
+func GetBlockAccountMergedKey(address string) string {
+	return fmt.Sprintf("%s%s", common.BlockAccountMergedPrefixAddress, address)
+}
+
+func ExistsBlockAccountMerged(st storage.Backend, address string) (bool, error) {
+	return st.Has(GetBlockAccountMergedKey(address))
+}
+
+func GetBlockAccountMerged(st storage.Backend, address string) (m *BlockAccountMerged, err error) {
+	if err = st.Get(GetBlockAccountMergedKey(address), &m); err != nil {
+		return
+	}
+
+	return
+}
+
+func GetBlockAccountsMerged(st storage.Backend, options storage.ListOptions) (
+	func() (*BlockAccountMerged, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(common.BlockAccountMergedPrefixAddress, options)
+
+	return (func() (*BlockAccountMerged, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return &BlockAccountMerged{}, false, item.Key
+			}
+
+			var m BlockAccountMerged
+			common.MustUnmarshalJSON(item.Value, &m)
+			return &m, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
@@ -129,3 +129,50 @@ func TestBlockAccountSaveBlockAccountSequenceIDs(t *testing.T) {
 		require.Equal(t, b.SequenceID, fetched[i].SequenceID)
 	}
 }
+
+func TestBlockAccountRemove(t *testing.T) {
+	st := storage.NewTestStorage()
+
+	var accounts []*BlockAccount
+	for i := 0; i < 3; i++ {
+		b := TestMakeBlockAccount()
+		b.MustSave(st)
+		accounts = append(accounts, b)
+	}
+
+	getAddresses := func() (addresses []string) {
+		iterFunc, closeFunc := GetBlockAccountAddressesByCreated(st, nil)
+		for {
+			address, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			addresses = append(addresses, address)
+		}
+		closeFunc()
+		return
+	}
+
+	require.NoError(t, accounts[1].Remove(st))
+
+	exists, err := ExistsBlockAccount(st, accounts[1].Address)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.Equal(t, []string{accounts[0].Address, accounts[2].Address}, getAddresses())
+
+	{ // without 'created-address' index, `Migrate` makes it
+		require.NoError(t, st.Remove(GetBlockAccountCreatedAddressKey(accounts[2].Address)))
+		require.Error(t, accounts[2].Remove(st))
+
+		require.NoError(t, Migrate(st))
+		require.NoError(t, accounts[2].Remove(st))
+		require.Equal(t, []string{accounts[0].Address}, getAddresses())
+
+		// the done migration does not run again
+		require.NoError(t, st.Remove(GetBlockAccountCreatedAddressKey(accounts[0].Address)))
+		require.NoError(t, Migrate(st))
+		exists, err := st.Has(GetBlockAccountCreatedAddressKey(accounts[0].Address))
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+}
@@ -0,0 +1,54 @@
+package block
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// GetBaseFee returns the minimum fee per operation for the next block of the
+// latest block, by the fullness of the recent `common.BaseFeeBlocks` blocks;
+// see `common.CalculateBaseFee`. The operations are counted by `TotalOps` of
+// blocks, so the operations of `ProposerTransaction` are also counted.
+// Without blocks, it is `common.BaseFee`.
+func GetBaseFee(st storage.Backend) (fee common.Amount, err error) {
+	iterFunc, closeFunc := GetBlockHeadersByConfirmed(st, storage.NewDefaultListOptions(true, nil, 1))
+	latest, _, _ := iterFunc()
+	closeFunc()
+
+	if latest.Height < 1 {
+		return common.BaseFee, nil
+	}
+
+	return GetBaseFeeByBlock(st, latest)
+}
+
+// GetBaseFeeByBlock returns the minimum fee per operation for the next block
+// of the given block.
+func GetBaseFeeByBlock(st storage.Backend, latest Header) (fee common.Amount, err error) {
+	return getBaseFeeByBlock(
+		st,
+		latest,
+		common.BaseFeeBlocks,
+		common.DefaultOperationsInBallotLimit,
+		common.BaseFeeMaxMultiplier,
+	)
+}
+
+func getBaseFeeByBlock(st storage.Backend, latest Header, blocks, opsInBallotLimit int, maxMultiplier uint64) (fee common.Amount, err error) {
+	if blocks < 1 {
+		return common.BaseFee, nil
+	}
+
+	var previousTotalOps uint64
+	if latest.Height > uint64(blocks) {
+		var previous Header
+		if previous, err = GetBlockHeaderByHeight(st, latest.Height-uint64(blocks)); err != nil {
+			return
+		}
+		previousTotalOps = previous.TotalOps
+	}
+
+	fee = common.CalculateBaseFee(latest.TotalOps-previousTotalOps, blocks, opsInBallotLimit, maxMultiplier)
+
+	return
+}
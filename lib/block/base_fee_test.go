@@ -0,0 +1,78 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestGetBaseFee(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	getBaseFee := func(blocks int) (common.Amount, error) {
+		iterFunc, closeFunc := GetBlockHeadersByConfirmed(st, storage.NewDefaultListOptions(true, nil, 1))
+		latest, _, _ := iterFunc()
+		closeFunc()
+
+		return getBaseFeeByBlock(st, latest, blocks, 100, 10)
+	}
+
+	{ // without blocks
+		fee, err := GetBaseFee(st)
+		require.NoError(t, err)
+		require.Equal(t, common.BaseFee, fee)
+	}
+
+	kp := keypair.Random()
+	var prev Block
+	saveBlock := func(ops uint64) {
+		blk := NewBlock(
+			kp.Address(),
+			voting.Basis{
+				Height:    prev.Height + 1,
+				BlockHash: prev.Hash,
+				TotalOps:  prev.TotalOps + ops,
+			},
+			"",
+			nil,
+			common.NowISO8601(),
+		)
+		require.NoError(t, blk.Save(st))
+		prev = *blk
+	}
+
+	saveBlock(200) // genesis, out of the recent blocks
+	saveBlock(50)
+	saveBlock(50)
+	{ // half full
+		fee, err := getBaseFee(2)
+		require.NoError(t, err)
+		require.Equal(t, common.BaseFee, fee)
+	}
+
+	saveBlock(100)
+	{ // 150 of 200
+		fee, err := getBaseFee(2)
+		require.NoError(t, err)
+		require.Equal(t, common.BaseFee*11/2, fee)
+	}
+
+	saveBlock(100)
+	{ // full
+		fee, err := getBaseFee(2)
+		require.NoError(t, err)
+		require.Equal(t, common.BaseFee*10, fee)
+	}
+
+	{ // disabled
+		fee, err := getBaseFee(0)
+		require.NoError(t, err)
+		require.Equal(t, common.BaseFee, fee)
+	}
+}
@@ -33,23 +33,77 @@ func (bck Block) IsEmpty() bool {
 	return len(bck.Hash) < 1
 }
 
-// NewBlock creates new block; `ptx` represents the
-// `ProposerTransaction.GetHash()`.
+// NewBlock creates new block of the version of the height; `ptx` represents
+// the `ProposerTransaction.GetHash()`.
 func NewBlock(proposer string, basis voting.Basis, ptx string, transactions []string, proposedTime string) *Block {
+	return NewBlockWithVersion(GetBlockVersion(basis.Height), proposer, basis, ptx, transactions, proposedTime, "")
+}
+
+// NewBlockWithVersion creates new block of the given version and state root;
+// it is used to rebuild the block, which was created in the previous version.
+func NewBlockWithVersion(version uint32, proposer string, basis voting.Basis, ptx string, transactions []string, proposedTime string, stateRoot string) *Block {
 	b := &Block{
-		Header:              *NewBlockHeader(basis, getTransactionRoot(append([]string{ptx}, transactions...)), proposedTime),
+		Header:              *NewBlockHeader(basis, getTransactionRoot(version, append([]string{ptx}, transactions...)), proposedTime),
 		Transactions:        transactions,
 		ProposerTransaction: ptx,
 		Proposer:            proposer,
 		Round:               basis.Round,
 	}
+	b.Header.Version = version
+	if version >= BlockVersionV2 {
+		b.Header.StateRoot = stateRoot
+	}
 
-	b.Hash = base58.Encode(common.MustMakeObjectHash(b))
+	b.Hash = b.makeHash()
 	return b
 }
 
-func getTransactionRoot(txs []string) string {
-	return common.MustMakeObjectHashString(txs) // TODO make root
+// makeHash returns the hash of block; since `BlockVersionV2`, `StateRoot` is
+// also hashed.
+func (bck Block) makeHash() string {
+	if bck.Version < BlockVersionV2 {
+		return base58.Encode(common.MustMakeObjectHash(bck))
+	}
+
+	return base58.Encode(common.MustMakeObjectHash([]interface{}{bck, bck.StateRoot}))
+}
+
+// VerifyHash checks the hash of block is made from the block itself.
+func (bck Block) VerifyHash() bool {
+	b := bck
+	b.Hash = ""
+
+	return b.makeHash() == bck.Hash
+}
+
+// getTransactionRoot returns the root of transactions; since
+// `BlockVersionV1`, it is the root of Merkle tree.
+func getTransactionRoot(version uint32, txs []string) string {
+	if version < BlockVersionV1 {
+		return common.MustMakeObjectHashString(txs)
+	}
+
+	return common.MakeMerkleRoot(txs)
+}
+
+// TransactionProof returns the Merkle proof of the transaction, which can be
+// checked with `TransactionsRoot`. The leaves of Merkle tree are the proposer
+// transaction and the transactions in order.
+func (bck Block) TransactionProof(hash string) (proof common.MerkleProof, err error) {
+	if bck.Version < BlockVersionV1 {
+		err = errors.TransactionProofNotAvailable
+		return
+	}
+
+	leaves := append([]string{bck.ProposerTransaction}, bck.Transactions...)
+	index, found := common.InStringArray(leaves, hash)
+	if !found {
+		err = errors.TransactionNotFound
+		return
+	}
+
+	proof, _ = common.MakeMerkleProof(leaves, index)
+	return
 }
 
 func getBlockKey(hash string) string {
@@ -74,7 +128,7 @@ func (b Block) NewBlockKeyConfirmed() string {
 	)
 }
 
-func (b *Block) Save(st *storage.LevelDBBackend) (err error) {
+func (b *Block) Save(st storage.Backend) (err error) {
 	key := getBlockKey(b.Hash)
 	if b.Confirmed == "" {
 		b.Confirmed = common.NowISO8601()
@@ -102,7 +156,7 @@ func (b *Block) Save(st *storage.LevelDBBackend) (err error) {
 	return
 }
 
-func (b Block) PreviousBlock(st *storage.LevelDBBackend) (blk Block, err error) {
+func (b Block) PreviousBlock(st storage.Backend) (blk Block, err error) {
 	if b.Height == common.GenesisBlockHeight {
 		err = errors.StorageRecordDoesNotExist
 		return
@@ -111,32 +165,32 @@ func (b Block) PreviousBlock(st *storage.LevelDBBackend) (blk Block, err error)
 	return GetBlockByHeight(st, b.Height-1)
 }
 
-func (b Block) NextBlock(st *storage.LevelDBBackend) (Block, error) {
+func (b Block) NextBlock(st storage.Backend) (Block, error) {
 	return GetBlockByHeight(st, b.Height+1)
 }
 
-func GetBlock(st *storage.LevelDBBackend, hash string) (bt Block, err error) {
+func GetBlock(st storage.Backend, hash string) (bt Block, err error) {
 	err = st.Get(getBlockKey(hash), &bt)
 	return
 }
 
-func GetBlockHeader(st *storage.LevelDBBackend, hash string) (bt Header, err error) {
+func GetBlockHeader(st storage.Backend, hash string) (bt Header, err error) {
 	err = st.Get(getBlockKey(hash), &bt)
 	return
 }
 
-func ExistsBlock(st *storage.LevelDBBackend, hash string) (exists bool, err error) {
+func ExistsBlock(st storage.Backend, hash string) (exists bool, err error) {
 	exists, err = st.Has(getBlockKey(hash))
 	return
 }
 
-func ExistsBlockByHeight(st *storage.LevelDBBackend, height uint64) (exists bool, err error) {
+func ExistsBlockByHeight(st storage.Backend, height uint64) (exists bool, err error) {
 	exists, err = st.Has(getBlockKeyPrefixHeight(height))
 	return
 }
 
 func LoadBlocksInsideIterator(
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	iterFunc func() (storage.IterItem, bool),
 	closeFunc func(),
 ) (
@@ -165,7 +219,7 @@ func LoadBlocksInsideIterator(
 }
 
 func LoadBlockHeadersInsideIterator(
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	iterFunc func() (storage.IterItem, bool),
 	closeFunc func(),
 ) (
@@ -193,7 +247,7 @@ func LoadBlockHeadersInsideIterator(
 		})
 }
 
-func GetBlocksByConfirmed(st *storage.LevelDBBackend, options storage.ListOptions) (
+func GetBlocksByConfirmed(st storage.Backend, options storage.ListOptions) (
 	func() (Block, bool, []byte),
 	func(),
 ) {
@@ -202,7 +256,7 @@ func GetBlocksByConfirmed(st *storage.LevelDBBackend, options storage.ListOption
 	return LoadBlocksInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockHeadersByConfirmed(st *storage.LevelDBBackend, options storage.ListOptions) (
+func GetBlockHeadersByConfirmed(st storage.Backend, options storage.ListOptions) (
 	func() (Header, bool, []byte),
 	func(),
 ) {
@@ -211,7 +265,7 @@ func GetBlockHeadersByConfirmed(st *storage.LevelDBBackend, options storage.List
 	return LoadBlockHeadersInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockByHeight(st *storage.LevelDBBackend, height uint64) (bt Block, err error) {
+func GetBlockByHeight(st storage.Backend, height uint64) (bt Block, err error) {
 	var hash string
 	if err = st.Get(getBlockKeyPrefixHeight(height), &hash); err != nil {
 		return
@@ -220,7 +274,7 @@ func GetBlockByHeight(st *storage.LevelDBBackend, height uint64) (bt Block, err
 	return GetBlock(st, hash)
 }
 
-func GetBlockHeaderByHeight(st *storage.LevelDBBackend, height uint64) (bt Header, err error) {
+func GetBlockHeaderByHeight(st storage.Backend, height uint64) (bt Header, err error) {
 	var hash string
 	if err = st.Get(getBlockKeyPrefixHeight(height), &hash); err != nil {
 		return
@@ -229,7 +283,7 @@ func GetBlockHeaderByHeight(st *storage.LevelDBBackend, height uint64) (bt Heade
 	return GetBlockHeader(st, hash)
 }
 
-func GetLatestBlock(st *storage.LevelDBBackend) Block {
+func GetLatestBlock(st storage.Backend) Block {
 	// get latest blocks
 	iterFunc, closeFunc := GetBlocksByConfirmed(st, storage.NewDefaultListOptions(true, nil, 1))
 	b, _, _ := iterFunc()
@@ -242,7 +296,7 @@ func GetLatestBlock(st *storage.LevelDBBackend) Block {
 	return b
 }
 
-func WalkBlocks(st *storage.LevelDBBackend, option *storage.WalkOption, walkFunc func(*Block, []byte) (bool, error)) error {
+func WalkBlocks(st storage.Backend, option *storage.WalkOption, walkFunc func(*Block, []byte) (bool, error)) error {
 	err := st.Walk(common.BlockPrefixHeight, option, func(key, value []byte) (bool, error) {
 		var hash string
 		if err := json.Unmarshal(value, &hash); err != nil {
@@ -10,6 +10,7 @@ import (
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/storage"
 	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
 
 	"github.com/stretchr/testify/require"
 )
@@ -287,3 +288,91 @@ func TestMakeGenesisBlockFindCommonAccount(t *testing.T) {
 		require.Equal(t, commonAccount.SequenceID, ac.SequenceID)
 	}
 }
+
+// TestMakeGenesisBlockHash checks the hash of genesis block is not changed by
+// the new block versions.
+func TestMakeGenesisBlockHash(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	genesisAccount := NewBlockAccount(keypair.Master("genesis").Address(), common.MaximumBalance)
+	commonAccount := NewBlockAccount(keypair.Master("common").Address(), 0)
+
+	bk, err := MakeGenesisBlock(st, *genesisAccount, *commonAccount, []byte("sebak-test-network"))
+	require.NoError(t, err)
+	require.Equal(t, BlockVersionV0, bk.Version)
+	require.Equal(t, "8Yy8LM2e2jkcZXDEQDXLMdh4mQQVAaZW9KrJrAchKSL3", bk.Hash)
+}
+
+func TestGetBlockVersion(t *testing.T) {
+	defer func(v1, v2 uint64) {
+		common.BlockVersionV1Height = v1
+		common.BlockVersionV2Height = v2
+	}(common.BlockVersionV1Height, common.BlockVersionV2Height)
+
+	common.BlockVersionV1Height = 10
+	common.BlockVersionV2Height = 20
+
+	require.Equal(t, BlockVersionV0, GetBlockVersion(common.GenesisBlockHeight))
+	require.Equal(t, BlockVersionV0, GetBlockVersion(9))
+	require.Equal(t, BlockVersionV1, GetBlockVersion(10))
+	require.Equal(t, BlockVersionV1, GetBlockVersion(19))
+	require.Equal(t, BlockVersionV2, GetBlockVersion(20))
+
+	// the genesis block is always `BlockVersionV0`
+	common.BlockVersionV1Height = 0
+	common.BlockVersionV2Height = 0
+	require.Equal(t, BlockVersionV0, GetBlockVersion(common.GenesisBlockHeight))
+	require.Equal(t, BlockVersionV2, GetBlockVersion(common.GenesisBlockHeight+1))
+
+	blk := NewBlock("proposer", voting.Basis{Height: 2}, "ptx", nil, common.NowISO8601())
+	require.Equal(t, BlockVersionV2, blk.Version)
+}
+
+func TestBlockTransactionProof(t *testing.T) {
+	defer SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	txs := []string{"tx0", "tx1", "tx2", "tx3"}
+	basis := voting.Basis{Height: 2, BlockHash: "prev-block", TotalTxs: 5, TotalOps: 5}
+
+	blk := NewBlock("proposer", basis, "ptx", txs, common.NowISO8601())
+	require.Equal(t, GetBlockVersion(basis.Height), blk.Version)
+	require.Equal(t, common.MakeMerkleRoot(append([]string{"ptx"}, txs...)), blk.TransactionsRoot)
+
+	for _, hash := range append([]string{"ptx"}, txs...) {
+		proof, err := blk.TransactionProof(hash)
+		require.NoError(t, err)
+		require.True(t, proof.Verify(blk.TransactionsRoot, hash))
+	}
+
+	_, err := blk.TransactionProof("tx4")
+	require.Equal(t, errors.TransactionNotFound, err)
+
+	// the block of previous version keeps the previous root
+	old := NewBlockWithVersion(BlockVersionV0, "proposer", basis, "ptx", txs, blk.ProposedTime, "")
+	require.Equal(t, BlockVersionV0, old.Version)
+	require.Equal(t, common.MustMakeObjectHashString(append([]string{"ptx"}, txs...)), old.TransactionsRoot)
+	require.NotEqual(t, blk.Hash, old.Hash)
+
+	_, err = old.TransactionProof("tx0")
+	require.Equal(t, errors.TransactionProofNotAvailable, err)
+}
+
+func TestBlockStateRoot(t *testing.T) {
+	basis := voting.Basis{Height: 2, BlockHash: "prev"}
+	txs := []string{"tx0", "tx1"}
+
+	blk := NewBlockWithVersion(BlockVersionV2, "proposer", basis, "ptx", txs, common.NowISO8601(), "root")
+	require.Equal(t, "root", blk.StateRoot)
+
+	// the state root is hashed
+	other := NewBlockWithVersion(BlockVersionV2, "proposer", basis, "ptx", txs, blk.ProposedTime, "other root")
+	require.NotEqual(t, blk.Hash, other.Hash)
+
+	// the block of previous version does not have the state root
+	old := NewBlockWithVersion(BlockVersionV1, "proposer", basis, "ptx", txs, blk.ProposedTime, "root")
+	require.Empty(t, old.StateRoot)
+	hashed := *old
+	hashed.Hash = ""
+	require.Equal(t, common.MustMakeObjectHashString(hashed), old.Hash)
+}
@@ -0,0 +1,673 @@
+package block
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+const (
+	CheckKindBlock          = "block"
+	CheckKindBlockIndex     = "block-index"
+	CheckKindTransaction    = "transaction"
+	CheckKindTxIndex        = "transaction-index"
+	CheckKindOperation      = "operation"
+	CheckKindOperationIndex = "operation-index"
+	CheckKindAccountIndex   = "account-index"
+	CheckKindSequenceID     = "sequence-id"
+	CheckKindSupply         = "supply"
+)
+
+// CheckProblem is the inconsistency of storage, which is found by `Check`.
+// The problem of index can be repaired, but the problem of primary record like
+// missing block can not be.
+type CheckProblem struct {
+	Kind     string `json:"kind"`
+	Key      string `json:"key"` // hash or address of record
+	Message  string `json:"message"`
+	Repaired bool   `json:"repaired"`
+
+	repair func(storage.Backend) error
+}
+
+func (p CheckProblem) Repairable() bool {
+	return p.repair != nil
+}
+
+func (p CheckProblem) String() string {
+	return fmt.Sprintf("%s: %s: %s", p.Kind, p.Key, p.Message)
+}
+
+// Check walks the blocks, transactions, operations and accounts and checks
+// their indices refer to each other. The hash chain of blocks is checked and
+// the total balance of accounts is compared with the genesis balance and the
+// inflations; the total balance is not checked in the pruned storage.
+//
+// With `repair`, the broken indices are rebuilt from the primary records and
+// the dangling indices are removed.
+func Check(st storage.Backend, repair bool) (problems []*CheckProblem, err error) {
+	c := &checker{st: st, repair: repair, supplyComplete: true}
+
+	for _, f := range []func() error{
+		c.checkDanglingBlockIndices,
+		c.checkBlocks,
+		c.checkChain,
+		c.checkDanglingTransactionIndices,
+		c.checkAccounts,
+		c.checkDanglingAccountIndices,
+		c.checkSupply,
+	} {
+		if err = f(); err != nil {
+			return
+		}
+		if err = c.repairProblems(); err != nil {
+			return
+		}
+	}
+
+	return c.problems, nil
+}
+
+type checker struct {
+	st       storage.Backend
+	repair   bool
+	problems []*CheckProblem
+	pending  []*CheckProblem
+
+	// the sums can be over `common.MaximumBalance`, so they are not added by
+	// `common.Amount.Add`
+	supply         common.Amount // genesis balance and inflations
+	total          common.Amount // sum of account balances
+	supplyComplete bool
+}
+
+func (c *checker) add(kind, key, message string, repair func(storage.Backend) error) {
+	p := &CheckProblem{Kind: kind, Key: key, Message: message, repair: repair}
+	c.problems = append(c.problems, p)
+	if repair != nil {
+		c.pending = append(c.pending, p)
+	}
+}
+
+// repairProblems repairs the problems found by the last check; the storage
+// is not changed while it is walked.
+func (c *checker) repairProblems() (err error) {
+	pending := c.pending
+	c.pending = nil
+
+	if !c.repair {
+		return
+	}
+
+	for _, p := range pending {
+		if err = p.repair(c.st); err != nil {
+			return
+		}
+		p.Repaired = true
+	}
+
+	return
+}
+
+// walkHashes walks the index items under the prefix, which have the hash as
+// value.
+func (c *checker) walkHashes(prefix string, f func(key, hash string) error) (err error) {
+	iterFunc, closeFunc := c.st.GetIterator(prefix, nil)
+	defer closeFunc()
+
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var hash string
+		if json.Unmarshal(item.Value, &hash) != nil {
+			c.add(indexKind(prefix), fmt.Sprintf("%q", item.Key), "broken index", removeFunc(string(item.Key)))
+			continue
+		}
+		if err = f(string(item.Key), hash); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// hasIndex checks the index item of hash is under the prefix.
+func (c *checker) hasIndex(prefix, hash string) (found bool) {
+	iterFunc, closeFunc := c.st.GetIterator(prefix, nil)
+	defer closeFunc()
+
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var value string
+		if json.Unmarshal(item.Value, &value) == nil && value == hash {
+			return true
+		}
+	}
+
+	return
+}
+
+func (c *checker) checkDanglingIndices(kind string, prefixes []string, exists func(string) (bool, error)) error {
+	for _, prefix := range prefixes {
+		err := c.walkHashes(prefix, func(key, hash string) error {
+			if found, err := exists(hash); err != nil {
+				return err
+			} else if !found {
+				c.add(kind, hash, "index refers to missing record", removeFunc(key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *checker) checkDanglingBlockIndices() error {
+	return c.checkDanglingIndices(
+		CheckKindBlockIndex,
+		[]string{common.BlockPrefixHeight, common.BlockPrefixConfirmed},
+		func(hash string) (bool, error) { return ExistsBlock(c.st, hash) },
+	)
+}
+
+// checkBlocks checks every block has the height and confirmed index.
+func (c *checker) checkBlocks() error {
+	iterFunc, closeFunc := c.st.GetIterator(common.BlockPrefixHash, nil)
+	defer closeFunc()
+
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var blk Block
+		if err := json.Unmarshal(item.Value, &blk); err != nil {
+			c.add(CheckKindBlock, fmt.Sprintf("%q", item.Key), "broken block", nil)
+			continue
+		}
+
+		var indexed string
+		if err := c.st.Get(getBlockKeyPrefixHeight(blk.Height), &indexed); err == errors.StorageRecordDoesNotExist {
+			c.add(CheckKindBlockIndex, blk.Hash, "height index not found", newFunc(getBlockKeyPrefixHeight(blk.Height), blk.Hash))
+		} else if err != nil {
+			return err
+		} else if indexed != blk.Hash {
+			c.add(CheckKindBlock, blk.Hash, fmt.Sprintf("another block, %s exists at height %d", indexed, blk.Height), nil)
+		}
+
+		confirmedPrefix := fmt.Sprintf("%s%s-", common.BlockPrefixConfirmed, blk.ProposedTime)
+		if !c.hasIndex(confirmedPrefix, blk.Hash) {
+			c.add(CheckKindBlockIndex, blk.Hash, "confirmed index not found", newFunc(blk.NewBlockKeyConfirmed(), blk.Hash))
+		}
+	}
+
+	return nil
+}
+
+// checkChain walks the blocks by height and checks the hash chain; the
+// transactions of the blocks, which are not pruned, are checked.
+func (c *checker) checkChain() error {
+	lowest := GetLowestFullBlockHeight(c.st)
+	if lowest > common.GenesisBlockHeight {
+		c.supplyComplete = false
+	}
+
+	var prev *Block
+	expected := common.GenesisBlockHeight
+
+	return c.walkHashes(common.BlockPrefixHeight, func(key, hash string) error {
+		height, err := strconv.ParseUint(key[len(common.BlockPrefixHeight):], 10, 64)
+		if err != nil {
+			c.add(CheckKindBlockIndex, fmt.Sprintf("%q", key), "broken height index", removeFunc(key))
+			return nil
+		}
+
+		// the missing block is reported by `checkDanglingBlockIndices`
+		blk, err := GetBlock(c.st, hash)
+		if err == errors.StorageRecordDoesNotExist {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if height != expected {
+			c.add(CheckKindBlock, strconv.FormatUint(expected, 10), fmt.Sprintf("blocks from height %d to %d not found", expected, height-1), nil)
+			c.supplyComplete = false
+			prev = nil
+		}
+		expected = height + 1
+
+		if blk.Height != height {
+			c.add(CheckKindBlock, blk.Hash, fmt.Sprintf("block of height %d is indexed at height %d", blk.Height, height), nil)
+		}
+		if !blk.VerifyHash() {
+			c.add(CheckKindBlock, blk.Hash, "hash does not match", nil)
+		}
+		if prev != nil && blk.PrevBlockHash != prev.Hash {
+			c.add(CheckKindBlock, blk.Hash, fmt.Sprintf("previous block hash, %s does not match with %s", blk.PrevBlockHash, prev.Hash), nil)
+		}
+		prev = &blk
+
+		if height < lowest {
+			return nil
+		}
+
+		return c.checkTransactions(blk)
+	})
+}
+
+func (c *checker) checkTransactions(blk Block) error {
+	hashes := blk.Transactions
+	if len(blk.ProposerTransaction) > 0 {
+		hashes = append([]string{blk.ProposerTransaction}, hashes...)
+	}
+
+	for _, hash := range hashes {
+		bt, err := GetBlockTransaction(c.st, hash)
+		if err == errors.StorageRecordDoesNotExist {
+			c.add(CheckKindTransaction, hash, fmt.Sprintf("transaction of block %s not found", blk.Hash), nil)
+			c.supplyComplete = false
+			continue
+		} else if err != nil {
+			return err
+		}
+		bt.blockHeight = blk.Height
+
+		if bt.Block != blk.Hash {
+			c.add(CheckKindTransaction, hash, fmt.Sprintf("transaction is in block %s, not %s", bt.Block, blk.Hash), nil)
+		}
+
+		var tx transaction.Transaction
+		if tp, err := GetTransactionPool(c.st, hash); err == errors.StorageRecordDoesNotExist {
+			c.add(CheckKindTransaction, hash, "transaction pool not found", nil)
+			c.supplyComplete = false
+		} else if err != nil {
+			return err
+		} else {
+			tx = tp.Transaction()
+			bt.transaction = tx
+			c.addSupply(blk, tx)
+		}
+
+		c.checkTransactionIndices(bt)
+
+		for _, opHash := range bt.Operations {
+			if err := c.checkOperation(bt, opHash); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *checker) checkTransactionIndices(bt BlockTransaction) {
+	height := heightKey(bt.blockHeight)
+
+	indices := map[string]string{
+		GetBlockTransactionKeyPrefixSource(bt.Source) + height:  bt.NewBlockTransactionKeySource(),
+		GetBlockTransactionKeyPrefixConfirmed(bt.Confirmed):     bt.NewBlockTransactionKeyConfirmed(),
+		GetBlockTransactionKeyPrefixAccount(bt.Source) + height: bt.NewBlockTransactionKeyByAccount(bt.Source),
+		GetBlockTransactionKeyPrefixBlock(bt.Block):             bt.NewBlockTransactionKeyByBlock(bt.Block),
+	}
+
+	for prefix, key := range indices {
+		if !c.hasIndex(prefix, bt.Hash) {
+			c.add(CheckKindTxIndex, bt.Hash, fmt.Sprintf("%s index not found", indexKind(prefix)), newFunc(key, bt.Hash))
+		}
+	}
+}
+
+// checkOperation checks the operation of transaction and it's indices. The
+// missing operation is saved again from the transaction pool.
+func (c *checker) checkOperation(bt BlockTransaction, opHash string) error {
+	bo, err := GetBlockOperation(c.st, opHash)
+	if err == errors.StorageRecordDoesNotExist {
+		var repair func(storage.Backend) error
+		for _, op := range bt.transaction.B.Operations {
+			if NewBlockOperationKey(common.MustMakeObjectHashString(op), bt.Hash) != opHash {
+				continue
+			}
+			op := op
+			repair = func(st storage.Backend) error {
+				return bt.SaveBlockOperation(st, op)
+			}
+			break
+		}
+		c.add(CheckKindOperation, opHash, "operation not found", repair)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	bo.seqID = bt.SequenceID
+
+	height := heightKey(bo.Height)
+	indices := map[string]string{
+		keyPrefixTxHash(bo.TxHash):                          bo.NewBlockOperationTxHashKey(),
+		keyPrefixSource(bo.Source) + height:                 bo.NewBlockOperationSourceKey(),
+		keyPrefixSourceAndType(bo.Source, bo.Type) + height: bo.NewBlockOperationSourceAndTypeKey(),
+		keyPrefixPeers(bo.Source) + height:                  bo.NewBlockOperationPeersKey(bo.Source),
+		keyPrefixPeersAndType(bo.Source, bo.Type) + height:  bo.NewBlockOperationPeersAndTypeKey(bo.Source),
+		keyPrefixBlockHeight(bo.Height):                     bo.NewBlockOperationBlockHeightKey(),
+	}
+	if bo.hasTarget() {
+		indices[keyPrefixTarget(bo.Target)+height] = bo.NewBlockOperationTargetKey(bo.Target)
+		indices[keyPrefixTargetAndType(bo.Target, bo.Type)+height] = bo.NewBlockOperationTargetAndTypeKey(bo.Target)
+		indices[keyPrefixPeers(bo.Target)+height] = bo.NewBlockOperationPeersKey(bo.Target)
+		indices[keyPrefixPeersAndType(bo.Target, bo.Type)+height] = bo.NewBlockOperationPeersAndTypeKey(bo.Target)
+	}
+
+	for prefix, key := range indices {
+		if !c.hasIndex(prefix, bo.Hash) {
+			c.add(CheckKindOperationIndex, bo.Hash, fmt.Sprintf("%s index not found", indexKind(prefix)), newFunc(key, bo.Hash))
+		}
+	}
+
+	// the payable operation indexes the transaction by it's target
+	if body, err := operation.UnmarshalBodyJSON(bo.Type, bo.Body); err == nil {
+		if pop, ok := body.(operation.Payable); ok {
+			prefix := GetBlockTransactionKeyPrefixAccount(pop.TargetAddress()) + height
+			if !c.hasIndex(prefix, bt.Hash) {
+				c.add(CheckKindTxIndex, bt.Hash, fmt.Sprintf("%s index not found", indexKind(prefix)), newFunc(bt.NewBlockTransactionKeyByAccount(pop.TargetAddress()), bt.Hash))
+			}
+		}
+	}
+
+	return nil
+}
+
+// addSupply adds the balance, which is created by the transaction; the
+// genesis accounts and the inflations.
+func (c *checker) addSupply(blk Block, tx transaction.Transaction) {
+	for _, op := range tx.B.Operations {
+		var amount common.Amount
+		switch opb := op.B.(type) {
+		case operation.CreateAccount:
+			if blk.Height != common.GenesisBlockHeight {
+				continue
+			}
+			amount = opb.Amount
+		case operation.Inflation:
+			amount = opb.GetAmount()
+		case operation.InflationPF:
+			amount = opb.GetAmount()
+		default:
+			continue
+		}
+
+		c.supply += amount
+	}
+}
+
+func (c *checker) checkDanglingTransactionIndices() error {
+	err := c.checkDanglingIndices(
+		CheckKindTxIndex,
+		[]string{
+			common.BlockTransactionPrefixSource,
+			common.BlockTransactionPrefixConfirmed,
+			common.BlockTransactionPrefixAccount,
+			common.BlockTransactionPrefixBlock,
+			common.BlockTransactionPrefixMemo,
+		},
+		func(hash string) (bool, error) { return ExistsBlockTransaction(c.st, hash) },
+	)
+	if err != nil {
+		return err
+	}
+
+	return c.checkDanglingIndices(
+		CheckKindOperationIndex,
+		[]string{
+			common.BlockOperationPrefixTxHash,
+			common.BlockOperationPrefixSource,
+			common.BlockOperationPrefixTarget,
+			common.BlockOperationPrefixPeers,
+			common.BlockOperationPrefixTypeSource,
+			common.BlockOperationPrefixTypeTarget,
+			common.BlockOperationPrefixTypePeers,
+			common.BlockOperationPrefixCreateFrozen,
+			common.BlockOperationPrefixFrozenLinked,
+			common.BlockOperationPrefixBlockHeight,
+		},
+		func(hash string) (bool, error) { return ExistsBlockOperation(c.st, hash) },
+	)
+}
+
+// checkAccounts checks every account has the 'created' index and the
+// `BlockAccountSequenceID` of it's sequence id; the balances are summed for
+// `checkSupply`.
+func (c *checker) checkAccounts() error {
+	iterFunc, closeFunc := c.st.GetIterator(common.BlockAccountPrefixAddress, nil)
+	defer closeFunc()
+
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var ba BlockAccount
+		if err := json.Unmarshal(item.Value, &ba); err != nil {
+			c.add(CheckKindAccountIndex, fmt.Sprintf("%q", item.Key), "broken account", nil)
+			c.supplyComplete = false
+			continue
+		}
+
+		c.total += ba.Balance
+
+		if err := c.checkAccountCreated(ba); err != nil {
+			return err
+		}
+
+		if exists, err := c.st.Has(GetBlockAccountSequenceIDKey(ba.Address, ba.SequenceID)); err != nil {
+			return err
+		} else if !exists {
+			bac := BlockAccountSequenceID{SequenceID: ba.SequenceID, Address: ba.Address, Balance: ba.GetBalance()}
+			c.add(CheckKindSequenceID, ba.Address, fmt.Sprintf("sequence id %d not found", ba.SequenceID), bac.Save)
+		}
+	}
+
+	return nil
+}
+
+func (c *checker) checkAccountCreated(ba BlockAccount) error {
+	createdAddressKey := GetBlockAccountCreatedAddressKey(ba.Address)
+
+	var createdKey string
+	if err := c.st.Get(createdAddressKey, &createdKey); err == nil {
+		var address string
+		if err = c.st.Get(createdKey, &address); err == nil && address == ba.Address {
+			return nil
+		} else if err != nil && err != errors.StorageRecordDoesNotExist {
+			return err
+		}
+	} else if err != errors.StorageRecordDoesNotExist {
+		return err
+	}
+
+	c.add(CheckKindAccountIndex, ba.Address, "created index not found", func(st storage.Backend) (err error) {
+		var found string
+		if found, err = findBlockAccountCreatedKey(st, ba.Address); err != nil {
+			return
+		}
+		if len(found) < 1 {
+			found = GetBlockAccountCreatedKey(common.GetUniqueIDFromUUID())
+			if err = st.New(found, ba.Address); err != nil {
+				return
+			}
+		}
+
+		return setFunc(createdAddressKey, found)(st)
+	})
+
+	return nil
+}
+
+// findBlockAccountCreatedKey walks the whole 'created' keys to find the key of
+// address.
+func findBlockAccountCreatedKey(st storage.Backend, address string) (createdKey string, err error) {
+	iterFunc, closeFunc := st.GetIterator(common.BlockAccountPrefixCreated, nil)
+	defer closeFunc()
+
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var a string
+		if json.Unmarshal(item.Value, &a) == nil && a == address {
+			return string(item.Key), nil
+		}
+	}
+
+	return
+}
+
+func (c *checker) checkDanglingAccountIndices() (err error) {
+	err = c.walkHashes(common.BlockAccountPrefixCreated, func(key, address string) error {
+		if exists, err := ExistsBlockAccount(c.st, address); err != nil {
+			return err
+		} else if !exists {
+			c.add(CheckKindAccountIndex, address, "created index refers to missing account", removeFunc(key))
+			return nil
+		}
+
+		var createdKey string
+		if err := c.st.Get(GetBlockAccountCreatedAddressKey(address), &createdKey); err != nil {
+			if err == errors.StorageRecordDoesNotExist {
+				return nil
+			}
+			return err
+		}
+		if createdKey != key {
+			c.add(CheckKindAccountIndex, address, "duplicated created index", removeFunc(key))
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	err = c.walkHashes(common.BlockAccountPrefixCreatedAddress, func(key, createdKey string) error {
+		if exists, err := c.st.Has(createdKey); err != nil {
+			return err
+		} else if !exists {
+			c.add(CheckKindAccountIndex, key[len(common.BlockAccountPrefixCreatedAddress):], "created address index refers to missing created index", removeFunc(key))
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	return c.walkHashes(common.BlockAccountSequenceIDByAddressPrefix, func(key, seqKey string) error {
+		if exists, err := c.st.Has(seqKey); err != nil {
+			return err
+		} else if !exists {
+			c.add(CheckKindSequenceID, seqKey, "index refers to missing sequence id", removeFunc(key))
+		}
+		return nil
+	})
+}
+
+// checkSupply compares the total balance of accounts with the supply; if the
+// transactions of some blocks are pruned or missing, the supply is unknown.
+func (c *checker) checkSupply() error {
+	if c.supplyComplete && c.total != c.supply {
+		c.add(CheckKindSupply, "", fmt.Sprintf("total balance, %v is not same with the supply, %v", c.total, c.supply), nil)
+	}
+
+	return nil
+}
+
+func indexKind(prefix string) string {
+	if len(prefix) < 1 {
+		return "unknown"
+	}
+
+	switch prefix[:1] {
+	case common.BlockPrefixConfirmed:
+		return "block confirmed"
+	case common.BlockPrefixHeight:
+		return "block height"
+	case common.BlockTransactionPrefixSource:
+		return "transaction source"
+	case common.BlockTransactionPrefixConfirmed:
+		return "transaction confirmed"
+	case common.BlockTransactionPrefixAccount:
+		return "transaction account"
+	case common.BlockTransactionPrefixBlock:
+		return "transaction block"
+	case common.BlockTransactionPrefixMemo:
+		return "transaction memo"
+	case common.BlockOperationPrefixTxHash:
+		return "operation transaction"
+	case common.BlockOperationPrefixSource:
+		return "operation source"
+	case common.BlockOperationPrefixTarget:
+		return "operation target"
+	case common.BlockOperationPrefixPeers:
+		return "operation peers"
+	case common.BlockOperationPrefixTypeSource:
+		return "operation type and source"
+	case common.BlockOperationPrefixTypeTarget:
+		return "operation type and target"
+	case common.BlockOperationPrefixTypePeers:
+		return "operation type and peers"
+	case common.BlockOperationPrefixCreateFrozen:
+		return "operation create frozen"
+	case common.BlockOperationPrefixFrozenLinked:
+		return "operation frozen linked"
+	case common.BlockOperationPrefixBlockHeight:
+		return "operation block height"
+	case common.BlockAccountPrefixCreated:
+		return "account created"
+	case common.BlockAccountPrefixCreatedAddress:
+		return "account created address"
+	case common.BlockAccountSequenceIDByAddressPrefix:
+		return "sequence id"
+	default:
+		return "unknown"
+	}
+}
+
+func newFunc(key string, value interface{}) func(storage.Backend) error {
+	return func(st storage.Backend) error {
+		return st.New(key, value)
+	}
+}
+
+func setFunc(key string, value interface{}) func(storage.Backend) error {
+	return func(st storage.Backend) error {
+		if exists, err := st.Has(key); err != nil {
+			return err
+		} else if exists {
+			return st.Set(key, value)
+		}
+		return st.New(key, value)
+	}
+}
+
+func removeFunc(key string) func(storage.Backend) error {
+	return func(st storage.Backend) error {
+		return removeKey(st, key)
+	}
+}
@@ -0,0 +1,179 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// makeCheckTestBlockchain makes the blocks after genesis; each block has the
+// proposer transaction, which deposits the inflation to the common account,
+// and the payment transaction. The payment is not applied to the accounts, so
+// the total balance is kept.
+func makeCheckTestBlockchain(t *testing.T, n int) (st storage.Backend, blocks []Block) {
+	conf := common.NewTestConfig()
+	st = InitTestBlockchain()
+
+	prev := GetGenesis(st)
+	blocks = append(blocks, prev)
+	for i := 0; i < n; i++ {
+		kp := keypair.Random()
+		tx := transaction.TestMakeTransactionWithKeypair(conf.NetworkID, 1, kp)
+
+		inflation, err := common.CalculateInflation(conf.InitialBalance)
+		require.NoError(t, err)
+		opc, _ := operation.NewOperation(operation.NewCollectTxFee(CommonKP.Address(), tx.B.Fee, 1, prev.Height, prev.Hash, prev.TotalTxs))
+		opi, _ := operation.NewOperation(operation.NewOperationBodyInflation(CommonKP.Address(), inflation, conf.InitialBalance, prev.Height, prev.Hash, prev.TotalTxs))
+		ptx, _ := transaction.NewTransaction(kp.Address(), 0, opc, opi)
+
+		blk := *NewBlock(
+			kp.Address(),
+			voting.Basis{
+				Height:    prev.Height + 1,
+				BlockHash: prev.Hash,
+				TotalTxs:  prev.TotalTxs + 2,
+				TotalOps:  prev.TotalOps + 3,
+			},
+			ptx.GetHash(),
+			[]string{tx.GetHash()},
+			common.NowISO8601(),
+		)
+		blk.MustSave(st)
+
+		for _, saved := range []transaction.Transaction{ptx, tx} {
+			bt := NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, saved)
+			bt.MustSave(st)
+			require.NoError(t, bt.SaveBlockOperations(st))
+			_, err := SaveTransactionPool(st, saved)
+			require.NoError(t, err)
+		}
+
+		ba, err := GetBlockAccount(st, CommonKP.Address())
+		require.NoError(t, err)
+		require.NoError(t, ba.Deposit(inflation))
+		require.NoError(t, ba.Save(st))
+
+		blocks = append(blocks, blk)
+		prev = blk
+	}
+
+	return
+}
+
+func requireNoCheckProblems(t *testing.T, st storage.Backend) {
+	problems, err := Check(st, false)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(problems), "%v", problems)
+}
+
+func TestCheck(t *testing.T) {
+	st, _ := makeCheckTestBlockchain(t, 3)
+	defer st.Close()
+
+	requireNoCheckProblems(t, st)
+}
+
+func TestCheckRepairIndices(t *testing.T) {
+	st, blocks := makeCheckTestBlockchain(t, 3)
+	defer st.Close()
+
+	blk := blocks[2]
+	bt, err := GetBlockTransaction(st, blk.Transactions[0])
+	require.NoError(t, err)
+	bo, err := GetBlockOperation(st, bt.Operations[0])
+	require.NoError(t, err)
+
+	// missing indices
+	require.NoError(t, removeIndices(st, GetBlockTransactionKeyPrefixBlock(blk.Hash), bt.Hash))
+	require.NoError(t, removeIndices(st, keyPrefixSource(bo.Source)+heightKey(bo.Height), bo.Hash))
+	require.NoError(t, removeIndices(st, keyPrefixTarget(bo.Target)+heightKey(bo.Height), bo.Hash))
+	require.NoError(t, st.Remove(getBlockKeyPrefixHeight(blocks[1].Height)))
+	require.NoError(t, st.Remove(GetBlockAccountCreatedAddressKey(CommonKP.Address())))
+
+	// dangling indices
+	require.NoError(t, st.New(GetBlockAccountCreatedKey(common.GetUniqueIDFromUUID()), keypair.Random().Address()))
+	dangling := bo
+	dangling.Hash = "findme"
+	dangling.seqID = bt.SequenceID
+	require.NoError(t, st.New(dangling.NewBlockOperationSourceKey(), dangling.Hash))
+
+	// missing operation
+	require.NoError(t, pruneBlockOperation(st, bo))
+
+	problems, err := Check(st, false)
+	require.NoError(t, err)
+	require.True(t, len(problems) > 0)
+	for _, p := range problems {
+		require.False(t, p.Repaired)
+	}
+
+	repaired, err := Check(st, true)
+	require.NoError(t, err)
+	require.True(t, len(repaired) > 0)
+	for _, p := range repaired {
+		require.True(t, p.Repaired, "%v", p)
+	}
+
+	requireNoCheckProblems(t, st)
+
+	// repaired indices work
+	exists, err := ExistsBlockByHeight(st, blocks[1].Height)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	var found []string
+	iterFunc, closeFunc := GetBlockOperationsBySource(st, bo.Source, nil)
+	for {
+		o, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		found = append(found, o.Hash)
+	}
+	closeFunc()
+	require.Contains(t, found, bo.Hash)
+}
+
+func TestCheckChain(t *testing.T) {
+	st, blocks := makeCheckTestBlockchain(t, 3)
+	defer st.Close()
+
+	changed := blocks[2]
+	changed.PrevBlockHash = blocks[0].Hash
+	require.NoError(t, st.Set(getBlockKey(changed.Hash), changed))
+
+	problems, err := Check(st, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(problems), "%v", problems) // hash and previous block hash
+	for _, p := range problems {
+		require.Equal(t, CheckKindBlock, p.Kind)
+		require.Equal(t, changed.Hash, p.Key)
+		require.False(t, p.Repairable())
+	}
+}
+
+func TestCheckSupply(t *testing.T) {
+	st, _ := makeCheckTestBlockchain(t, 3)
+	defer st.Close()
+
+	ba, err := GetBlockAccount(st, CommonKP.Address())
+	require.NoError(t, err)
+	require.NoError(t, ba.Deposit(1))
+	require.NoError(t, ba.Save(st))
+
+	problems, err := Check(st, false)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(problems))
+	require.Equal(t, CheckKindSupply, problems[0].Kind)
+
+	// the supply of pruned storage can not be checked
+	require.NoError(t, SavePrunedBlockHeight(st, common.GenesisBlockHeight))
+	requireNoCheckProblems(t, st)
+}
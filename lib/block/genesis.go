@@ -18,7 +18,7 @@ import (
 )
 
 // Returns: Genesis block
-func GetGenesis(st *storage.LevelDBBackend) Block {
+func GetGenesis(st storage.Backend) Block {
 	if blk, err := GetBlockByHeight(st, common.GenesisBlockHeight); err != nil {
 		panic(err)
 	} else {
@@ -32,6 +32,7 @@ func GetGenesis(st *storage.LevelDBBackend) Block {
 // * `Block.Proposer` is empty
 // * `Block.Transaction` is empty
 // * `Block.ProposedTime` is `common.GenesisBlockConfirmedTime`
+// * `Block.Version` is always `BlockVersionV0`
 // * has only one `Transaction`
 //
 // This Transaction is different from other normal Transaction;
@@ -44,7 +45,7 @@ func GetGenesis(st *storage.LevelDBBackend) Block {
 //   * `CreateAccount.Amount` is 0
 //   * `CreateAccount.Target` is common account
 // * `Transaction.B.Fee` is 0
-func MakeGenesisBlock(st *storage.LevelDBBackend, genesisAccount BlockAccount, commonAccount BlockAccount, networkID []byte) (blk *Block, err error) {
+func MakeGenesisBlock(st storage.Backend, genesisAccount BlockAccount, commonAccount BlockAccount, networkID []byte) (blk *Block, err error) {
 	if genesisAccount.Address == commonAccount.Address {
 		err = fmt.Errorf("genesis account and common account are same.")
 		return
@@ -102,7 +103,8 @@ func MakeGenesisBlock(st *storage.LevelDBBackend, genesisAccount BlockAccount, c
 	kp := keypair.Master(string(networkID))
 	tx.Sign(kp, []byte(networkID))
 
-	blk = NewBlock(
+	blk = NewBlockWithVersion(
+		BlockVersionV0,
 		"",
 		voting.Basis{
 			Height:   common.GenesisBlockHeight,
@@ -112,6 +114,7 @@ func MakeGenesisBlock(st *storage.LevelDBBackend, genesisAccount BlockAccount, c
 		"",
 		[]string{tx.GetHash()},
 		common.GenesisBlockConfirmedTime,
+		"",
 	)
 	if err = blk.Save(st); err != nil {
 		return
@@ -129,5 +132,11 @@ func MakeGenesisBlock(st *storage.LevelDBBackend, genesisAccount BlockAccount, c
 		return
 	}
 
+	for _, ba := range []BlockAccount{genesisAccount, commonAccount} {
+		if err = NewBlockAccountHistory(&ba, blk.Height).Save(st); err != nil {
+			return
+		}
+	}
+
 	return
 }
@@ -3,9 +3,43 @@ package block
 import (
 	"encoding/json"
 
+	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/voting"
 )
 
+const (
+	// BlockVersionV0 block has the object hash of transactions as
+	// `TransactionsRoot`.
+	BlockVersionV0 uint32 = 0
+
+	// BlockVersionV1 block has the root of Merkle tree of transactions as
+	// `TransactionsRoot`, so the transaction can be proved by
+	// `Block.TransactionProof`.
+	BlockVersionV1 uint32 = 1
+
+	// BlockVersionV2 block has `StateRoot`, the root of state trie of the
+	// accounts after the block is finished.
+	BlockVersionV2 uint32 = 2
+)
+
+// GetBlockVersion returns the version of block at the given height; the
+// version is decided by the activation heights of network,
+// `common.BlockVersionV1Height` and `common.BlockVersionV2Height`, not by the
+// version of node. The genesis block is always `BlockVersionV0`, so the hash
+// of genesis block is not changed by the new versions.
+func GetBlockVersion(height uint64) uint32 {
+	switch {
+	case height <= common.GenesisBlockHeight:
+		return BlockVersionV0
+	case height >= common.BlockVersionV2Height:
+		return BlockVersionV2
+	case height >= common.BlockVersionV1Height:
+		return BlockVersionV1
+	default:
+		return BlockVersionV0
+	}
+}
+
 type Header struct {
 	// TODO rename `Header` to `BlockHeader`
 	Version          uint32 `json:"version"`
@@ -15,6 +49,9 @@ type Header struct {
 	Height           uint64 `json:"height"`
 	TotalTxs         uint64 `json:"total-txs"`
 	TotalOps         uint64 `json:"total-ops"`
+	// StateRoot is not included in the hash of the blocks before
+	// `BlockVersionV2`; see `Block.makeHash`.
+	StateRoot string `json:"state_root" rlp:"-"`
 
 	// TODO smart contract fields
 }
@@ -0,0 +1,139 @@
+package block
+
+import (
+	"fmt"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// migration upgrades the storage, which was made by the previous version.
+type migration struct {
+	name string
+	run  func(storage.Backend) error
+}
+
+// migrations are run in order by `Migrate`; the new migration must be
+// appended.
+var migrations = []migration{
+	{name: "block-account-created-address", run: migrateBlockAccountCreatedAddress},
+	{name: "block-account-history", run: migrateBlockAccountHistory},
+}
+
+func getMigrationKey(name string) string {
+	return fmt.Sprintf("%s-migration-%s", common.InternalPrefix, name)
+}
+
+// Migrate runs the migrations, which are not done yet in the storage; the
+// done migration is recorded, so it runs only once.
+func Migrate(st storage.Backend) (err error) {
+	for _, m := range migrations {
+		key := getMigrationKey(m.name)
+
+		var done bool
+		if done, err = st.Has(key); err != nil {
+			return
+		} else if done {
+			continue
+		}
+
+		if err = m.run(st); err != nil {
+			return
+		}
+		if err = st.New(key, true); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// migrateBlockAccountCreatedAddress makes the 'created-address' index of the
+// accounts, which were saved before the index.
+func migrateBlockAccountCreatedAddress(st storage.Backend) (err error) {
+	created := map[string]string{}
+	iterFunc, closeFunc := st.GetIterator(common.BlockAccountPrefixCreated, nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var address string
+		common.MustUnmarshalJSON(item.Value, &address)
+		created[address] = string(item.Key)
+	}
+	closeFunc()
+
+	for address, createdKey := range created {
+		var exists bool
+		if exists, err = st.Has(GetBlockAccountCreatedAddressKey(address)); err != nil {
+			return
+		} else if exists {
+			continue
+		}
+
+		if err = st.New(GetBlockAccountCreatedAddressKey(address), createdKey); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// migrateBlockAccountHistory saves the `BlockAccountHistory` of the current
+// accounts at the latest block, if the storage was made before
+// `BlockAccountHistory`; the history of the previous blocks is not available.
+func migrateBlockAccountHistory(st storage.Backend) (err error) {
+	var exists bool
+	if exists, err = ExistsBlockByHeight(st, common.GenesisBlockHeight); err != nil || !exists {
+		return
+	}
+
+	// the genesis block saves the history of the genesis accounts
+	var hasGenesis bool
+	iterFunc, closeFunc := st.GetIterator(common.BlockAccountHistoryPrefixAddress, nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var h BlockAccountHistory
+		common.MustUnmarshalJSON(item.Value, &h)
+		if h.Height == common.GenesisBlockHeight {
+			hasGenesis = true
+			break
+		}
+	}
+	closeFunc()
+
+	if hasGenesis {
+		return
+	}
+
+	latest := GetLatestBlock(st)
+
+	var accounts []BlockAccount
+	iterFunc, closeFunc = st.GetIterator(common.BlockAccountPrefixAddress, nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var ba BlockAccount
+		common.MustUnmarshalJSON(item.Value, &ba)
+		accounts = append(accounts, ba)
+	}
+	closeFunc()
+
+	for _, ba := range accounts {
+		ba := ba
+		if err = NewBlockAccountHistory(&ba, latest.Height).Save(st); err != nil {
+			return
+		}
+	}
+
+	return SaveBlockAccountHistoryStart(st, latest.Height)
+}
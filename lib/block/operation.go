@@ -92,7 +92,7 @@ func (bo *BlockOperation) targetIsLinked() bool {
 	return false
 }
 
-func (bo *BlockOperation) Save(st *storage.LevelDBBackend) (err error) {
+func (bo *BlockOperation) Save(st storage.Backend) (err error) {
 	if bo.isSaved {
 		return errors.AlreadySaved
 	}
@@ -296,11 +296,11 @@ func (bo BlockOperation) NewBlockOperationBlockHeightKey() string {
 	)
 }
 
-func ExistsBlockOperation(st *storage.LevelDBBackend, hash string) (bool, error) {
+func ExistsBlockOperation(st storage.Backend, hash string) (bool, error) {
 	return st.Has(key(hash))
 }
 
-func GetBlockOperation(st *storage.LevelDBBackend, hash string) (bo BlockOperation, err error) {
+func GetBlockOperation(st storage.Backend, hash string) (bo BlockOperation, err error) {
 	if err = st.Get(key(hash), &bo); err != nil {
 		return
 	}
@@ -310,7 +310,7 @@ func GetBlockOperation(st *storage.LevelDBBackend, hash string) (bo BlockOperati
 }
 
 // Looks up the operation referenced by `txHash`, then get the operation's hash from it
-func GetBlockOperationByIndex(st *storage.LevelDBBackend, txHash string, opIndex int) (BlockOperation, error) {
+func GetBlockOperationByIndex(st storage.Backend, txHash string, opIndex int) (BlockOperation, error) {
 	if bt, err := GetBlockTransaction(st, txHash); err != nil {
 		return BlockOperation{}, err
 	} else if opIndex < 0 || opIndex >= len(bt.Operations) {
@@ -321,7 +321,7 @@ func GetBlockOperationByIndex(st *storage.LevelDBBackend, txHash string, opIndex
 }
 
 func LoadBlockOperationsInsideIterator(
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	iterFunc func() (storage.IterItem, bool),
 	closeFunc func(),
 ) (
@@ -349,7 +349,7 @@ func LoadBlockOperationsInsideIterator(
 		})
 }
 
-func GetBlockOperationsByTx(st *storage.LevelDBBackend, txHash string, options storage.ListOptions) (
+func GetBlockOperationsByTx(st storage.Backend, txHash string, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -358,7 +358,7 @@ func GetBlockOperationsByTx(st *storage.LevelDBBackend, txHash string, options s
 	return LoadBlockOperationsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockOperationsBySource(st *storage.LevelDBBackend, source string, options storage.ListOptions) (
+func GetBlockOperationsBySource(st storage.Backend, source string, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -368,7 +368,7 @@ func GetBlockOperationsBySource(st *storage.LevelDBBackend, source string, optio
 }
 
 // Find all operations which created frozen account.
-func GetBlockOperationsByFrozen(st *storage.LevelDBBackend, options storage.ListOptions) (
+func GetBlockOperationsByFrozen(st storage.Backend, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -377,7 +377,7 @@ func GetBlockOperationsByFrozen(st *storage.LevelDBBackend, options storage.List
 }
 
 // Find all operations which created frozen account and have the link of a general account's address.
-func GetBlockOperationsByLinked(st *storage.LevelDBBackend, hash string, options storage.ListOptions) (
+func GetBlockOperationsByLinked(st storage.Backend, hash string, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -385,7 +385,7 @@ func GetBlockOperationsByLinked(st *storage.LevelDBBackend, hash string, options
 	return LoadBlockOperationsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockOperationsBySourceAndType(st *storage.LevelDBBackend, source string, ty operation.OperationType, options storage.ListOptions) (
+func GetBlockOperationsBySourceAndType(st storage.Backend, source string, ty operation.OperationType, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -393,7 +393,7 @@ func GetBlockOperationsBySourceAndType(st *storage.LevelDBBackend, source string
 	return LoadBlockOperationsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockOperationsByTarget(st *storage.LevelDBBackend, target string, options storage.ListOptions) (
+func GetBlockOperationsByTarget(st storage.Backend, target string, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -402,7 +402,7 @@ func GetBlockOperationsByTarget(st *storage.LevelDBBackend, target string, optio
 	return LoadBlockOperationsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockOperationsByTargetAndType(st *storage.LevelDBBackend, target string, ty operation.OperationType, options storage.ListOptions) (
+func GetBlockOperationsByTargetAndType(st storage.Backend, target string, ty operation.OperationType, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -410,7 +410,7 @@ func GetBlockOperationsByTargetAndType(st *storage.LevelDBBackend, target string
 	return LoadBlockOperationsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockOperationsByPeers(st *storage.LevelDBBackend, addr string, options storage.ListOptions) (
+func GetBlockOperationsByPeers(st storage.Backend, addr string, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -419,7 +419,7 @@ func GetBlockOperationsByPeers(st *storage.LevelDBBackend, addr string, options
 	return LoadBlockOperationsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockOperationsByPeersAndType(st *storage.LevelDBBackend, addr string, ty operation.OperationType, options storage.ListOptions) (
+func GetBlockOperationsByPeersAndType(st storage.Backend, addr string, ty operation.OperationType, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
@@ -427,7 +427,7 @@ func GetBlockOperationsByPeersAndType(st *storage.LevelDBBackend, addr string, t
 	return LoadBlockOperationsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockOperationsByBlockHeight(st *storage.LevelDBBackend, height uint64, options storage.ListOptions) (
+func GetBlockOperationsByBlockHeight(st storage.Backend, height uint64, options storage.ListOptions) (
 	func() (BlockOperation, bool, []byte),
 	func(),
 ) {
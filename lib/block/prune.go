@@ -0,0 +1,265 @@
+package block
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// PruneBlock removes the transactions of block, their operations, the
+// indices of them and `TransactionPool`; the block itself and the accounts are
+// kept. The removed transaction leaves the mark, so it can be known as pruned
+// by `IsPrunedBlockTransaction`.
+//
+// The transaction, which has the operation related to the frozen account, is
+// not pruned, because unfreezing is validated with its operations. The
+// transaction of `UpdateValidators` is also kept for the light client; see
+// `ValidatorSetChangeProof`.
+func PruneBlock(st storage.Backend, blk Block) (err error) {
+	hashes := blk.Transactions
+	if len(blk.ProposerTransaction) > 0 {
+		hashes = append([]string{blk.ProposerTransaction}, hashes...)
+	}
+
+	for _, hash := range hashes {
+		if err = pruneBlockTransaction(st, blk, hash); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func pruneBlockTransaction(st storage.Backend, blk Block, hash string) (err error) {
+	var bt BlockTransaction
+	if bt, err = GetBlockTransaction(st, hash); err != nil {
+		if err == errors.StorageRecordDoesNotExist {
+			err = nil
+		}
+		return
+	}
+	bt.blockHeight = blk.Height
+
+	var tx transaction.Transaction
+	var tp TransactionPool
+	if tp, err = GetTransactionPool(st, hash); err == nil {
+		tx = tp.Transaction()
+		bt.transaction = tx
+	} else if err != errors.StorageRecordDoesNotExist {
+		return
+	}
+	err = nil
+
+	var bos []BlockOperation
+	for _, opHash := range bt.Operations {
+		var bo BlockOperation
+		if bo, err = GetBlockOperation(st, opHash); err != nil {
+			if err == errors.StorageRecordDoesNotExist {
+				err = nil
+				continue
+			}
+			return
+		}
+
+		var keep bool
+		if keep, err = isKeptBlockOperation(st, bo); err != nil || keep {
+			return
+		}
+		bos = append(bos, bo)
+	}
+
+	for _, bo := range bos {
+		if err = pruneBlockOperation(st, bo); err != nil {
+			return
+		}
+	}
+
+	accounts := []string{bt.Source}
+	for _, bo := range bos {
+		if bo.hasTarget() {
+			accounts = append(accounts, bo.Target)
+		}
+	}
+
+	prefixes := []string{
+		GetBlockTransactionKeyPrefixSource(bt.Source) + heightKey(blk.Height),
+		GetBlockTransactionKeyPrefixConfirmed(bt.Confirmed),
+		GetBlockTransactionKeyPrefixBlock(bt.Block),
+	}
+	for _, address := range accounts {
+		prefixes = append(prefixes, GetBlockTransactionKeyPrefixAccount(address)+heightKey(blk.Height))
+	}
+	if !tx.IsEmpty() {
+		if memo := tx.B.GetMemo(); !memo.IsEmpty() {
+			for _, address := range bt.memoAccounts() {
+				prefixes = append(
+					prefixes,
+					GetBlockTransactionKeyPrefixMemo(address, memo)+heightKey(blk.Height),
+				)
+			}
+		}
+	}
+
+	for _, prefix := range prefixes {
+		if err = removeIndices(st, prefix, hash); err != nil {
+			return
+		}
+	}
+
+	if err = removeKey(st, GetBlockTransactionKey(hash)); err != nil {
+		return
+	}
+	if err = removeKey(st, GetTransactionPoolKey(hash)); err != nil {
+		return
+	}
+
+	return st.New(GetPrunedBlockTransactionKey(hash), blk.Height)
+}
+
+// isKeptBlockOperation checks the operation is used to validate the
+// unfreezing, creating frozen account and unfreezing request, or to follow the
+// validator set changes.
+func isKeptBlockOperation(st storage.Backend, bo BlockOperation) (bool, error) {
+	switch bo.Type {
+	case operation.TypeUnfreezingRequest, operation.TypeUpdateValidators:
+		return true, nil
+	case operation.TypeCreateAccount:
+		return st.Has(GetBlockOperationCreateFrozenKey(bo.Target, bo.Height))
+	default:
+		return false, nil
+	}
+}
+
+func pruneBlockOperation(st storage.Backend, bo BlockOperation) (err error) {
+	height := heightKey(bo.Height)
+
+	prefixes := []string{
+		keyPrefixTxHash(bo.TxHash),
+		keyPrefixSource(bo.Source) + height,
+		keyPrefixSourceAndType(bo.Source, bo.Type) + height,
+		keyPrefixPeers(bo.Source) + height,
+		keyPrefixPeersAndType(bo.Source, bo.Type) + height,
+		keyPrefixBlockHeight(bo.Height),
+	}
+	if bo.hasTarget() {
+		prefixes = append(
+			prefixes,
+			keyPrefixTarget(bo.Target)+height,
+			keyPrefixTargetAndType(bo.Target, bo.Type)+height,
+			keyPrefixPeers(bo.Target)+height,
+			keyPrefixPeersAndType(bo.Target, bo.Type)+height,
+		)
+	}
+
+	for _, prefix := range prefixes {
+		if err = removeIndices(st, prefix, bo.Hash); err != nil {
+			return
+		}
+	}
+
+	return removeKey(st, key(bo.Hash))
+}
+
+func heightKey(height uint64) string {
+	return fmt.Sprintf("%s", common.EncodeUint64ToByteSlice(height))
+}
+
+// removeIndices removes the items under the prefix, which have the given
+// hash as value.
+func removeIndices(st storage.Backend, prefix, hash string) (err error) {
+	var keys []string
+
+	iterFunc, closeFunc := st.GetIterator(prefix, nil)
+	for {
+		item, hasNext := iterFunc()
+		if !hasNext {
+			break
+		}
+
+		var value string
+		if json.Unmarshal(item.Value, &value) != nil || value != hash {
+			continue
+		}
+		keys = append(keys, string(item.Key))
+	}
+	closeFunc()
+
+	for _, k := range keys {
+		if err = removeKey(st, k); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// removeKey removes the key; the missing key is ignored.
+func removeKey(st storage.Backend, k string) error {
+	if err := st.Remove(k); err != nil && err != errors.StorageRecordDoesNotExist {
+		return err
+	}
+
+	return nil
+}
+
+func GetPrunedBlockTransactionKey(hash string) string {
+	return fmt.Sprintf("%s%s", common.BlockTransactionPrefixPruned, hash)
+}
+
+// IsPrunedBlockTransaction checks the transaction was confirmed, but pruned
+// by `PruneBlock`.
+func IsPrunedBlockTransaction(st storage.Backend, hash string) (bool, error) {
+	return st.Has(GetPrunedBlockTransactionKey(hash))
+}
+
+// IsConfirmedBlockTransaction checks the transaction was confirmed; unlike
+// `ExistsBlockTransaction`, the pruned transaction is also confirmed, so it
+// is used to find the known transaction.
+func IsConfirmedBlockTransaction(st storage.Backend, hash string) (bool, error) {
+	if exists, err := ExistsBlockTransaction(st, hash); err != nil || exists {
+		return exists, err
+	}
+
+	return IsPrunedBlockTransaction(st, hash)
+}
+
+func getPrunedBlockHeightKey() string {
+	return fmt.Sprintf("%s-last-pruned-block", common.InternalPrefix)
+}
+
+// GetPrunedBlockHeight returns the height of the last pruned block; if
+// nothing pruned, it is 0.
+func GetPrunedBlockHeight(st storage.Backend) (height uint64, err error) {
+	if err = st.Get(getPrunedBlockHeightKey(), &height); err == errors.StorageRecordDoesNotExist {
+		err = nil
+	}
+
+	return
+}
+
+func SavePrunedBlockHeight(st storage.Backend, height uint64) (err error) {
+	var exists bool
+	if exists, err = st.Has(getPrunedBlockHeightKey()); err != nil {
+		return
+	} else if exists {
+		return st.Set(getPrunedBlockHeightKey(), height)
+	}
+
+	return st.New(getPrunedBlockHeightKey(), height)
+}
+
+// GetLowestFullBlockHeight returns the height of the lowest block, which
+// still has its transactions and operations.
+func GetLowestFullBlockHeight(st storage.Backend) uint64 {
+	height, err := GetPrunedBlockHeight(st)
+	if err != nil || height < common.GenesisBlockHeight {
+		return common.GenesisBlockHeight
+	}
+
+	return height + 1
+}
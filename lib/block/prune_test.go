@@ -0,0 +1,191 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestPruneBlock(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	kp := keypair.Random()
+	kpTarget := keypair.Random()
+
+	opHashes := map[string][]string{}
+	saveBlock := func(blk Block, txs []transaction.Transaction) {
+		blk.MustSave(st)
+		for _, tx := range txs {
+			bt := NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+			bt.MustSave(st)
+			require.NoError(t, bt.SaveBlockOperations(st))
+			_, err := SaveTransactionPool(st, tx)
+			require.NoError(t, err)
+			opHashes[tx.GetHash()] = bt.Operations
+		}
+	}
+
+	var prunedTxs, keptTxs []transaction.Transaction
+	var prunedHashes, keptHashes []string
+	for i := 0; i < 3; i++ {
+		tx := transaction.TestMakeTransactionWithKeypair(conf.NetworkID, 1, kp, kpTarget)
+		prunedTxs = append(prunedTxs, tx)
+		prunedHashes = append(prunedHashes, tx.GetHash())
+	}
+	for i := 0; i < 3; i++ {
+		tx := transaction.TestMakeTransactionWithKeypair(conf.NetworkID, 1, kp, kpTarget)
+		keptTxs = append(keptTxs, tx)
+		keptHashes = append(keptHashes, tx.GetHash())
+	}
+
+	prunedBlock := TestMakeNewBlock(prunedHashes)
+	saveBlock(prunedBlock, prunedTxs)
+	keptBlock := TestMakeNewBlockWithPrevBlock(prunedBlock, keptHashes)
+	saveBlock(keptBlock, keptTxs)
+
+	require.NoError(t, PruneBlock(st, prunedBlock))
+
+	// block itself is kept
+	exists, err := ExistsBlock(st, prunedBlock.Hash)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	for _, tx := range prunedTxs {
+		exists, err := ExistsBlockTransaction(st, tx.GetHash())
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		exists, err = ExistsTransactionPool(st, tx.GetHash())
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		pruned, err := IsPrunedBlockTransaction(st, tx.GetHash())
+		require.NoError(t, err)
+		require.True(t, pruned)
+
+		// the pruned transaction is still known as confirmed
+		confirmed, err := IsConfirmedBlockTransaction(st, tx.GetHash())
+		require.NoError(t, err)
+		require.True(t, confirmed)
+
+		require.NotEmpty(t, opHashes[tx.GetHash()])
+		for _, opHash := range opHashes[tx.GetHash()] {
+			exists, err := ExistsBlockOperation(st, opHash)
+			require.NoError(t, err)
+			require.False(t, exists)
+		}
+	}
+
+	for _, tx := range keptTxs {
+		exists, err := ExistsBlockTransaction(st, tx.GetHash())
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		pruned, err := IsPrunedBlockTransaction(st, tx.GetHash())
+		require.NoError(t, err)
+		require.False(t, pruned)
+
+		confirmed, err := IsConfirmedBlockTransaction(st, tx.GetHash())
+		require.NoError(t, err)
+		require.True(t, confirmed)
+	}
+
+	{ // indices only have the kept transactions
+		for _, address := range []string{kp.Address(), kpTarget.Address()} {
+			var hashes []string
+			iterFunc, closeFunc := GetBlockTransactionsByAccount(st, address, nil)
+			for {
+				bt, hasNext, _ := iterFunc()
+				if !hasNext {
+					break
+				}
+				hashes = append(hashes, bt.Hash)
+			}
+			closeFunc()
+			require.Equal(t, keptHashes, hashes)
+		}
+
+		var hashes []string
+		iterFunc, closeFunc := GetBlockTransactionsBySource(st, kp.Address(), nil)
+		for {
+			bt, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			hashes = append(hashes, bt.Hash)
+		}
+		closeFunc()
+		require.Equal(t, keptHashes, hashes)
+
+		var txHashes []string
+		opIterFunc, opCloseFunc := GetBlockOperationsBySource(st, kp.Address(), nil)
+		for {
+			bo, hasNext, _ := opIterFunc()
+			if !hasNext {
+				break
+			}
+			txHashes = append(txHashes, bo.TxHash)
+		}
+		opCloseFunc()
+		require.Equal(t, keptHashes, txHashes)
+	}
+}
+
+// In TestPruneBlockUpdateValidators test, the transaction of
+// `UpdateValidators` is kept for the light client.
+func TestPruneBlockUpdateValidators(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	kp := keypair.Random()
+	op, err := operation.NewOperation(operation.NewUpdateValidators(10, []string{keypair.Random().Address()}, []string{"https://localhost:12345"}, nil))
+	require.NoError(t, err)
+	tx, err := transaction.NewTransaction(kp.Address(), 0, op)
+	require.NoError(t, err)
+	tx.Sign(kp, conf.NetworkID)
+
+	blk := TestMakeNewBlock([]string{tx.GetHash()})
+	blk.MustSave(st)
+	bt := NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+	bt.MustSave(st)
+	require.NoError(t, bt.SaveBlockOperations(st))
+	_, err = SaveTransactionPool(st, tx)
+	require.NoError(t, err)
+
+	require.NoError(t, PruneBlock(st, blk))
+
+	exists, err := ExistsBlockTransaction(st, tx.GetHash())
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = ExistsTransactionPool(st, tx.GetHash())
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestPrunedBlockHeight(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	height, err := GetPrunedBlockHeight(st)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), height)
+	require.Equal(t, common.GenesisBlockHeight, GetLowestFullBlockHeight(st))
+
+	require.NoError(t, SavePrunedBlockHeight(st, 10))
+	require.NoError(t, SavePrunedBlockHeight(st, 11))
+
+	height, err = GetPrunedBlockHeight(st)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), height)
+	require.Equal(t, uint64(12), GetLowestFullBlockHeight(st))
+}
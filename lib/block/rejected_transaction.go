@@ -0,0 +1,88 @@
+package block
+
+import (
+	"time"
+
+	"github.com/hashicorp/golang-lru"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction"
+)
+
+// RejectedTransaction keeps the reason of the rejected transaction with the
+// result of each operation, so the client can find which operation is
+// invalid.
+type RejectedTransaction struct {
+	Hash       string                        `json:"hash"`
+	Code       uint                          `json:"code"`
+	Message    string                        `json:"message"`
+	Operations []transaction.OperationResult `json:"operations"`
+	Rejected   string                        `json:"rejected"`
+}
+
+func NewRejectedTransaction(hash string, err error, results []transaction.OperationResult) RejectedTransaction {
+	rt := RejectedTransaction{
+		Hash:       hash,
+		Operations: results,
+		Rejected:   common.NowISO8601(),
+	}
+	if e, ok := err.(*errors.Error); ok {
+		rt.Code = e.Code
+		rt.Message = e.Message
+	} else {
+		rt.Code = errors.InvalidOperation.Code
+		rt.Message = err.Error()
+	}
+
+	return rt
+}
+
+// RejectedTransactions keeps the recently rejected transactions in memory.
+// The rejected transactions are not stored in the storage, because anyone can
+// submit the invalid transactions; the number of records is limited and the
+// record is expired after the ttl.
+type RejectedTransactions struct {
+	cache *lru.Cache
+	ttl   time.Duration
+}
+
+func NewRejectedTransactions(size int, ttl time.Duration) *RejectedTransactions {
+	cache, err := lru.New(size)
+	if err != nil {
+		panic(err)
+	}
+
+	return &RejectedTransactions{cache: cache, ttl: ttl}
+}
+
+// Add keeps `RejectedTransaction`; the same transaction can be rejected again
+// by the different reason, so the previous one is overwritten.
+func (r *RejectedTransactions) Add(rt RejectedTransaction) {
+	if r == nil {
+		return
+	}
+
+	r.cache.Add(rt.Hash, rt)
+}
+
+// Get returns the `RejectedTransaction`, which is not expired.
+func (r *RejectedTransactions) Get(hash string) (rt RejectedTransaction, found bool) {
+	if r == nil {
+		return
+	}
+
+	var v interface{}
+	if v, found = r.cache.Get(hash); !found {
+		return
+	}
+	rt = v.(RejectedTransaction)
+
+	rejected, err := common.ParseISO8601(rt.Rejected)
+	if err != nil || time.Since(rejected) > r.ttl {
+		r.cache.Remove(hash)
+		return RejectedTransaction{}, false
+	}
+
+	return
+}
@@ -0,0 +1,70 @@
+package block
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestRejectedTransactions(t *testing.T) {
+	op, _ := operation.NewOperation(operation.NewPayment(keypair.Random().Address(), common.Amount(1)))
+	results := []transaction.OperationResult{
+		transaction.NewOperationResult(0, op, nil),
+		transaction.NewOperationResult(1, op, errors.BlockAccountDoesNotExists),
+	}
+
+	rts := NewRejectedTransactions(2, time.Hour)
+
+	hash := "showme"
+	_, found := rts.Get(hash)
+	require.False(t, found)
+
+	rt := NewRejectedTransaction(hash, errors.BlockAccountDoesNotExists, results)
+	rts.Add(rt)
+
+	fetched, found := rts.Get(hash)
+	require.True(t, found)
+	require.Equal(t, rt, fetched)
+
+	{ // rejected again
+		rts.Add(NewRejectedTransaction(hash, errors.BlockAccountInsufficientReserve, results[:1]))
+
+		fetched, found := rts.Get(hash)
+		require.True(t, found)
+		require.Equal(t, errors.BlockAccountInsufficientReserve.Code, fetched.Code)
+		require.Equal(t, 1, len(fetched.Operations))
+	}
+
+	{ // the number of rejected transactions is limited
+		rts.Add(NewRejectedTransaction("findme", errors.BlockAccountDoesNotExists, results))
+		rts.Add(NewRejectedTransaction("killme", errors.BlockAccountDoesNotExists, results))
+
+		_, found := rts.Get(hash)
+		require.False(t, found)
+		_, found = rts.Get("killme")
+		require.True(t, found)
+	}
+
+	{ // expired
+		rt := NewRejectedTransaction("expired", errors.BlockAccountDoesNotExists, results)
+		rt.Rejected = common.FormatISO8601(time.Now().Add(-2 * time.Hour))
+		rts.Add(rt)
+
+		_, found := rts.Get("expired")
+		require.False(t, found)
+	}
+
+	{ // nil does nothing
+		var rts *RejectedTransactions
+		rts.Add(rt)
+		_, found := rts.Get(hash)
+		require.False(t, found)
+	}
+}
@@ -20,6 +20,18 @@ func TestMakeBlockAccount(kps ...*keypair.Full) *BlockAccount {
 	return NewBlockAccount(address, balance)
 }
 
+// SetTestBlockVersionHeights activates `BlockVersionV1` and `BlockVersionV2`
+// from the given heights; the returned function restores the previous
+// heights.
+func SetTestBlockVersionHeights(v1, v2 uint64) func() {
+	previousV1, previousV2 := common.BlockVersionV1Height, common.BlockVersionV2Height
+	common.BlockVersionV1Height, common.BlockVersionV2Height = v1, v2
+
+	return func() {
+		common.BlockVersionV1Height, common.BlockVersionV2Height = previousV1, previousV2
+	}
+}
+
 var (
 	GenesisKP *keypair.Full
 	CommonKP  *keypair.Full
@@ -42,7 +54,7 @@ func init() {
 // Params:
 //   st = Storage to write the blockchain to
 //
-func MakeTestBlockchain(st *storage.LevelDBBackend) {
+func MakeTestBlockchain(st storage.Backend) {
 	conf := common.NewTestConfig()
 	balance := conf.InitialBalance
 	genesisAccount := NewBlockAccount(GenesisKP.Address(), balance)
@@ -61,35 +73,35 @@ func MakeTestBlockchain(st *storage.LevelDBBackend) {
 }
 
 // Like `MakeTestBlockchain`, but also create a storage
-func InitTestBlockchain() *storage.LevelDBBackend {
+func InitTestBlockchain() storage.Backend {
 	st := storage.NewTestStorage()
 	MakeTestBlockchain(st)
 	return st
 }
 
 /// Version of `Block.Save` that panics on error, usable only in tests
-func (b *Block) MustSave(st *storage.LevelDBBackend) {
+func (b *Block) MustSave(st storage.Backend) {
 	if err := b.Save(st); err != nil {
 		panic(err)
 	}
 }
 
 /// Version of `BlockAccount.Save` that panics on error, usable only in tests
-func (b *BlockAccount) MustSave(st *storage.LevelDBBackend) {
+func (b *BlockAccount) MustSave(st storage.Backend) {
 	if err := b.Save(st); err != nil {
 		panic(err)
 	}
 }
 
 /// Version of `BlockTransaction.Save` that panics on error, usable only in tests
-func (b *BlockTransaction) MustSave(st *storage.LevelDBBackend) {
+func (b *BlockTransaction) MustSave(st storage.Backend) {
 	if err := b.Save(st); err != nil {
 		panic(err)
 	}
 }
 
 /// Version of `BlockTransaction.Save` that panics on error, usable only in tests
-func (b *BlockOperation) MustSave(st *storage.LevelDBBackend) {
+func (b *BlockOperation) MustSave(st storage.Backend) {
 	if err := b.Save(st); err != nil {
 		panic(err)
 	}
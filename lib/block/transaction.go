@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/btcsuite/btcutil/base58"
+
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/storage"
@@ -19,6 +21,7 @@ import (
 //  * get list by `Confirmed` order
 //  * get list by `Account` and created order
 //  * get list by `Block` and created order
+//  * get list by `Account` and `Memo` and created order
 
 // TODO(BlockTransaction): support counting
 
@@ -103,7 +106,35 @@ func (bt BlockTransaction) NewBlockTransactionKeyByBlock(hash string) string {
 	)
 }
 
-func (bt *BlockTransaction) Save(st *storage.LevelDBBackend) (err error) {
+func (bt BlockTransaction) NewBlockTransactionKeyByMemo(accountAddress string, memo transaction.Memo) string {
+	return fmt.Sprintf(
+		"%s%s%s%s",
+		GetBlockTransactionKeyPrefixMemo(accountAddress, memo),
+		common.EncodeUint64ToByteSlice(bt.blockHeight),
+		common.EncodeUint64ToByteSlice(bt.SequenceID),
+		common.GetUniqueIDFromUUID(),
+	)
+}
+
+// memoAccounts returns the accounts, which the memo of transaction is indexed
+// for; source and the targets of operations.
+func (bt BlockTransaction) memoAccounts() (accounts []string) {
+	accounts = append(accounts, bt.Source)
+	for _, op := range bt.Transaction().B.Operations {
+		pop, ok := op.B.(operation.Payable)
+		if !ok {
+			continue
+		}
+		if _, found := common.InStringArray(accounts, pop.TargetAddress()); found {
+			continue
+		}
+		accounts = append(accounts, pop.TargetAddress())
+	}
+
+	return
+}
+
+func (bt *BlockTransaction) Save(st storage.Backend) (err error) {
 	if bt.isSaved {
 		return errors.AlreadySaved
 	}
@@ -133,6 +164,13 @@ func (bt *BlockTransaction) Save(st *storage.LevelDBBackend) (err error) {
 	if err = st.New(bt.NewBlockTransactionKeyByBlock(bt.Block), bt.Hash); err != nil {
 		return
 	}
+	if memo := bt.Transaction().B.GetMemo(); !memo.IsEmpty() {
+		for _, address := range bt.memoAccounts() {
+			if err = st.New(bt.NewBlockTransactionKeyByMemo(address, memo), bt.Hash); err != nil {
+				return
+			}
+		}
+	}
 
 	bt.isSaved = true
 
@@ -164,7 +202,7 @@ func (bt BlockTransaction) Transaction() transaction.Transaction {
 	return bt.transaction
 }
 
-func (bt *BlockTransaction) SaveBlockOperations(st *storage.LevelDBBackend) (err error) {
+func (bt *BlockTransaction) SaveBlockOperations(st storage.Backend) (err error) {
 	if bt.Transaction().IsEmpty() {
 		return errors.FailedToSaveBlockOperaton
 	}
@@ -187,7 +225,7 @@ func (bt *BlockTransaction) SaveBlockOperations(st *storage.LevelDBBackend) (err
 	return nil
 }
 
-func (bt *BlockTransaction) SaveBlockOperation(st *storage.LevelDBBackend, op operation.Operation) (err error) {
+func (bt *BlockTransaction) SaveBlockOperation(st storage.Backend, op operation.Operation) (err error) {
 	if bt.blockHeight < 1 {
 		var blk Block
 		if blk, err = GetBlock(st, bt.Block); err != nil {
@@ -245,11 +283,24 @@ func GetBlockTransactionKeyPrefixBlock(hash string) string {
 	return fmt.Sprintf("%s%s-", common.BlockTransactionPrefixBlock, hash)
 }
 
+// GetBlockTransactionKeyPrefixMemo returns the key prefix of memo index. The
+// memo value is base58 encoded after the memo type, so the same value of
+// different memo types does not conflict.
+func GetBlockTransactionKeyPrefixMemo(accountAddress string, memo transaction.Memo) string {
+	return fmt.Sprintf(
+		"%s%s-%s-%s-",
+		common.BlockTransactionPrefixMemo,
+		accountAddress,
+		memo.Type.String(),
+		base58.Encode([]byte(memo.Value)),
+	)
+}
+
 func GetBlockTransactionKey(hash string) string {
 	return fmt.Sprintf("%s%s", common.BlockTransactionPrefixHash, hash)
 }
 
-func GetBlockTransaction(st *storage.LevelDBBackend, hash string) (bt BlockTransaction, err error) {
+func GetBlockTransaction(st storage.Backend, hash string) (bt BlockTransaction, err error) {
 	if err = st.Get(GetBlockTransactionKey(hash), &bt); err != nil {
 		return
 	}
@@ -258,12 +309,12 @@ func GetBlockTransaction(st *storage.LevelDBBackend, hash string) (bt BlockTrans
 	return
 }
 
-func ExistsBlockTransaction(st *storage.LevelDBBackend, hash string) (bool, error) {
+func ExistsBlockTransaction(st storage.Backend, hash string) (bool, error) {
 	return st.Has(GetBlockTransactionKey(hash))
 }
 
 func LoadBlockTransactionsInsideIterator(
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	iterFunc func() (storage.IterItem, bool),
 	closeFunc func(),
 ) (
@@ -291,7 +342,7 @@ func LoadBlockTransactionsInsideIterator(
 		})
 }
 
-func GetBlockTransactionsBySource(st *storage.LevelDBBackend, source string, options storage.ListOptions) (
+func GetBlockTransactionsBySource(st storage.Backend, source string, options storage.ListOptions) (
 	func() (BlockTransaction, bool, []byte),
 	func(),
 ) {
@@ -300,7 +351,7 @@ func GetBlockTransactionsBySource(st *storage.LevelDBBackend, source string, opt
 	return LoadBlockTransactionsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockTransactionsByConfirmed(st *storage.LevelDBBackend, options storage.ListOptions) (
+func GetBlockTransactionsByConfirmed(st storage.Backend, options storage.ListOptions) (
 	func() (BlockTransaction, bool, []byte),
 	func(),
 ) {
@@ -309,7 +360,7 @@ func GetBlockTransactionsByConfirmed(st *storage.LevelDBBackend, options storage
 	return LoadBlockTransactionsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockTransactionsByAccount(st *storage.LevelDBBackend, accountAddress string, options storage.ListOptions) (
+func GetBlockTransactionsByAccount(st storage.Backend, accountAddress string, options storage.ListOptions) (
 	func() (BlockTransaction, bool, []byte),
 	func(),
 ) {
@@ -317,7 +368,15 @@ func GetBlockTransactionsByAccount(st *storage.LevelDBBackend, accountAddress st
 	return LoadBlockTransactionsInsideIterator(st, iterFunc, closeFunc)
 }
 
-func GetBlockTransactionsByBlock(st *storage.LevelDBBackend, hash string, options storage.ListOptions) (
+func GetBlockTransactionsByAccountMemo(st storage.Backend, accountAddress string, memo transaction.Memo, options storage.ListOptions) (
+	func() (BlockTransaction, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(GetBlockTransactionKeyPrefixMemo(accountAddress, memo), options)
+	return LoadBlockTransactionsInsideIterator(st, iterFunc, closeFunc)
+}
+
+func GetBlockTransactionsByBlock(st storage.Backend, hash string, options storage.ListOptions) (
 	func() (BlockTransaction, bool, []byte),
 	func(),
 ) {
@@ -39,7 +39,7 @@ func GetTransactionPoolKey(hash string) string {
 	return fmt.Sprintf("%s%s", common.TransactionPoolPrefix, hash)
 }
 
-func (tp TransactionPool) Save(st *storage.LevelDBBackend) (err error) {
+func (tp TransactionPool) Save(st storage.Backend) (err error) {
 	key := GetTransactionPoolKey(tp.Hash)
 
 	var exists bool
@@ -85,20 +85,20 @@ func (tp TransactionPool) Transaction() transaction.Transaction {
 	return tp.transaction
 }
 
-func ExistsTransactionPool(st *storage.LevelDBBackend, hash string) (bool, error) {
+func ExistsTransactionPool(st storage.Backend, hash string) (bool, error) {
 	return st.Has(GetTransactionPoolKey(hash))
 }
 
-func GetTransactionPool(st *storage.LevelDBBackend, hash string) (tp TransactionPool, err error) {
+func GetTransactionPool(st storage.Backend, hash string) (tp TransactionPool, err error) {
 	err = st.Get(GetTransactionPoolKey(hash), &tp)
 	return
 }
 
-func DeleteTransactionPool(st *storage.LevelDBBackend, hash string) error {
+func DeleteTransactionPool(st storage.Backend, hash string) error {
 	return st.Remove(GetTransactionPoolKey(hash))
 }
 
-func SaveTransactionPool(st *storage.LevelDBBackend, tx transaction.Transaction) (tp TransactionPool, err error) {
+func SaveTransactionPool(st storage.Backend, tx transaction.Transaction) (tp TransactionPool, err error) {
 	if tp, err = NewTransactionPool(tx); err != nil {
 		return
 	}
@@ -325,6 +325,67 @@ func TestMultipleBlockTransactionGetByAccount(t *testing.T) {
 	}
 }
 
+func TestMultipleBlockTransactionGetByAccountMemo(t *testing.T) {
+	conf := common.NewTestConfig()
+	kp := keypair.Random()
+	kpAnother := keypair.Random()
+	st := storage.NewTestStorage()
+
+	memo := transaction.NewMemo(transaction.MemoText, "showme")
+	anotherMemo := transaction.NewMemo(transaction.MemoID, "100")
+
+	saveTxs := func(n int, memo *transaction.Memo, source *keypair.Full, targets ...*keypair.Full) (hashes []string) {
+		var txs []transaction.Transaction
+		for i := 0; i < n; i++ {
+			tx := transaction.TestMakeTransactionWithKeypair(conf.NetworkID, 1, source, targets...)
+			tx.B.Memo = memo
+			tx.Sign(source, conf.NetworkID)
+			txs = append(txs, tx)
+			hashes = append(hashes, tx.GetHash())
+		}
+
+		blk := TestMakeNewBlock(hashes)
+		for _, tx := range txs {
+			bt := NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+			bt.MustSave(st)
+		}
+		return
+	}
+
+	var expected []string
+	expected = append(expected, saveTxs(3, &memo, kp)...)
+	saveTxs(3, &anotherMemo, kp)
+	saveTxs(3, nil, kp)
+	// target is this keypair
+	targeted := saveTxs(3, &memo, kpAnother, kp)
+	expected = append(expected, targeted...)
+
+	getHashes := func(address string, memo transaction.Memo) (hashes []string) {
+		iterFunc, closeFunc := GetBlockTransactionsByAccountMemo(st, address, memo, nil)
+		for {
+			bt, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			hashes = append(hashes, bt.Hash)
+		}
+		closeFunc()
+		return
+	}
+
+	require.Equal(t, expected, getHashes(kp.Address(), memo))
+	require.Equal(t, 3, len(getHashes(kp.Address(), anotherMemo)))
+	require.Equal(t, targeted, getHashes(kpAnother.Address(), memo))
+	require.Equal(t, 0, len(getHashes(kpAnother.Address(), anotherMemo)))
+
+	// same value with the different memo type
+	require.Equal(t, 0, len(getHashes(kp.Address(), transaction.NewMemo(transaction.MemoText, anotherMemo.Value))))
+	textID := transaction.NewMemo(transaction.MemoText, "100")
+	textIDHashes := saveTxs(2, &textID, kp)
+	require.Equal(t, textIDHashes, getHashes(kp.Address(), textID))
+	require.Equal(t, 3, len(getHashes(kp.Address(), anotherMemo)))
+}
+
 func TestMultipleBlockTransactionGetByBlock(t *testing.T) {
 	conf := common.NewTestConfig()
 	kp := keypair.Random()
@@ -0,0 +1,163 @@
+package block
+
+import (
+	"fmt"
+	"sort"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// ValidatorSetChange is the validators, which are added and removed by
+// `UpdateValidators` operation from the block of `Height`. the storage should
+// support,
+//  * get list by `Height` order
+//
+// models
+//  * 'height'
+// 	- 'vsc-<ValidatorSetChange.Height>-<ValidatorSetChange.Hash>': `ValidatorSetChange`
+
+type ValidatorSetChange struct {
+	Hash      string   `json:"hash"` // hash of `UpdateValidators` operation
+	Height    uint64   `json:"height"`
+	Add       []string `json:"add"`
+	Endpoints []string `json:"endpoints"` // endpoints of `Add`
+	Remove    []string `json:"remove"`
+}
+
+func NewValidatorSetChange(hash string, height uint64, add, endpoints, remove []string) *ValidatorSetChange {
+	return &ValidatorSetChange{
+		Hash:      hash,
+		Height:    height,
+		Add:       add,
+		Endpoints: endpoints,
+		Remove:    remove,
+	}
+}
+
+func (c *ValidatorSetChange) String() string {
+	return string(common.MustMarshalJSON(c))
+}
+
+// ValidatorSetChangeProof is the transaction, which has `UpdateValidators`
+// operations, with the Merkle proof in the block of `Block`; the light client
+// follows the validator set changes by it with the block headers. The block
+// before `BlockVersionV1` does not have the Merkle proof.
+type ValidatorSetChangeProof struct {
+	Block       string                  `json:"block"`
+	Transaction transaction.Transaction `json:"transaction"`
+	Proof       common.MerkleProof      `json:"proof"`
+}
+
+// Changes returns the `ValidatorSetChange`s of the `UpdateValidators`
+// operations in the transaction.
+func (p ValidatorSetChangeProof) Changes() (changes []*ValidatorSetChange) {
+	for _, op := range p.Transaction.B.Operations {
+		opb, ok := op.B.(operation.UpdateValidators)
+		if !ok {
+			continue
+		}
+		changes = append(
+			changes,
+			NewValidatorSetChange(common.MustMakeObjectHashString(op), opb.Height, opb.Add, opb.Endpoints, opb.Remove),
+		)
+	}
+
+	return
+}
+
+func (c *ValidatorSetChange) Save(st storage.Backend) (err error) {
+	return st.New(GetValidatorSetChangeKey(c.Height, c.Hash), c)
+}
+
+func GetValidatorSetChangeKey(height uint64, hash string) string {
+	return fmt.Sprintf("%s%020d-%s", common.ValidatorSetChangePrefixHeight, height, hash)
+}
+
+func GetValidatorSetChanges(st storage.Backend, options storage.ListOptions) (
+	func() (*ValidatorSetChange, bool, []byte),
+	func(),
+) {
+	iterFunc, closeFunc := st.GetIterator(common.ValidatorSetChangePrefixHeight, options)
+
+	return (func() (*ValidatorSetChange, bool, []byte) {
+			item, hasNext := iterFunc()
+			if !hasNext {
+				return &ValidatorSetChange{}, false, item.Key
+			}
+
+			var c ValidatorSetChange
+			common.MustUnmarshalJSON(item.Value, &c)
+			return &c, hasNext, item.Key
+		}), (func() {
+			closeFunc()
+		})
+}
+
+// GetValidatorSetChangesUntil returns the `ValidatorSetChange`s, which take
+// effect until the given height, in the order of height.
+func GetValidatorSetChangesUntil(st storage.Backend, height uint64) (changes []*ValidatorSetChange) {
+	iterFunc, closeFunc := GetValidatorSetChanges(st, nil)
+	defer closeFunc()
+
+	for {
+		c, hasNext, _ := iterFunc()
+		if !hasNext || c.Height > height {
+			break
+		}
+		changes = append(changes, c)
+	}
+
+	return
+}
+
+// GetValidatorsAt returns the addresses of validators, which vote the block of
+// the given height; the `ValidatorSetChange`s until the height are applied to
+// the initial validators.
+func GetValidatorsAt(st storage.Backend, initial []string, height uint64) []string {
+	return ApplyValidatorSetChanges(initial, GetValidatorSetChangesUntil(st, height))
+}
+
+// ApplyValidatorSetChanges returns the addresses of validators, which the
+// given `ValidatorSetChange`s are applied to the initial validators in order.
+func ApplyValidatorSetChanges(initial []string, changes []*ValidatorSetChange) []string {
+	validators := map[string]bool{}
+	for _, address := range initial {
+		validators[address] = true
+	}
+
+	for _, c := range changes {
+		for _, address := range c.Remove {
+			delete(validators, address)
+		}
+		for _, address := range c.Add {
+			validators[address] = true
+		}
+	}
+
+	var addresses []string
+	for address := range validators {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+// GetValidatorEndpointsAt returns the endpoints of the validators, which are
+// added by the `ValidatorSetChange`s until the given height; the endpoint of
+// the latest change is used.
+func GetValidatorEndpointsAt(st storage.Backend, height uint64) map[string]string {
+	endpoints := map[string]string{}
+	for _, c := range GetValidatorSetChangesUntil(st, height) {
+		for i, address := range c.Add {
+			if i < len(c.Endpoints) {
+				endpoints[address] = c.Endpoints[i]
+			}
+		}
+	}
+
+	return endpoints
+}
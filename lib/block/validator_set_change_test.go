@@ -0,0 +1,42 @@
+package block
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage"
+)
+
+func TestValidatorSetChangesUntil(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	var changes []*ValidatorSetChange
+	for _, height := range []uint64{20, 3, 10} {
+		c := NewValidatorSetChange(
+			keypair.Random().Address(), // as hash
+			height,
+			[]string{keypair.Random().Address()},
+			[]string{"https://localhost:12345"},
+			nil,
+		)
+		require.NoError(t, c.Save(st))
+		changes = append(changes, c)
+	}
+
+	require.Equal(t, 0, len(GetValidatorSetChangesUntil(st, 2)))
+
+	fetched := GetValidatorSetChangesUntil(st, 10)
+	require.Equal(t, 2, len(fetched))
+	require.Equal(t, changes[1], fetched[0])
+	require.Equal(t, changes[2], fetched[1])
+
+	fetched = GetValidatorSetChangesUntil(st, 100)
+	require.Equal(t, 3, len(fetched))
+	require.Equal(t, changes[0], fetched[2])
+
+	// same change can not be saved again
+	require.Error(t, changes[0].Save(st))
+}
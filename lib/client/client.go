@@ -22,10 +22,14 @@ const (
 	UrlAccount               = "/accounts/{id}"
 	UrlAccountOperations     = "/accounts/{id}/operations"
 	UrlAccountFrozenAccounts = "/accounts/{id}/frozen-accounts"
+	UrlAccountData           = "/accounts/{id}/data"
+	UrlAccountDataByKey      = "/accounts/{id}/data/{key}"
 	UrlFrozenAccounts        = "/frozen-accounts"
 	UrlTransactions          = "/transactions"
 	UrlTransactionByHash     = "/transactions/{id}"
 	UrlTransactionStatus     = "/transactions/{id}/status"
+	UrlTransactionProof      = "/transactions/{id}/proof"
+	UrlAccountProof          = "/accounts/{id}/proof"
 	UrlTransactionOperations = "/transactions/{id}/operations"
 	UrlSubscribe             = "/subscribe"
 )
@@ -41,6 +45,7 @@ const (
 	QueryOrder  QueryKey = "reverse"
 	QueryCursor QueryKey = "cursor"
 	QueryType   QueryKey = "type"
+	QueryHeight QueryKey = "height"
 )
 
 type Q struct {
@@ -65,6 +70,8 @@ func (qs Queries) toQueryString() string {
 			urlValues.Add(QueryCursor.String(), q.Value)
 		case QueryType:
 			urlValues.Add(QueryType.String(), q.Value)
+		case QueryHeight:
+			urlValues.Add(QueryHeight.String(), q.Value)
 
 		}
 	}
@@ -187,6 +194,21 @@ func (c *Client) LoadAccount(id string, queries ...Q) (account Account, err erro
 	return
 }
 
+func (c *Client) LoadAccountData(id string, queries ...Q) (dPage AccountDataPage, err error) {
+	url := strings.Replace(UrlAccountData, "{id}", id, -1)
+	url += Queries(queries).toQueryString()
+	err = c.getResponse(url, http.Header{}, &dPage)
+	return
+}
+
+func (c *Client) LoadAccountDataByKey(id, key string, queries ...Q) (data AccountData, err error) {
+	url := strings.Replace(UrlAccountDataByKey, "{id}", id, -1)
+	url = strings.Replace(url, "{key}", neturl.PathEscape(key), -1)
+	url += Queries(queries).toQueryString()
+	err = c.getResponse(url, http.Header{}, &data)
+	return
+}
+
 func (c *Client) LoadFrozenAccountsByLinked(id string, queries ...Q) (fPage FrozenAccountsPage, err error) {
 	url := strings.Replace(UrlAccountFrozenAccounts, "{id}", id, -1)
 	url += Queries(queries).toQueryString()
@@ -215,6 +237,20 @@ func (c *Client) LoadTransactionStatus(id string, queries ...Q) (transactionHist
 	return
 }
 
+func (c *Client) LoadTransactionProof(id string, queries ...Q) (proof TransactionProof, err error) {
+	url := strings.Replace(UrlTransactionProof, "{id}", id, -1)
+	url += Queries(queries).toQueryString()
+	err = c.getResponse(url, http.Header{}, &proof)
+	return
+}
+
+func (c *Client) LoadAccountProof(id string, queries ...Q) (proof AccountProof, err error) {
+	url := strings.Replace(UrlAccountProof, "{id}", id, -1)
+	url += Queries(queries).toQueryString()
+	err = c.getResponse(url, http.Header{}, &proof)
+	return
+}
+
 func (c *Client) LoadTransactions(queries ...Q) (tPage TransactionsPage, err error) {
 	url := UrlTransactions
 	url += Queries(queries).toQueryString()
@@ -256,11 +292,24 @@ func (c *Client) SubmitTransaction(tx []byte) (TransactionPostError, error) {
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
 	resp, err := c.Post(url, tx, headers)
-	defer resp.Body.Close()
 	if err != nil {
-		var data TransactionPostError
-		if err2 := c.ToResponse(resp, &data); err2 != nil {
-			return TransactionPostError{}, err2
+		return TransactionPostError{}, err
+	}
+
+	if err = c.ToResponse(resp, nil); err != nil {
+		e, ok := err.(Error)
+		if !ok {
+			return TransactionPostError{}, err
+		}
+
+		data := TransactionPostError{
+			Status:  "rejected",
+			Message: e.Problem.Title,
+		}
+		if raw, found := e.Problem.Extras["operations"]; found {
+			if err2 := json.Unmarshal(raw, &data.Operations); err2 != nil {
+				return TransactionPostError{}, err2
+			}
 		}
 		return data, err
 	}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/trieproof"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
 )
 
@@ -21,12 +22,45 @@ type Account struct {
 		Self         Link `json:"self"`
 		Transactions Link `json:"transactions"`
 		Operations   Link `json:"operations"`
+		Data         Link `json:"data"`
 	} `json:"_links"`
 
 	Address    string `json:"address"`
 	SequenceID uint64 `json:"sequence_id"`
 	Balance    string `json:"balance"`
 	Linked     string `json:"linked"`
+	Signers    []struct {
+		Address string `json:"address"`
+		Weight  uint32 `json:"weight"`
+	} `json:"signers"`
+	Thresholds struct {
+		Low    uint32 `json:"low"`
+		Medium uint32 `json:"medium"`
+		High   uint32 `json:"high"`
+	} `json:"thresholds"`
+	DataEntries uint64 `json:"data_entries"`
+}
+
+type AccountData struct {
+	Links struct {
+		Self    Link `json:"self"`
+		Account Link `json:"account"`
+	} `json:"_links"`
+
+	Address string `json:"address"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+}
+
+type AccountDataPage struct {
+	Links struct {
+		Self Link `json:"self"`
+		Next Link `json:"next"`
+		Prev Link `json:"prev"`
+	} `json:"_links"`
+	Embedded struct {
+		Records []AccountData `json:"records"`
+	} `json:"_embedded"`
 }
 
 type FrozenAccount struct {
@@ -73,6 +107,10 @@ type Transaction struct {
 	SequenceID     uint64 `json:"sequence_id"`
 	Created        string `json:"created"`
 	OperationCount uint64 `json:"operation_count"`
+	Memo           *struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"memo,omitempty"`
 }
 
 type TransactionPostError struct {
@@ -80,9 +118,19 @@ type TransactionPostError struct {
 		Self   Link `json:"self"`
 		Status Link `json:"status"`
 	} `json:"_links"`
-	Hash    string      `json:"hash"`
-	Status  string      `json:"status"`
-	Message interface{} `json:"message"`
+	Hash       string            `json:"hash"`
+	Status     string            `json:"status"`
+	Message    interface{}       `json:"message"`
+	Operations []OperationResult `json:"operations,omitempty"`
+}
+
+// OperationResult is the result of each operation of the rejected
+// transaction; `Code` is 0 if the operation is valid.
+type OperationResult struct {
+	Index   int    `json:"index"`
+	Type    string `json:"type"`
+	Code    uint   `json:"code"`
+	Message string `json:"message,omitempty"`
 }
 
 type TransactionStatus struct {
@@ -90,8 +138,88 @@ type TransactionStatus struct {
 		Self        Link `json:"self"`
 		Transaction Link `json:"transaction"`
 	} `json:"_links"`
-	Hash   string `json:"hash"`
-	Status string `json:"status"`
+	Hash       string            `json:"hash"`
+	Status     string            `json:"status"`
+	Code       uint              `json:"code,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	Operations []OperationResult `json:"operations,omitempty"`
+	Rejected   string            `json:"rejected,omitempty"`
+}
+
+type BlockHeader struct {
+	Version          uint32 `json:"version"`
+	PrevBlockHash    string `json:"prev_block_hash"`
+	TransactionsRoot string `json:"transactions_root"`
+	ProposedTime     string `json:"proposed_time"`
+	Height           uint64 `json:"height"`
+	TotalTxs         uint64 `json:"total-txs"`
+	TotalOps         uint64 `json:"total-ops"`
+	StateRoot        string `json:"state_root"`
+}
+
+// TransactionProof is the Merkle proof of the transaction in the block of
+// `Header`.
+type TransactionProof struct {
+	Links struct {
+		Self        Link `json:"self"`
+		Transaction Link `json:"transaction"`
+		Block       Link `json:"block"`
+	} `json:"_links"`
+	Hash   string             `json:"hash"`
+	Block  string             `json:"block"`
+	Header BlockHeader        `json:"header"`
+	Proof  common.MerkleProof `json:"proof"`
+}
+
+// Verify checks the transaction is included in the block of the given
+// `TransactionsRoot`. The root should be taken from the trusted block header,
+// like the header verified by `lightclient.LightClient`, not from the
+// response.
+func (p TransactionProof) Verify(transactionsRoot string) bool {
+	return p.Proof.Verify(transactionsRoot, p.Hash)
+}
+
+// AccountProof is the proof of the account in the state trie of the block of
+// `Header`.
+type AccountProof struct {
+	Links struct {
+		Self    Link `json:"self"`
+		Account Link `json:"account"`
+		Block   Link `json:"block"`
+	} `json:"_links"`
+	Address string      `json:"address"`
+	Block   string      `json:"block"`
+	Header  BlockHeader `json:"header"`
+	Account struct {
+		Address    string        `json:"address"`
+		Balance    common.Amount `json:"balance"`
+		SequenceID uint64        `json:"sequence_id"`
+	} `json:"account"`
+	Proof [][]byte `json:"proof"`
+}
+
+// Verify checks the account is in the state trie of the given block header.
+// Like `TransactionProof.Verify`, the header should be the trusted one, not
+// the header of response.
+func (p AccountProof) Verify(header BlockHeader) bool {
+	value, err := trieproof.VerifyProof(trieproof.DecodeRoot(header.StateRoot), []byte(p.Address), p.Proof)
+	if err != nil || len(value) < 1 {
+		return false
+	}
+
+	// the value is the stored record of account
+	var ba struct {
+		Address    string        `json:"address"`
+		Balance    common.Amount `json:"balance"`
+		SequenceID uint64        `json:"sequence_id"`
+	}
+	if err = json.Unmarshal(value, &ba); err != nil {
+		return false
+	}
+
+	return ba.Address == p.Account.Address &&
+		ba.Balance == p.Account.Balance &&
+		ba.SequenceID == p.Account.SequenceID
 }
 
 type TransactionsPage struct {
@@ -36,6 +36,10 @@ type Config struct {
 
 	JSONRPCEndpoint *Endpoint
 
+	// PruneBlocks is the number of latest blocks, which keep their
+	// transactions and operations; if 0, nothing is pruned.
+	PruneBlocks uint64
+
 	WatcherMode bool
 
 	DiscoveryEndpoints []*Endpoint
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"math"
 	"time"
 
 	"github.com/ulule/limiter"
@@ -57,6 +58,44 @@ const (
 	// `ProposerTransaction`.
 	DefaultOperationsInBallotLimit int = 10000
 
+	// RejectedTransactionsLimit is the maximum number of the rejected
+	// transactions, which are kept in memory.
+	RejectedTransactionsLimit int = 10000
+
+	// RejectedTransactionsTTL is how long the rejected transaction is kept.
+	RejectedTransactionsTTL time.Duration = time.Hour
+
+	// MaxSignersInAccount is the maximum number of signers, which can be set
+	// by `ManageSigners` operation.
+	MaxSignersInAccount int = 20
+
+	// MemoTextLimit is the maximum length of text memo of transaction in
+	// bytes.
+	MemoTextLimit int = 64
+
+	// DataEntryKeyLimit is the maximum length of the key of account data
+	// entry in bytes.
+	DataEntryKeyLimit int = 64
+
+	// DataEntryValueLimit is the maximum length of the value of account data
+	// entry in bytes.
+	DataEntryValueLimit int = 256
+
+	// MinimumPruneBlocks is the minimum number of latest blocks, which keep
+	// their transactions and operations in pruning mode; the consensus still
+	// reads the transactions of the recent blocks.
+	MinimumPruneBlocks uint64 = 100
+
+	// BaseFeeBlocks is the number of recent blocks, which decides the base
+	// fee by their fullness; see `CalculateBaseFee`. The fullness is counted
+	// with `DefaultOperationsInBallotLimit`, so every node gets the same base
+	// fee.
+	BaseFeeBlocks int = 10
+
+	// BaseFeeMaxMultiplier is the maximum multiplier of `BaseFee` when the
+	// recent blocks are full.
+	BaseFeeMaxMultiplier uint64 = 10
+
 	DefaultTimeoutINIT       = 2 * time.Second
 	DefaultTimeoutSIGN       = 2 * time.Second
 	DefaultTimeoutACCEPT     = 2 * time.Second
@@ -77,6 +116,24 @@ var (
 	// 14 (days) * 24 (hours) * 60 (minutes) * 12 (60 seconds / 5 seconds per block on average)
 	UnfreezingPeriod uint64 = 241920
 
+	// BlockVersionV1Height and BlockVersionV2Height are the heights of
+	// network, from which the blocks are made in `block.BlockVersionV1` and
+	// `block.BlockVersionV2`; see `block.GetBlockVersion`. They are set by the
+	// node flags and every node of network must have the same heights, see
+	// `node.NodePolicy.CheckNetworkParameters`. By default, the new versions
+	// are not activated, so the existing network keeps the blocks of
+	// `block.BlockVersionV0`; the network, which already has the blocks,
+	// must set them above its latest block.
+	BlockVersionV1Height uint64 = math.MaxUint64
+	BlockVersionV2Height uint64 = math.MaxUint64
+
+	// ProposerSelector is the name of proposer selector of network, one of
+	// "sequential", "reputation" and "random"; it is set by the node flag,
+	// but every node must select the same proposer, so it must be same in the
+	// network, see `node.NodePolicy.CheckNetworkParameters`. See
+	// `consensus.NewProposerSelector`.
+	ProposerSelector string = "sequential"
+
 	// BallotConfirmedTimeAllowDuration is the duration time for ballot from
 	// other nodes. If confirmed time of ballot has too late or ahead by
 	// BallotConfirmedTimeAllowDuration, it will be considered not-wellformed.
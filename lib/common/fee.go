@@ -0,0 +1,23 @@
+package common
+
+// CalculateBaseFee returns the minimum fee per operation from `ops`, the
+// number of operations in the recent `blocks` blocks. Until the blocks are
+// half full of `opsInBallotLimit`, it is `BaseFee`; over that, it increases
+// linearly up to `BaseFee * maxMultiplier` when the blocks are full.
+func CalculateBaseFee(ops uint64, blocks, opsInBallotLimit int, maxMultiplier uint64) Amount {
+	if blocks < 1 || opsInBallotLimit < 1 || maxMultiplier <= 1 {
+		return BaseFee
+	}
+
+	capacity := uint64(blocks) * uint64(opsInBallotLimit)
+	if ops*2 <= capacity {
+		return BaseFee
+	}
+
+	over := ops*2 - capacity
+	if over > capacity {
+		over = capacity
+	}
+
+	return BaseFee + Amount(uint64(BaseFee)*(maxMultiplier-1)*over/capacity)
+}
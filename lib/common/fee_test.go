@@ -0,0 +1,28 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateBaseFee(t *testing.T) {
+	cases := []struct {
+		ops      uint64
+		expected Amount
+	}{
+		{0, BaseFee},
+		{500, BaseFee},          // half full
+		{750, BaseFee * 11 / 2}, // half of max multiplier over the half full
+		{1000, BaseFee * 10},    // full
+		{1010, BaseFee * 10},    // with the operations of proposer transaction
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.expected, CalculateBaseFee(c.ops, 10, 100, 10), "ops=%d", c.ops)
+	}
+
+	// disabled
+	require.Equal(t, BaseFee, CalculateBaseFee(1000, 0, 100, 10))
+	require.Equal(t, BaseFee, CalculateBaseFee(1000, 10, 100, 1))
+}
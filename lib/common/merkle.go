@@ -0,0 +1,125 @@
+package common
+
+import (
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// The leaves and the nodes of Merkle tree are hashed with the different
+// prefixes, so the node can not be proved as the leaf.
+const (
+	merkleLeafPrefix byte = 0x00
+	merkleNodePrefix byte = 0x01
+)
+
+// MerkleProof is the path from the leaf to the root of Merkle tree. `Path` has
+// the sibling hashes from the bottom; if the node is the last one of the odd
+// number of nodes, it does not have the sibling and is promoted to the next
+// level.
+type MerkleProof struct {
+	Index  int      `json:"index"`  // index of leaf
+	Leaves int      `json:"leaves"` // number of leaves
+	Path   []string `json:"path"`   // base58 encoded sibling hashes
+}
+
+func merkleLeafHash(leaf string) []byte {
+	return MakeHash(append([]byte{merkleLeafPrefix}, []byte(leaf)...))
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	b := make([]byte, 0, 1+len(left)+len(right))
+	b = append(b, merkleNodePrefix)
+	b = append(b, left...)
+	b = append(b, right...)
+
+	return MakeHash(b)
+}
+
+// merkleLevels returns the hashes of every level of Merkle tree from the
+// leaves to the root.
+func merkleLevels(leaves []string) (levels [][][]byte) {
+	level := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = merkleLeafHash(leaf)
+	}
+	levels = append(levels, level)
+
+	for len(level) > 1 {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			if i+1 < len(level) {
+				next = append(next, merkleNodeHash(level[i], level[i+1]))
+			} else {
+				next = append(next, level[i])
+			}
+		}
+		levels = append(levels, next)
+		level = next
+	}
+
+	return
+}
+
+// MakeMerkleRoot returns the base58 encoded root of Merkle tree of the leaves.
+// If the leaves is empty, the root is empty.
+func MakeMerkleRoot(leaves []string) string {
+	if len(leaves) < 1 {
+		return ""
+	}
+
+	levels := merkleLevels(leaves)
+	return base58.Encode(levels[len(levels)-1][0])
+}
+
+// MakeMerkleProof returns the proof of the leaf at the index.
+func MakeMerkleProof(leaves []string, index int) (proof MerkleProof, found bool) {
+	if index < 0 || index >= len(leaves) {
+		return
+	}
+
+	proof = MerkleProof{Index: index, Leaves: len(leaves), Path: []string{}}
+
+	i := index
+	levels := merkleLevels(leaves)
+	for _, level := range levels[:len(levels)-1] {
+		if i%2 == 1 {
+			proof.Path = append(proof.Path, base58.Encode(level[i-1]))
+		} else if i+1 < len(level) {
+			proof.Path = append(proof.Path, base58.Encode(level[i+1]))
+		}
+		i /= 2
+	}
+
+	return proof, true
+}
+
+// Verify checks the leaf is included in the Merkle tree of the root.
+func (p MerkleProof) Verify(root, leaf string) bool {
+	if p.Index < 0 || p.Index >= p.Leaves {
+		return false
+	}
+
+	hash := merkleLeafHash(leaf)
+
+	var used int
+	i, n := p.Index, p.Leaves
+	for n > 1 {
+		if i%2 == 1 || i+1 < n {
+			if used >= len(p.Path) {
+				return false
+			}
+			sibling := base58.Decode(p.Path[used])
+			used++
+
+			if i%2 == 1 {
+				hash = merkleNodeHash(sibling, hash)
+			} else {
+				hash = merkleNodeHash(hash, sibling)
+			}
+		}
+
+		i /= 2
+		n = (n + 1) / 2
+	}
+
+	return used == len(p.Path) && base58.Encode(hash) == root
+}
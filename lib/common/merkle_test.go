@@ -0,0 +1,58 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleProof(t *testing.T) {
+	require.Equal(t, "", MakeMerkleRoot(nil))
+
+	for n := 1; n <= 9; n++ {
+		var leaves []string
+		for i := 0; i < n; i++ {
+			leaves = append(leaves, fmt.Sprintf("leaf-%d", i))
+		}
+		root := MakeMerkleRoot(leaves)
+
+		for i, leaf := range leaves {
+			proof, found := MakeMerkleProof(leaves, i)
+			require.True(t, found)
+			require.True(t, proof.Verify(root, leaf), "leaves=%d index=%d", n, i)
+
+			// different leaf
+			require.False(t, proof.Verify(root, "showme"))
+
+			// different index
+			if n > 1 {
+				wrong := proof
+				wrong.Index = (i + 1) % n
+				require.False(t, wrong.Verify(root, leaf), "leaves=%d index=%d", n, i)
+			}
+		}
+
+		_, found := MakeMerkleProof(leaves, n)
+		require.False(t, found)
+	}
+}
+
+func TestMerkleRoot(t *testing.T) {
+	leaves := []string{"a", "b", "c"}
+	root := MakeMerkleRoot(leaves)
+
+	// order of leaves
+	require.NotEqual(t, root, MakeMerkleRoot([]string{"b", "a", "c"}))
+
+	// the node can not be proved as the leaf
+	levels := merkleLevels(leaves)
+	proof := MerkleProof{Index: 0, Leaves: 2, Path: []string{}}
+	proof.Path = append(proof.Path, MakeMerkleRoot([]string{"c"}))
+	require.False(t, proof.Verify(root, string(levels[1][0])))
+
+	// the path must be used entirely
+	proof, _ = MakeMerkleProof(leaves, 2)
+	proof.Path = append(proof.Path, root)
+	require.False(t, proof.Verify(root, "c"))
+}
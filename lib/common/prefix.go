@@ -4,11 +4,14 @@ const (
 	BlockPrefixHash                       = string(0x00)
 	BlockPrefixConfirmed                  = string(0x01)
 	BlockPrefixHeight                     = string(0x02)
+	BlockPrefixCertificate                = string(0x03)
 	BlockTransactionPrefixHash            = string(0x10)
 	BlockTransactionPrefixSource          = string(0x11)
 	BlockTransactionPrefixConfirmed       = string(0x12)
 	BlockTransactionPrefixAccount         = string(0x13)
 	BlockTransactionPrefixBlock           = string(0x14)
+	BlockTransactionPrefixMemo            = string(0x15)
+	BlockTransactionPrefixPruned          = string(0x16)
 	BlockOperationPrefixHash              = string(0x20)
 	BlockOperationPrefixTxHash            = string(0x21)
 	BlockOperationPrefixSource            = string(0x22)
@@ -24,6 +27,13 @@ const (
 	BlockAccountPrefixCreated             = string(0x31)
 	BlockAccountSequenceIDPrefix          = string(0x32)
 	BlockAccountSequenceIDByAddressPrefix = string(0x33)
+	BlockAccountDataPrefixAddress         = string(0x34)
+	BlockAccountPrefixCreatedAddress      = string(0x35)
+	ValidatorSetChangePrefixHeight        = string(0x36)
+	StateTriePrefixHash                   = string(0x37)
+	BlockAccountHistoryPrefixAddress      = string(0x38)
+	BlockAccountMergedPrefixAddress       = string(0x39)
 	TransactionPoolPrefix                 = string(0x40)
+	BallotEvidencePrefixHeight            = string(0x42)
 	InternalPrefix                        = string(0x50) // internal data
 )
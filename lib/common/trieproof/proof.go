@@ -0,0 +1,52 @@
+// Package trieproof verifies the proof nodes of the state trie. It does not
+// depend on storage, so the clients can verify the proofs of the state without
+// the storage and the trie database of node.
+package trieproof
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcutil/base58"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"boscoin.io/sebak/lib/common"
+)
+
+// DecodeRoot returns the root of trie from `block.Header.StateRoot`; the
+// empty string is the empty trie.
+func DecodeRoot(root string) common.Hash {
+	if len(root) < 1 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(base58.Decode(root))
+}
+
+// VerifyProof returns the value of key from the proof nodes of
+// `trie.MakeProof`. If the nodes do not make the path from the root, it
+// returns error; if the key is not in the trie, the value is nil.
+func VerifyProof(root common.Hash, key []byte, nodes [][]byte) (value []byte, err error) {
+	db := proofDB{}
+	for _, n := range nodes {
+		db[string(crypto.Keccak256(n))] = n
+	}
+
+	value, _, err = trie.VerifyProof(ethcommon.Hash(root), key, db)
+	return
+}
+
+// proofDB is the trie node database of the proof nodes by the hash.
+type proofDB map[string][]byte
+
+func (db proofDB) Get(key []byte) ([]byte, error) {
+	if v, found := db[string(key)]; found {
+		return v, nil
+	}
+	return nil, fmt.Errorf("proof node %x missing", key)
+}
+
+func (db proofDB) Has(key []byte) (bool, error) {
+	_, found := db[string(key)]
+	return found, nil
+}
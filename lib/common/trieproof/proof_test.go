@@ -0,0 +1,62 @@
+package trieproof
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+)
+
+type proofNodes struct {
+	nodes [][]byte
+}
+
+func (p *proofNodes) Put(key []byte, value []byte) error {
+	p.nodes = append(p.nodes, append([]byte{}, value...))
+	return nil
+}
+
+func TestVerifyProof(t *testing.T) {
+	tr, err := trie.New(common.Hash{}, trie.NewDatabase(ethdb.NewMemDatabase()))
+	require.NoError(t, err)
+
+	require.NoError(t, tr.TryUpdate([]byte("showme"), []byte("findme")))
+	require.NoError(t, tr.TryUpdate([]byte("killme"), []byte("vacuum")))
+	root := common.Hash(tr.Hash())
+
+	proof := &proofNodes{}
+	require.NoError(t, tr.Prove([]byte("showme"), 0, proof))
+
+	value, err := VerifyProof(root, []byte("showme"), proof.nodes)
+	require.NoError(t, err)
+	require.Equal(t, []byte("findme"), value)
+
+	{ // with the other root
+		_, err := VerifyProof(common.Hash{}, []byte("showme"), proof.nodes)
+		require.Error(t, err)
+	}
+
+	{ // with the changed node
+		changed := make([][]byte, len(proof.nodes))
+		for i, n := range proof.nodes {
+			changed[i] = append([]byte{}, n...)
+		}
+		last := changed[len(changed)-1]
+		last[len(last)-1]++
+
+		_, err := VerifyProof(root, []byte("showme"), changed)
+		require.Error(t, err)
+	}
+
+	{ // the absent key
+		absent := &proofNodes{}
+		require.NoError(t, tr.Prove([]byte("unknown"), 0, absent))
+
+		value, err := VerifyProof(root, []byte("unknown"), absent.nodes)
+		require.NoError(t, err)
+		require.Nil(t, value)
+	}
+}
@@ -24,7 +24,7 @@ type ISAAC struct {
 	sync.RWMutex
 
 	connectionManager   network.ConnectionManager
-	storage             *storage.LevelDBBackend
+	storage             storage.Backend
 	proposerSelector    ProposerSelector
 	log                 logging.Logger
 	policy              voting.ThresholdPolicy
@@ -41,7 +41,17 @@ type ISAAC struct {
 // ISAAC should know network.ConnectionManager
 // because the ISAAC uses connected validators when calculating proposer
 func NewISAAC(node *node.LocalNode, p voting.ThresholdPolicy,
-	cm network.ConnectionManager, st *storage.LevelDBBackend, conf common.Config, syncer SyncController) (is *ISAAC, err error) {
+	cm network.ConnectionManager, st storage.Backend, conf common.Config, syncer SyncController) (is *ISAAC, err error) {
+
+	var initial []string
+	for address := range node.GetValidators() {
+		initial = append(initial, address)
+	}
+
+	var selector ProposerSelector
+	if selector, err = NewProposerSelector(common.ProposerSelector, cm, st, initial); err != nil {
+		return
+	}
 
 	is = &ISAAC{
 		Node:              node,
@@ -49,7 +59,7 @@ func NewISAAC(node *node.LocalNode, p voting.ThresholdPolicy,
 		RunningRounds:     map[string]*RunningRound{},
 		connectionManager: cm,
 		storage:           st,
-		proposerSelector:  SequentialSelector{cm},
+		proposerSelector:  selector,
 		Conf:              conf,
 		log:               log.New(logging.Ctx{"node": node.Alias()}),
 		syncer:            syncer,
@@ -81,7 +91,9 @@ func (is *ISAAC) ConnectionManager() network.ConnectionManager {
 	return is.connectionManager
 }
 
-func (is *ISAAC) SelectProposer(blockHeight uint64, round uint64) string {
+// SelectProposer returns the proposer of the given round; if the proposer can
+// not be selected, the round has no proposer.
+func (is *ISAAC) SelectProposer(blockHeight uint64, round uint64) (string, error) {
 	return is.proposerSelector.Select(blockHeight, round)
 }
 
@@ -90,13 +102,15 @@ func (is *ISAAC) SelectProposer(blockHeight uint64, round uint64) string {
 // that a node has expired to other nodes when a timeout occurs in the state.
 func (is *ISAAC) GenerateExpiredBallot(basis voting.Basis, state ballot.State) (ballot.Ballot, error) {
 	is.log.Debug("ISAAC.GenerateExpiredBallot", "basis", basis, "state", state)
-	proposerAddr := is.SelectProposer(basis.Height, basis.Round)
+	proposerAddr, err := is.SelectProposer(basis.Height, basis.Round)
+	if err != nil {
+		return ballot.Ballot{}, err
+	}
 
 	newExpiredBallot := ballot.NewBallot(is.Node.Address(), proposerAddr, basis, []string{})
 	newExpiredBallot.SetVote(state, voting.EXP)
 
 	config := is.Conf
-	var err error
 
 	opc, err := ballot.NewCollectTxFeeFromBallot(*newExpiredBallot, config.CommonAccountAddress)
 	if err != nil {
@@ -211,6 +225,20 @@ func (is *ISAAC) IsVoted(b ballot.Ballot) bool {
 	return runningRound.IsVoted(b)
 }
 
+// ConflictingBallot returns the ballot, which is voted by the same source of
+// the given ballot, but conflicts with it.
+func (is *ISAAC) ConflictingBallot(b ballot.Ballot) (ballot.Ballot, bool) {
+	is.RLock()
+	defer is.RUnlock()
+
+	runningRound, found := is.RunningRounds[b.VotingBasis().Index()]
+	if !found {
+		return ballot.Ballot{}, false
+	}
+
+	return runningRound.ConflictingBallot(b)
+}
+
 func (is *ISAAC) Vote(b ballot.Ballot) (isNew bool, err error) {
 	is.Lock()
 	defer is.Unlock()
@@ -219,10 +247,10 @@ func (is *ISAAC) Vote(b ballot.Ballot) (isNew bool, err error) {
 	var found bool
 	var runningRound *RunningRound
 	if runningRound, found = is.RunningRounds[basisIndex]; !found {
-		proposer := is.SelectProposer(
-			b.VotingBasis().Height,
-			b.VotingBasis().Round,
-		)
+		var proposer string
+		if proposer, err = is.SelectProposer(b.VotingBasis().Height, b.VotingBasis().Round); err != nil {
+			return
+		}
 
 		if runningRound, err = NewRunningRound(proposer, b); err != nil {
 			return true, err
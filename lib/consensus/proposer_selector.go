@@ -1,21 +1,259 @@
 package consensus
 
 import (
+	"bytes"
+	"fmt"
 	"sort"
+	"sync"
 
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/storage"
+)
+
+const (
+	SequentialSelectorName = "sequential"
+	ReputationSelectorName = "reputation"
+	RandomSelectorName     = "random"
+
+	// ReputationWindow is the number of recent blocks, which
+	// `ReputationSelector` looks up to find the validators missed their
+	// proposals.
+	ReputationWindow uint64 = 10
 )
 
 type ProposerSelector interface {
-	Select(uint64, uint64) string
+	Select(uint64, uint64) (string, error)
+}
+
+// NewProposerSelector returns the `ProposerSelector` of the given name; the
+// name is the network parameter, `common.ProposerSelector`. `initial` is the
+// validators at startup.
+func NewProposerSelector(name string, cm network.ConnectionManager, st storage.Backend, initial []string) (ProposerSelector, error) {
+	switch name {
+	case SequentialSelectorName:
+		return SequentialSelector{cm}, nil
+	case ReputationSelectorName:
+		return NewReputationSelector(st, initial, ReputationWindow), nil
+	case RandomSelectorName:
+		return NewRandomSelector(st, initial), nil
+	default:
+		return nil, fmt.Errorf("unknown proposer selector: '%s'", name)
+	}
 }
 
 type SequentialSelector struct {
 	cm network.ConnectionManager
 }
 
-func (s SequentialSelector) Select(blockHeight uint64, round uint64) string {
+func (s SequentialSelector) Select(blockHeight uint64, round uint64) (string, error) {
 	candidates := sort.StringSlice(s.cm.AllValidators())
 	candidates.Sort()
-	return candidates[(blockHeight+round)%uint64(len(candidates))]
+	return candidates[(blockHeight+round)%uint64(len(candidates))], nil
+}
+
+// ReputationSelector selects the proposer like `SequentialSelector`, but the
+// validators, which missed their proposals in the recent blocks, are moved
+// behind the others, so the validator which is down does not waste the
+// rounds. The order of validators is found only from the blocks in the window
+// and the validators at each height, so every node selects the same proposer,
+// even if it does not have the older blocks, like the node from snapshot:
+//
+// If the block is confirmed in the round over 0, the validators before the
+// proposer of block in the order at the basis of the block are missed. The
+// orders at the heights in the window are calculated again from the start of
+// the window, by the missed validators from the start; the order before the
+// window is same with `SequentialSelector`. If any block in the window is not
+// found, the proposer is not selected.
+type ReputationSelector struct {
+	sync.Mutex
+
+	st      storage.Backend
+	initial []string
+	window  uint64
+
+	// orders is cached by the block height
+	orders map[uint64][]string
+}
+
+func NewReputationSelector(st storage.Backend, initial []string, window uint64) *ReputationSelector {
+	return &ReputationSelector{
+		st:      st,
+		initial: initial,
+		window:  window,
+		orders:  map[uint64][]string{},
+	}
+}
+
+func (s *ReputationSelector) Select(blockHeight uint64, round uint64) (string, error) {
+	ordered, err := s.Order(blockHeight)
+	if err != nil {
+		return "", err
+	}
+
+	return ordered[round%uint64(len(ordered))], nil
+}
+
+// Order returns the validators in the order of rounds for the block on the
+// basis of the given block height.
+func (s *ReputationSelector) Order(blockHeight uint64) (ordered []string, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if o, found := s.orders[blockHeight]; found {
+		return o, nil
+	}
+
+	if ordered, err = s.order(blockHeight); err != nil {
+		return
+	}
+
+	// only the orders of recent blocks are needed
+	for height := range s.orders {
+		if height+s.window < blockHeight {
+			delete(s.orders, height)
+		}
+	}
+	s.orders[blockHeight] = ordered
+
+	return
+}
+
+// order calculates the order of validators on the basis of the given block
+// height from the blocks in the window.
+func (s *ReputationSelector) order(blockHeight uint64) (ordered []string, err error) {
+	start := common.FirstProposedBlockHeight
+	if blockHeight >= start+s.window {
+		start = blockHeight - s.window + 1
+	}
+
+	missed := map[string]bool{}
+	if blockHeight < start {
+		return s.reorder(blockHeight, missed)
+	}
+
+	// the order at the basis of the first block in the window
+	if ordered, err = s.reorder(start-1, missed); err != nil {
+		return
+	}
+	for height := start; height <= blockHeight; height++ {
+		var b block.Block
+		if b, err = block.GetBlockByHeight(s.st, height); err != nil {
+			err = errors.ProposerNotSelected.Clone().SetData("height", height).SetData("error", err.Error())
+			return
+		}
+
+		// the block of `height` is proposed on the basis of `height - 1`
+		for round := uint64(0); round < b.Round && round < uint64(len(ordered)); round++ {
+			if ordered[round] != b.Proposer {
+				missed[ordered[round]] = true
+			}
+		}
+
+		if ordered, err = s.reorder(height, missed); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// reorder returns the validators at the given block height in the order of
+// `SequentialSelector`, but the missed validators are moved behind.
+func (s *ReputationSelector) reorder(blockHeight uint64, missed map[string]bool) (ordered []string, err error) {
+	candidates := block.GetValidatorsAt(s.st, s.initial, blockHeight+1)
+	if len(candidates) < 1 {
+		err = errors.ProposerNotSelected.Clone().SetData("height", blockHeight)
+		return
+	}
+
+	var behind []string
+	for _, v := range rotateCandidates(candidates, blockHeight) {
+		if missed[v] {
+			behind = append(behind, v)
+		} else {
+			ordered = append(ordered, v)
+		}
+	}
+	ordered = append(ordered, behind...)
+
+	return
+}
+
+// rotateCandidates returns the candidates in the order of `SequentialSelector`
+// at the given block height.
+func rotateCandidates(candidates []string, blockHeight uint64) []string {
+	n := uint64(len(candidates))
+	if n < 1 {
+		return nil
+	}
+
+	rotated := make([]string, 0, n)
+	for i := uint64(0); i < n; i++ {
+		rotated = append(rotated, candidates[(blockHeight+i)%n])
+	}
+
+	return rotated
+}
+
+// RandomSelector selects the proposer by the seed of the block, so the order
+// of proposers can not be known before the block is confirmed. The seed is the
+// hash of the block, which has only one value for the confirmed block, so
+// every node has the same seed. The proposer of the block can change the seed
+// only by making the different block, like with the other transactions, and
+// the block must be agreed by the other validators. The candidates are the
+// validators at the next height, ordered by the hash of the seed and their
+// address, and each round takes the next one, so every validator is selected
+// once in the rounds of the number of validators.
+//
+// Without the block in storage, the proposer is not selected.
+type RandomSelector struct {
+	st      storage.Backend
+	initial []string
+}
+
+func NewRandomSelector(st storage.Backend, initial []string) *RandomSelector {
+	return &RandomSelector{
+		st:      st,
+		initial: initial,
+	}
+}
+
+func (s *RandomSelector) Select(blockHeight uint64, round uint64) (string, error) {
+	seed, err := s.Seed(blockHeight)
+	if err != nil {
+		return "", errors.ProposerNotSelected.Clone().SetData("height", blockHeight).SetData("error", err.Error())
+	}
+
+	candidates := block.GetValidatorsAt(s.st, s.initial, blockHeight+1)
+	if len(candidates) < 1 {
+		return "", errors.ProposerNotSelected.Clone().SetData("height", blockHeight)
+	}
+
+	hashes := map[string][]byte{}
+	for _, v := range candidates {
+		hashes[v] = common.MustMakeObjectHash([]interface{}{seed, v})
+	}
+
+	ordered := make([]string, len(candidates))
+	copy(ordered, candidates)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return bytes.Compare(hashes[ordered[i]], hashes[ordered[j]]) < 0
+	})
+
+	return ordered[round%uint64(len(ordered))], nil
+}
+
+// Seed returns the seed of the block at the given height.
+func (s *RandomSelector) Seed(blockHeight uint64) (seed []byte, err error) {
+	var b block.Block
+	if b, err = block.GetBlockByHeight(s.st, blockHeight); err != nil {
+		return
+	}
+
+	seed = common.MustMakeObjectHash([]interface{}{blockHeight, b.Hash})
+
+	return
 }
@@ -69,3 +69,18 @@ func (rr *RunningRound) Vote(ballot ballot.Ballot) {
 
 	rr.Ballots = append(rr.Ballots, ballot)
 }
+
+// ConflictingBallot returns the voted ballot, which conflicts with the given
+// ballot, see `ballot.IsConflicting`.
+func (rr *RunningRound) ConflictingBallot(b ballot.Ballot) (ballot.Ballot, bool) {
+	rr.RLock()
+	defer rr.RUnlock()
+
+	for _, voted := range rr.Ballots {
+		if ballot.IsConflicting(voted, b) {
+			return voted, true
+		}
+	}
+
+	return ballot.Ballot{}, false
+}
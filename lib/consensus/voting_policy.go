@@ -17,13 +17,22 @@ type ISAACVotingThresholdPolicy struct {
 }
 
 func (vt *ISAACVotingThresholdPolicy) Validators() int {
+	vt.RLock()
+	defer vt.RUnlock()
+
 	return vt.validators
 }
 
+// SetValidators sets the number of validators; it can be changed by
+// `UpdateValidators` operation.
 func (vt *ISAACVotingThresholdPolicy) SetValidators(n int) {
 	if n < 1 {
 		panic(errors.VotingThresholdInvalidValidators)
 	}
+
+	vt.Lock()
+	defer vt.Unlock()
+
 	vt.validators = n
 }
 
@@ -46,7 +55,13 @@ func (vt *ISAACVotingThresholdPolicy) SetConnected(n int) {
 }
 
 func (vt *ISAACVotingThresholdPolicy) Threshold() int {
-	v := float64(vt.validators) * (float64(vt.threshold) / float64(100))
+	return vt.ThresholdOf(vt.Validators())
+}
+
+// ThresholdOf returns the threshold for the given number of validators; the
+// validators of the past blocks can be different from the current validators.
+func (vt *ISAACVotingThresholdPolicy) ThresholdOf(validators int) int {
+	v := float64(validators) * (float64(vt.threshold) / float64(100))
 	threshold := int(math.Ceil(v))
 
 	if threshold < 0 {
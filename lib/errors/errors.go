@@ -115,4 +115,34 @@ var (
 	SnapshotNotFound                          = NewError(197, "snapshot not found")
 	SnapshotLimitReached                      = NewError(198, "snapshots over limit")
 	BallotsNotFound                           = NewError(199, "ballots not found")
+	InvalidSigners                            = NewError(200, "invalid signers")
+	InvalidThresholds                         = NewError(201, "invalid thresholds")
+	TransactionUnknownSigner                  = NewError(202, "signature from unknown signer")
+	TransactionInsufficientSignatureWeight    = NewError(203, "signature weight does not reach the threshold")
+	TransactionInvalidTimeBounds              = NewError(204, "invalid time bounds")
+	TransactionOutOfTimeBounds                = NewError(205, "transaction is out of time bounds")
+	TransactionInvalidMemo                    = NewError(206, "invalid memo")
+	InvalidDataEntry                          = NewError(207, "invalid data entry")
+	BlockAccountDataDoesNotExists             = NewError(208, "account data does not exists")
+	BlockAccountInsufficientReserve           = NewError(209, "balance does not cover the reserve of account")
+	BlockAccountHasFrozenAccounts             = NewError(210, "account has linked frozen accounts")
+	AccountMergeNotLastOperation              = NewError(211, "account-merge must be the last operation")
+	BlockAccountMergedInBallot                = NewError(212, "account is merged in the same ballot")
+	TransactionReplacementFeeTooLow           = NewError(213, "replacement transaction must have higher fee")
+	InvalidEnvelope                           = NewError(214, "invalid transaction envelope")
+	EnvelopeNetworkIDMismatch                 = NewError(215, "network id of envelope does not match")
+	InvalidValidatorSetChange                 = NewError(216, "invalid validator set change")
+	BallotConflicted                          = NewError(217, "ballot conflicts with the voted ballot")
+	InvalidCertificate                        = NewError(218, "invalid block certificate")
+	TransactionProofNotAvailable              = NewError(219, "transaction proof is not available in the block version")
+	InvalidStateRoot                          = NewError(220, "state root does not match")
+	AccountProofNotAvailable                  = NewError(221, "account proof is not available in the block version")
+	BlockTransactionPruned                    = NewError(222, "transaction is pruned")
+	InvalidSnapshot                           = NewError(223, "invalid snapshot")
+	InvalidArchive                            = NewError(224, "invalid archive")
+	InvalidBlockVersion                       = NewError(225, "block version does not match the height")
+	ProposerNotSelected                       = NewError(226, "proposer can not be selected")
+	TransactionReplaced                       = NewError(227, "transaction is replaced by the higher fee transaction")
+	TransactionEvictedFromPool                = NewError(228, "transaction is evicted from the full pool by the higher fee transaction")
+	BlockAccountHistoryNotAvailable           = NewError(229, "account history is not available at the height")
 )
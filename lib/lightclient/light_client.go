@@ -0,0 +1,329 @@
+// Package lightclient verifies the blocks of node without running the full
+// node; only the block headers and their certificates are downloaded from the
+// node `/blocks` API.
+//
+// From the trusted genesis block hash and validators, `LightClient` checks,
+//   - the chain of `PrevBlockHash`
+//   - the certificate of block is signed by the validators over the threshold
+//   - the block, which is made from the header and the ballots of certificate,
+//     has same hash with the certificate
+//
+// Then the transactions of the verified block can be checked by the Merkle
+// proof from the node `/transactions/{id}/proof` API and the
+// `TransactionsRoot` of header.
+//
+// The validator set changes by `UpdateValidators` operation are followed; the
+// node gives the transaction of the operation with its Merkle proof in the
+// block, `block.ValidatorSetChangeProof`, with the header, so the certificate
+// of the block after the changes is checked by the validators at the height.
+package lightclient
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network"
+	api "boscoin.io/sebak/lib/node/runner/node_api"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// MaxHeadersPerRequest is the maximum number of block headers in one request
+// to the node.
+var MaxHeadersPerRequest uint64 = 100
+
+type LightClient struct {
+	sync.RWMutex
+
+	client    network.NetworkClient
+	networkID []byte
+	initial   []string
+	policy    voting.ThresholdPolicy
+
+	genesis string
+	blocks  map[ /* block.Height */ uint64]block.Block
+	latest  uint64
+
+	// changes is the verified validator set changes in the order of
+	// `ValidatorSetChange.Height`
+	changes []*block.ValidatorSetChange
+}
+
+// NewLightClient makes `LightClient` from the trusted genesis block hash and
+// the validators at genesis; the threshold of the validators at each height is
+// found by `policy`.
+func NewLightClient(client network.NetworkClient, networkID []byte, genesis string, initial []string, policy voting.ThresholdPolicy) *LightClient {
+	return &LightClient{
+		client:    client,
+		networkID: networkID,
+		initial:   initial,
+		policy:    policy,
+		genesis:   genesis,
+		blocks:    map[uint64]block.Block{},
+		latest:    common.GenesisBlockHeight,
+	}
+}
+
+// Latest returns the height of the latest verified block.
+func (l *LightClient) Latest() uint64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.latest
+}
+
+// Block returns the verified block; the block is made from the header and
+// the certificate, so `Confirmed` is empty.
+func (l *LightClient) Block(height uint64) (blk block.Block, found bool) {
+	l.RLock()
+	defer l.RUnlock()
+
+	blk, found = l.blocks[height]
+	return
+}
+
+// Validators returns the validators, which vote the block of the given
+// height, from the verified validator set changes.
+func (l *LightClient) Validators(height uint64) []string {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.validatorsAt(height)
+}
+
+func (l *LightClient) validatorsAt(height uint64) []string {
+	var changes []*block.ValidatorSetChange
+	for _, c := range l.changes {
+		if c.Height > height {
+			break
+		}
+		changes = append(changes, c)
+	}
+
+	return block.ApplyValidatorSetChanges(l.initial, changes)
+}
+
+// Update downloads and verifies the block headers from the next of latest
+// verified block until the given height.
+func (l *LightClient) Update(height uint64) (err error) {
+	for {
+		start := l.Latest() + 1
+		if start > height {
+			return
+		}
+
+		end := height + 1
+		if end-start > MaxHeadersPerRequest {
+			end = start + MaxHeadersPerRequest
+		}
+
+		var body []byte
+		if body, err = l.client.GetBlockHeaders(start, end); err != nil {
+			return
+		}
+
+		var headers []block.Header
+		var certificates map[uint64]ballot.Certificate
+		var proofs map[string][]block.ValidatorSetChangeProof
+		if headers, certificates, proofs, err = unmarshalHeaders(body); err != nil {
+			return
+		}
+		if len(headers) < 1 {
+			return errors.BlockNotFound
+		}
+
+		for _, header := range headers {
+			c, found := certificates[header.Height]
+			if !found {
+				return errors.InvalidCertificate.Clone().SetData("error", "certificate not found")
+			}
+			if err = l.verify(header, c, proofs); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// verify checks the header is the next block of the latest verified block and
+// agreed by the validators; then the validator set changes in the block are
+// kept.
+func (l *LightClient) verify(header block.Header, c ballot.Certificate, proofs map[string][]block.ValidatorSetChangeProof) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if header.Height != l.latest+1 {
+		return errors.BlockNotFound
+	}
+
+	prevHash := l.genesis
+	if prev, found := l.blocks[l.latest]; found {
+		prevHash = prev.Hash
+	}
+	if header.PrevBlockHash != prevHash {
+		return errors.HashDoesNotMatch
+	}
+	if header.Version != block.GetBlockVersion(header.Height) {
+		return errors.InvalidBlockVersion
+	}
+
+	if len(c.Ballots) < 1 {
+		return errors.InvalidCertificate.Clone().SetData("error", "empty ballots")
+	}
+
+	// the block is made like the ballot is finished
+	b := c.Ballots[0]
+	basis := voting.Basis{
+		Round:     b.VotingBasis().Round,
+		Height:    header.Height,
+		BlockHash: header.PrevBlockHash,
+		TotalTxs:  header.TotalTxs,
+		TotalOps:  header.TotalOps,
+	}
+	blk := block.NewBlockWithVersion(
+		header.Version,
+		b.Proposer(),
+		basis,
+		b.ProposerTransaction().GetHash(),
+		b.Transactions(),
+		header.ProposedTime,
+		header.StateRoot,
+	)
+	if blk.TransactionsRoot != header.TransactionsRoot || blk.Hash != c.Block {
+		return errors.HashDoesNotMatch
+	}
+
+	validators := l.validatorsAt(blk.Height)
+	if err := c.Verify(*blk, validators, l.policy.ThresholdOf(len(validators)), l.networkID); err != nil {
+		return err
+	}
+
+	var changes []*block.ValidatorSetChange
+	for _, p := range proofs[blk.Hash] {
+		tx := p.Transaction
+		if tx.B.MakeHashString() != tx.H.Hash {
+			return errors.HashDoesNotMatch
+		}
+		if !includes(*blk, tx.H.Hash, p.Proof) {
+			return errors.InvalidValidatorSetChange.Clone().SetData("error", "transaction not in block")
+		}
+		changes = append(changes, p.Changes()...)
+	}
+
+	l.blocks[blk.Height] = *blk
+	l.latest = blk.Height
+
+	if len(changes) > 0 {
+		l.changes = append(l.changes, changes...)
+		sort.SliceStable(l.changes, func(i, j int) bool {
+			if l.changes[i].Height == l.changes[j].Height {
+				return l.changes[i].Hash < l.changes[j].Hash
+			}
+			return l.changes[i].Height < l.changes[j].Height
+		})
+	}
+
+	return nil
+}
+
+// VerifyTransaction checks the transaction is included in the verified block
+// of the given height by the Merkle proof from the node.
+func (l *LightClient) VerifyTransaction(height uint64, hash string) error {
+	blk, found := l.Block(height)
+	if !found {
+		return errors.BlockNotFound
+	}
+
+	var proof common.MerkleProof
+	if blk.Version >= block.BlockVersionV1 {
+		body, err := l.client.GetTransactionProof(hash)
+		if err != nil {
+			return err
+		}
+
+		var p struct {
+			Hash  string             `json:"hash"`
+			Block string             `json:"block"`
+			Proof common.MerkleProof `json:"proof"`
+		}
+		if err := json.Unmarshal(body, &p); err != nil {
+			return err
+		}
+		if p.Hash != hash || p.Block != blk.Hash {
+			return errors.TransactionNotFound
+		}
+		proof = p.Proof
+	}
+
+	if !includes(blk, hash, proof) {
+		return errors.TransactionNotFound
+	}
+
+	return nil
+}
+
+// includes checks the transaction is included in the verified block by the
+// Merkle proof. The block before `block.BlockVersionV1` does not have the
+// Merkle root, so the transaction is found in the transactions of block, which
+// are verified with the hash of block.
+func includes(blk block.Block, hash string, proof common.MerkleProof) bool {
+	if blk.Version < block.BlockVersionV1 {
+		_, found := common.InStringArray(append([]string{blk.ProposerTransaction}, blk.Transactions...), hash)
+		return found
+	}
+
+	return proof.Verify(blk.TransactionsRoot, hash)
+}
+
+func unmarshalHeaders(body []byte) (
+	headers []block.Header,
+	certificates map[uint64]ballot.Certificate,
+	proofs map[ /* block.Hash */ string][]block.ValidatorSetChangeProof,
+	err error,
+) {
+	certificates = map[uint64]ballot.Certificate{}
+	proofs = map[string][]block.ValidatorSetChangeProof{}
+
+	r := bufio.NewReader(bytes.NewReader(body))
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			err = readErr
+			return
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			var itemType api.NodeItemDataType
+			var item interface{}
+			if itemType, item, err = api.UnmarshalNodeItemResponse(line); err != nil {
+				return
+			}
+
+			switch itemType {
+			case api.NodeItemBlockHeader:
+				headers = append(headers, item.(block.Header))
+			case api.NodeItemBlockCertificate:
+				c := item.(ballot.Certificate)
+				certificates[c.Height] = c
+			case api.NodeItemValidatorSetChange:
+				p := item.(block.ValidatorSetChangeProof)
+				proofs[p.Block] = append(proofs[p.Block], p)
+			case api.NodeItemError:
+				err = item.(*errors.Error)
+				return
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return
+}
@@ -0,0 +1,299 @@
+package lightclient
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/node/runner"
+	"boscoin.io/sebak/lib/node/runner/api"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
+)
+
+type testLightClientNode struct {
+	st         storage.Backend
+	network    *network.MemoryNetwork
+	kps        []*keypair.Full
+	validators []string
+	congress   *keypair.Full
+	conf       common.Config
+}
+
+// newTestLightClientNode makes the node, which serves the node `/blocks` API
+// and the `/transactions/{id}/proof` API thru `network.MemoryNetwork`.
+func newTestLightClientNode(numberOfValidators int) *testLightClientNode {
+	conf := common.NewTestConfig()
+	congress := keypair.Random()
+	conf.CongressAccountAddress = congress.Address()
+	st := block.InitTestBlockchain()
+
+	mn, localNode := network.CreateMemoryNetwork(nil)
+	nh := runner.NewNetworkHandlerNode(localNode, mn, st, nil, nil, network.UrlPathPrefixNode, conf)
+	mn.AddHandler(nh.HandlerURLPattern(runner.GetBlocksPattern), nh.GetBlocksHandler)
+	ah := api.NewNetworkHandlerAPI(localNode, mn, st, network.UrlPathPrefixAPI, node.NodeInfo{})
+	mn.AddHandler(ah.HandlerURLPattern(api.GetTransactionProofHandlerPattern), ah.GetTransactionProofHandler)
+
+	n := &testLightClientNode{st: st, network: mn, congress: congress, conf: conf}
+	for i := 0; i < numberOfValidators; i++ {
+		kp := keypair.Random()
+		n.kps = append(n.kps, kp)
+		n.validators = append(n.validators, kp.Address())
+	}
+
+	return n
+}
+
+// makeBlock stores the next block of the given transactions with the
+// certificate, which is signed by the given signers.
+func (n *testLightClientNode) makeBlock(signers []*keypair.Full, txs ...transaction.Transaction) block.Block {
+	var hashes []string
+	for _, tx := range txs {
+		hashes = append(hashes, tx.GetHash())
+	}
+
+	latest := block.GetLatestBlock(n.st)
+	basis := voting.Basis{
+		Height:    latest.Height,
+		BlockHash: latest.Hash,
+		TotalTxs:  latest.TotalTxs + uint64(len(txs)) + 1,
+		TotalOps:  latest.TotalOps + uint64(len(txs)) + 1,
+	}
+
+	var ptx ballot.ProposerTransaction
+	ptx.H.Hash = ptx.B.MakeHashString()
+
+	proposer := signers[0].Address()
+	var ballots []ballot.Ballot
+	for _, kp := range signers {
+		b := ballot.NewBallot(kp.Address(), proposer, basis, hashes)
+		b.SetProposerTransaction(ptx)
+		b.SetVote(ballot.StateACCEPT, voting.YES)
+		b.Sign(kp, n.conf.NetworkID)
+		ballots = append(ballots, *b)
+	}
+
+	next := basis
+	next.Height++
+	blk := block.NewBlock(proposer, next, ptx.GetHash(), hashes, common.NowISO8601())
+	blk.MustSave(n.st)
+
+	for _, tx := range txs {
+		bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+		bt.MustSave(n.st)
+		if _, err := block.SaveTransactionPool(n.st, tx); err != nil {
+			panic(err)
+		}
+		if err := bt.SaveBlockOperations(n.st); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := ballot.NewCertificate(*blk, ballots).Save(n.st); err != nil {
+		panic(err)
+	}
+
+	return *blk
+}
+
+// updateValidators makes the transaction of `UpdateValidators` by the
+// congress account.
+func (n *testLightClientNode) updateValidators(height uint64, add []*keypair.Full, remove []string) transaction.Transaction {
+	var addresses, endpoints []string
+	for _, kp := range add {
+		addresses = append(addresses, kp.Address())
+		endpoints = append(endpoints, "https://localhost:12345")
+	}
+
+	op, err := operation.NewOperation(operation.NewUpdateValidators(height, addresses, endpoints, remove))
+	if err != nil {
+		panic(err)
+	}
+	tx, err := transaction.NewTransaction(n.congress.Address(), 0, op)
+	if err != nil {
+		panic(err)
+	}
+	tx.Sign(n.congress, n.conf.NetworkID)
+
+	return tx
+}
+
+func (n *testLightClientNode) genesis() string {
+	blk, _ := block.GetBlockByHeight(n.st, common.GenesisBlockHeight)
+	return blk.Hash
+}
+
+func (n *testLightClientNode) client() network.NetworkClient {
+	return n.network.GetClient(n.network.Endpoint())
+}
+
+func newTestPolicy() voting.ThresholdPolicy {
+	policy, err := consensus.NewDefaultVotingThresholdPolicy(67)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+func TestLightClient(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	n := newTestLightClientNode(4)
+	defer n.st.Close()
+
+	var blocks []block.Block
+	var txs []transaction.Transaction
+	for i := 0; i < 5; i++ {
+		_, tx := transaction.TestMakeTransaction(n.conf.NetworkID, 1)
+		txs = append(txs, tx)
+		blocks = append(blocks, n.makeBlock(n.kps[:3], tx))
+	}
+
+	defer func(limit uint64) { MaxHeadersPerRequest = limit }(MaxHeadersPerRequest)
+	MaxHeadersPerRequest = 2
+
+	l := NewLightClient(n.client(), n.conf.NetworkID, n.genesis(), n.validators, newTestPolicy())
+	require.Equal(t, common.GenesisBlockHeight, l.Latest())
+
+	require.NoError(t, l.Update(blocks[3].Height))
+	require.Equal(t, blocks[3].Height, l.Latest())
+
+	require.NoError(t, l.Update(blocks[4].Height))
+	require.Equal(t, blocks[4].Height, l.Latest())
+
+	for _, expected := range blocks {
+		blk, found := l.Block(expected.Height)
+		require.True(t, found)
+		require.Equal(t, expected.Hash, blk.Hash)
+	}
+
+	// transaction inclusion by the Merkle proof
+	require.NoError(t, l.VerifyTransaction(blocks[2].Height, txs[2].GetHash()))
+	require.Equal(t, errors.TransactionNotFound, l.VerifyTransaction(blocks[2].Height, txs[3].GetHash()))
+	require.Equal(t, errors.BlockNotFound, l.VerifyTransaction(blocks[4].Height+1, txs[4].GetHash()))
+	{ // the transaction, which the node does not have
+		err := l.VerifyTransaction(blocks[2].Height, "findme")
+		require.Equal(t, errors.HTTPProblem.Code, err.(*errors.Error).Code)
+	}
+
+	// the block, which the node does not have
+	err := l.Update(blocks[4].Height + 1)
+	require.Equal(t, errors.HTTPProblem.Code, err.(*errors.Error).Code)
+	require.Equal(t, blocks[4].Height, l.Latest())
+}
+
+// In TestLightClientPreviousBlockVersion test, the transactions of the block
+// before `block.BlockVersionV1` are checked without the Merkle proof.
+func TestLightClientPreviousBlockVersion(t *testing.T) {
+	n := newTestLightClientNode(4)
+	defer n.st.Close()
+
+	_, tx := transaction.TestMakeTransaction(n.conf.NetworkID, 1)
+	blk := n.makeBlock(n.kps[:3], tx)
+	require.Equal(t, block.BlockVersionV0, blk.Version)
+
+	l := NewLightClient(n.client(), n.conf.NetworkID, n.genesis(), n.validators, newTestPolicy())
+	require.NoError(t, l.Update(blk.Height))
+	require.NoError(t, l.VerifyTransaction(blk.Height, tx.GetHash()))
+	require.NoError(t, l.VerifyTransaction(blk.Height, blk.ProposerTransaction))
+	require.Equal(t, errors.TransactionNotFound, l.VerifyTransaction(blk.Height, "findme"))
+}
+
+// In TestLightClientValidatorSetChange test, the certificates after the
+// validator set change are checked by the changed validators.
+func TestLightClientValidatorSetChange(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	n := newTestLightClientNode(4)
+	defer n.st.Close()
+
+	added := []*keypair.Full{keypair.Random(), keypair.Random()}
+	removed := n.kps[0]
+
+	latest := block.GetLatestBlock(n.st)
+	changed := latest.Height + 3
+	first := n.makeBlock(n.kps[:3], n.updateValidators(changed, added, []string{removed.Address()}))
+	require.True(t, first.Version >= block.BlockVersionV1)
+	n.makeBlock(n.kps[:3])
+
+	// the validators of the changed height are n.kps[1:] and added
+	next := append(append([]*keypair.Full{}, n.kps[1:]...), added...)
+	blk := n.makeBlock(next[:4])
+	require.Equal(t, changed, blk.Height)
+
+	l := NewLightClient(n.client(), n.conf.NetworkID, n.genesis(), n.validators, newTestPolicy())
+	require.NoError(t, l.Update(blk.Height))
+	require.Equal(t, blk.Height, l.Latest())
+
+	require.Equal(t, block.ApplyValidatorSetChanges(n.validators, nil), l.Validators(changed-1))
+
+	var expected []string
+	for _, kp := range next {
+		expected = append(expected, kp.Address())
+	}
+	sort.Strings(expected)
+	require.Equal(t, expected, l.Validators(changed))
+
+	{ // the removed validator can not sign the block after the change
+		signers := append([]*keypair.Full{removed}, next[:3]...)
+		n.makeBlock(signers)
+		err := l.Update(blk.Height + 1)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+		require.Equal(t, blk.Height, l.Latest())
+	}
+
+	{ // without following the change, the block is not verified
+		lc := NewLightClient(n.client(), n.conf.NetworkID, n.genesis(), n.validators, newTestPolicy())
+		require.NoError(t, lc.Update(first.Height))
+		lc.changes = nil
+		err := lc.Update(blk.Height)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+		require.Equal(t, blk.Height-1, lc.Latest())
+	}
+}
+
+func TestLightClientUntrusted(t *testing.T) {
+	n := newTestLightClientNode(4)
+	defer n.st.Close()
+
+	_, tx0 := transaction.TestMakeTransaction(n.conf.NetworkID, 1)
+	_, tx1 := transaction.TestMakeTransaction(n.conf.NetworkID, 1)
+	n.makeBlock(n.kps[:3], tx0)
+	blk := n.makeBlock(n.kps[:2], tx1)
+
+	{ // different genesis
+		l := NewLightClient(n.client(), n.conf.NetworkID, "showme", n.validators, newTestPolicy())
+		require.Equal(t, errors.HashDoesNotMatch, l.Update(blk.Height))
+		require.Equal(t, common.GenesisBlockHeight, l.Latest())
+	}
+
+	{ // unknown validators
+		var validators []string
+		for i := 0; i < 4; i++ {
+			validators = append(validators, keypair.Random().Address())
+		}
+
+		l := NewLightClient(n.client(), n.conf.NetworkID, n.genesis(), validators, newTestPolicy())
+		err := l.Update(blk.Height)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+		require.Equal(t, common.GenesisBlockHeight, l.Latest())
+	}
+
+	{ // the last block is signed under threshold
+		l := NewLightClient(n.client(), n.conf.NetworkID, n.genesis(), n.validators, newTestPolicy())
+		err := l.Update(blk.Height)
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+		require.Equal(t, blk.Height-1, l.Latest())
+	}
+}
@@ -20,6 +20,8 @@ type ConsensusMetrics struct {
 
 	Validators        metrics.Gauge
 	MissingValidators metrics.Gauge
+
+	Equivocations metrics.Counter
 }
 
 func (c *ConsensusMetrics) SetBlockIntervalSeconds(t time.Time) time.Time {
@@ -46,6 +48,9 @@ func (c *ConsensusMetrics) SetValidators(num int) {
 func (c *ConsensusMetrics) SetMissingValidators(num int) {
 	c.MissingValidators.Set(float64(num))
 }
+func (c *ConsensusMetrics) AddEquivocation() {
+	c.Equivocations.Add(1)
+}
 
 func PromConsensusMetrics() *ConsensusMetrics {
 	return &ConsensusMetrics{
@@ -91,6 +96,12 @@ func PromConsensusMetrics() *ConsensusMetrics {
 			Name:      "missing_validators",
 			Help:      "Number of missing validators.",
 		}, []string{}),
+		Equivocations: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: ConsensusSubsystem,
+			Name:      "equivocations",
+			Help:      "Number of conflicting ballots detected.",
+		}, []string{}),
 	}
 }
 
@@ -106,5 +117,7 @@ func NopConsensusMetrics() *ConsensusMetrics {
 
 		Validators:        discard.NewGauge(),
 		MissingValidators: discard.NewGauge(),
+
+		Equivocations: discard.NewCounter(),
 	}
 }
@@ -41,6 +41,12 @@ type NetworkClient interface {
 	SendDiscovery(interface{}) ([]byte, error)
 	GetTransactions([]string) ([]byte, error)
 	GetBallots() ([]byte, error)
+	// GetBlockHeaders returns the block headers with their certificates
+	// from the start height until before the end height.
+	GetBlockHeaders(start, end uint64) ([]byte, error)
+	// GetTransactionProof returns the Merkle proof of the confirmed
+	// transaction from the `/transactions/{id}/proof` API.
+	GetTransactionProof(hash string) ([]byte, error)
 }
 
 type MessageBroker interface {
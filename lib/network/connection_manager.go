@@ -2,6 +2,7 @@ package network
 
 import (
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/node"
 )
 
 type ConnectionManager interface {
@@ -13,4 +14,5 @@ type ConnectionManager interface {
 	CountConnected() int
 	IsReady() bool
 	Discovery(DiscoveryMessage) error
+	UpdateValidators([]*node.Validator, []string)
 }
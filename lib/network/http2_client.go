@@ -2,6 +2,7 @@ package network
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -170,6 +171,49 @@ func (c *HTTP2NetworkClient) GetBallots() (retBody []byte, err error) {
 	return
 }
 
+func (c *HTTP2NetworkClient) GetBlockHeaders(start, end uint64) (retBody []byte, err error) {
+	headers := c.DefaultHeaders()
+	headers.Set("Content-Type", "application/json")
+
+	u := c.resolvePath(UrlPathPrefixNode + "/blocks")
+	u.RawQuery = blockHeadersQuery(start, end)
+
+	var response *http.Response
+	response, err = c.client.Get(u.String(), headers)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	retBody, err = ioutil.ReadAll(response.Body)
+
+	if response.StatusCode != http.StatusOK {
+		err = errors.HTTPProblem.Clone().SetData("status", response.StatusCode)
+	}
+
+	return
+}
+
+func (c *HTTP2NetworkClient) GetTransactionProof(hash string) (retBody []byte, err error) {
+	headers := c.DefaultHeaders()
+	headers.Set("Content-Type", "application/json")
+
+	u := c.resolvePath(transactionProofPath(hash))
+
+	var response *http.Response
+	response, err = c.client.Get(u.String(), headers)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	retBody, err = ioutil.ReadAll(response.Body)
+
+	if response.StatusCode != http.StatusOK {
+		err = errors.HTTPProblem.Clone().SetData("status", response.StatusCode)
+	}
+
+	return
+}
+
 ///
 /// Perform a raw Get request on this peer
 ///
@@ -202,3 +246,14 @@ func (client *HTTP2NetworkClient) Get(endpoint string) ([]byte, error) {
 
 	return ioutil.ReadAll(response.Body)
 }
+
+// blockHeadersQuery makes the query of node `/blocks` API for the block headers
+// of the height range.
+func blockHeadersQuery(start, end uint64) string {
+	return fmt.Sprintf("mode=header&height-range=%d-%d", start, end)
+}
+
+// transactionProofPath makes the path of the `/transactions/{id}/proof` API.
+func transactionProofPath(hash string) string {
+	return fmt.Sprintf("%s/v1/transactions/%s/proof", UrlPathPrefixAPI, url.PathEscape(hash))
+}
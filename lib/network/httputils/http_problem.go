@@ -39,6 +39,10 @@ type Problem struct {
 	//occurrence of the problem.  It may or may not yield further
 	//information if dereferenced.
 	Instance string `json:"instance,omitempty"`
+
+	// "extras" - The additional data of `errors.Error`, except "error" and
+	// "status", which are already used for "detail" and "status".
+	Extras map[string]interface{} `json:"extras,omitempty"`
 }
 
 func NewProblem(problemType string, title string) Problem {
@@ -64,6 +68,15 @@ func NewErrorProblem(err error, status int) Problem {
 		if detail, ok := e.Data["error"]; ok {
 			p.Detail = detail.(string)
 		}
+		for k, v := range e.Data {
+			if k == "error" || k == "status" {
+				continue
+			}
+			if p.Extras == nil {
+				p.Extras = map[string]interface{}{}
+			}
+			p.Extras[k] = v
+		}
 	} else {
 		p = NewProblem(HttpProblemDefaultType, err.Error())
 	}
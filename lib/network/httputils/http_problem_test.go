@@ -130,3 +130,22 @@ func TestProblem(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorProblemExtras(t *testing.T) {
+	{ // without extras
+		p := NewErrorProblem(errors.InvalidOperation.Clone().SetData("error", "showme"), 400)
+		require.Equal(t, "showme", p.Detail)
+		require.Nil(t, p.Extras)
+	}
+
+	{ // "error" and "status" are not in extras
+		e := errors.InvalidOperation.Clone().
+			SetData("error", "showme").
+			SetData("status", 400).
+			SetData("operations", []int{1, 2})
+		p := NewErrorProblem(e, 400)
+		require.Equal(t, "showme", p.Detail)
+		require.Equal(t, 1, len(p.Extras))
+		require.Equal(t, []int{1, 2}, p.Extras["operations"])
+	}
+}
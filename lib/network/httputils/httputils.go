@@ -20,11 +20,14 @@ func IsEventStream(r *http.Request) bool {
 var (
 	// ErrorsToStatus defines errors.Error does not have 400 status code.
 	ErrorsToStatus = map[uint]int{
-		errors.TooManyRequests.Code:               http.StatusTooManyRequests,
-		errors.BlockTransactionDoesNotExists.Code: http.StatusNotFound,
-		errors.BlockAccountDoesNotExists.Code:     http.StatusNotFound,
-		errors.TransactionPoolFull.Code:           http.StatusLocked,
-		errors.BadRequestParameter.Code:           http.StatusBadRequest,
+		errors.TooManyRequests.Code:                 http.StatusTooManyRequests,
+		errors.BlockTransactionDoesNotExists.Code:   http.StatusNotFound,
+		errors.BlockTransactionPruned.Code:          http.StatusGone,
+		errors.BlockAccountDoesNotExists.Code:       http.StatusNotFound,
+		errors.BlockAccountDataDoesNotExists.Code:   http.StatusNotFound,
+		errors.BlockAccountHistoryNotAvailable.Code: http.StatusGone,
+		errors.TransactionPoolFull.Code:             http.StatusLocked,
+		errors.BadRequestParameter.Code:             http.StatusBadRequest,
 	}
 )
 
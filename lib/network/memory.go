@@ -22,6 +22,10 @@ type MemoryNetwork struct {
 	peers map[ /* endpoint */ string]*MemoryNetwork
 
 	messageBroker MessageBroker
+
+	// router serves the handlers to `MemoryTransportClient`, like the http
+	// requests.
+	router *mux.Router
 }
 
 func (t *MemoryNetwork) GetClient(endpoint *common.Endpoint) NetworkClient {
@@ -117,6 +121,7 @@ func (prev *MemoryNetwork) NewMemoryNetwork() *MemoryNetwork {
 		receiveChannel: make(chan common.NetworkMessage),
 		close:          make(chan bool),
 		peers:          peers,
+		router:         mux.NewRouter(),
 	}
 
 	n.peers[n.endpoint.String()] = n
@@ -125,8 +130,13 @@ func (prev *MemoryNetwork) NewMemoryNetwork() *MemoryNetwork {
 	return n
 }
 
-func (p *MemoryNetwork) AddHandler(string, http.HandlerFunc) *mux.Route {
-	return &mux.Route{}
+func (p *MemoryNetwork) AddHandler(pattern string, handler http.HandlerFunc) *mux.Route {
+	return p.router.HandleFunc(pattern, handler)
+}
+
+// ServeHTTP serves the request by the added handlers.
+func (p *MemoryNetwork) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.router.ServeHTTP(w, r)
 }
 
 func (p *MemoryNetwork) AddMiddleware(string, ...mux.MiddlewareFunc) error {
@@ -2,6 +2,9 @@ package network
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
@@ -31,7 +34,7 @@ func (m *MemoryTransportClient) Connect(node node.Node) (b []byte, err error) {
 }
 
 func (m *MemoryTransportClient) GetNodeInfo() ([]byte, error) {
-	return []byte{}, errors.NotImplemented
+	return m.server.GetNodeInfo(), nil
 }
 
 func (m *MemoryTransportClient) SendMessage(message interface{}) (body []byte, err error) {
@@ -75,3 +78,29 @@ func (m *MemoryTransportClient) GetTransactions([]string) ([]byte, error) {
 func (m *MemoryTransportClient) GetBallots() ([]byte, error) {
 	return []byte{}, errors.NotImplemented
 }
+
+func (m *MemoryTransportClient) GetBlockHeaders(start, end uint64) (body []byte, err error) {
+	u := url.URL{Path: UrlPathPrefixNode + "/blocks", RawQuery: blockHeadersQuery(start, end)}
+
+	w := httptest.NewRecorder()
+	m.server.ServeHTTP(w, httptest.NewRequest("GET", u.String(), nil))
+
+	body = w.Body.Bytes()
+	if w.Code != http.StatusOK {
+		err = errors.HTTPProblem.Clone().SetData("status", w.Code)
+	}
+
+	return
+}
+
+func (m *MemoryTransportClient) GetTransactionProof(hash string) (body []byte, err error) {
+	w := httptest.NewRecorder()
+	m.server.ServeHTTP(w, httptest.NewRequest("GET", transactionProofPath(hash), nil))
+
+	body = w.Body.Bytes()
+	if w.Code != http.StatusOK {
+		err = errors.HTTPProblem.Clone().SetData("status", w.Code)
+	}
+
+	return
+}
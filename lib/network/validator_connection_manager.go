@@ -30,6 +30,7 @@ type ValidatorConnectionManager struct {
 	config                        common.Config
 	discoveryChannel              chan DiscoveryMessage
 	connectedEqualOrOverThreshold bool
+	started                       bool
 
 	log logging.Logger
 }
@@ -96,6 +97,10 @@ func (c *ValidatorConnectionManager) Start() {
 		c.startDiscovery()
 	}
 
+	c.Lock()
+	c.started = true
+	c.Unlock()
+
 	c.log.Debug("starting to connect to validators", "validators", c.localNode.GetValidators())
 	for _, v := range c.localNode.GetValidators() {
 		if v.Address() == c.localNode.Address() {
@@ -166,6 +171,12 @@ func (c *ValidatorConnectionManager) countConnectedUnlocked() int {
 func (c *ValidatorConnectionManager) connectingValidator(v *node.Validator) {
 	ticker := time.NewTicker(time.Second * 1)
 	for _ = range ticker.C {
+		// the validator is removed by `UpdateValidators`
+		if c.localNode.Validator(v.Address()) != v {
+			ticker.Stop()
+			return
+		}
+
 		if v.Endpoint() == nil {
 			continue
 		}
@@ -301,6 +312,17 @@ func (c *ValidatorConnectionManager) connectValidator(v *node.Validator) (err er
 		return
 	}
 
+	// the validator, which has the different parameters of network, can not
+	// agree the same blocks
+	if b, err = client.GetNodeInfo(); err != nil {
+		return
+	}
+	var nodeInfo node.NodeInfo
+	if nodeInfo, err = node.NewNodeInfoFromJSON(b); err != nil {
+		return
+	}
+	err = nodeInfo.Policy.CheckNetworkParameters()
+
 	return
 }
 
@@ -355,12 +377,43 @@ func (c *ValidatorConnectionManager) Broadcast(message common.Message) {
 	return
 }
 
+// UpdateValidators connects to the added validators and disconnects the removed
+// validators. The validators of local node should be already updated.
+func (c *ValidatorConnectionManager) UpdateValidators(added []*node.Validator, removed []string) {
+	c.Lock()
+	for _, address := range removed {
+		delete(c.connected, address)
+	}
+	if connected := c.countConnectedUnlocked(); connected > 0 {
+		c.policy.SetConnected(connected)
+	}
+	c.connectedEqualOrOverThreshold = c.countConnectedUnlocked() >= c.policy.Threshold()
+	started := c.started
+	c.Unlock()
+
+	metrics.Consensus.SetValidators(len(c.localNode.GetValidators()))
+
+	c.log.Debug("validators updated", "added", added, "removed", removed)
+
+	// before `Start()`, the validators will be connected by `Start()`
+	if !started {
+		return
+	}
+
+	for _, v := range added {
+		if v.Address() == c.localNode.Address() {
+			continue
+		}
+		go c.connectingValidator(v)
+	}
+}
+
 func (c *ValidatorConnectionManager) watchForMetrics() {
-	numValidators := len(c.localNode.GetValidators())
-	metrics.Consensus.SetValidators(numValidators)
+	metrics.Consensus.SetValidators(len(c.localNode.GetValidators()))
 
 	ticker := time.NewTicker(time.Second * 60)
 	for _ = range ticker.C {
+		numValidators := len(c.localNode.GetValidators())
 		numConnected := c.CountConnected()
 		metrics.Consensus.SetMissingValidators(numValidators - numConnected)
 	}
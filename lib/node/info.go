@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 )
 
 type NodeInfo struct {
@@ -39,19 +40,47 @@ type NodePolicy struct {
 	TransactionsLimit         int           `json:"transactions-limit"`            // transactions limit in a ballot
 	OperationsLimit           int           `json:"operations-limit"`              // operations limit in a transaction
 	OperationsInBallotLimit   int           `json:"operations-in-ballot-limit"`    // operations limit in a ballot
+	BaseFeeBlocks             int           `json:"base-fee-blocks"`               // number of recent blocks for base fee; see `common.CalculateBaseFee`
+	BaseFeeMaxMultiplier      uint64        `json:"base-fee-max-multiplier"`       // maximum multiplier of base fee
+	ProposerSelector          string        `json:"proposer-selector"`             // proposer selector; see `common.ProposerSelector`
 	GenesisBlockConfirmedTime string        `json:"genesis-block-confirmed-time"`  // confirmed time of genesis block; see `common.GenesisBlockConfirmedTime`
 	InflationRatio            string        `json:"inflation-ratio"`               // inflation ratio; see `common.InflationRatio`
 	UnfreezingPeriod          uint64        `json:"unfreezing-period"`             // unfreezing period
 	BlockHeightEndOfInflation uint64        `json:"block-height-end-of-inflation"` // block height of inflation end; see `common.BlockHeightEndOfInflation`
+	BlockVersionV1Height      uint64        `json:"block-version-v1-height"`       // see `common.BlockVersionV1Height`
+	BlockVersionV2Height      uint64        `json:"block-version-v2-height"`       // see `common.BlockVersionV2Height`
+}
+
+// CheckNetworkParameters checks the parameters of network in the policy of
+// the other node are same with the local ones; every node of network must
+// make and validate the blocks by the same parameters. The parameters, which
+// are not in the policy of the node of the older version, are not checked.
+func (p NodePolicy) CheckNetworkParameters() error {
+	if p.BlockVersionV1Height != 0 && p.BlockVersionV1Height != common.BlockVersionV1Height {
+		return errors.DiscoveryPolicyDoesNotMatch.Clone().SetData("policy", "block-version-v1-height")
+	}
+	if p.BlockVersionV2Height != 0 && p.BlockVersionV2Height != common.BlockVersionV2Height {
+		return errors.DiscoveryPolicyDoesNotMatch.Clone().SetData("policy", "block-version-v2-height")
+	}
+	if len(p.ProposerSelector) > 0 && p.ProposerSelector != common.ProposerSelector {
+		return errors.DiscoveryPolicyDoesNotMatch.Clone().SetData("policy", "proposer-selector")
+	}
+
+	return nil
 }
 
 type NodeBlockInfo struct {
-	Height    uint64 `json:"height"`
-	Hash      string `json:"hash"`
-	TotalTxs  uint64 `json:"total-txs"`
-	TotalOps  uint64 `json:"total-ops"`
-	Proposed  string `json:"proposed"`
-	Confirmed string `json:"confirmed"`
+	Height    uint64        `json:"height"`
+	Hash      string        `json:"hash"`
+	TotalTxs  uint64        `json:"total-txs"`
+	TotalOps  uint64        `json:"total-ops"`
+	Proposed  string        `json:"proposed"`
+	Confirmed string        `json:"confirmed"`
+	BaseFee   common.Amount `json:"base-fee"` // minimum fee of operation for the next block
+
+	// LowestFullHeight is the height of the lowest block, which still has
+	// its transactions and operations; the older blocks are pruned.
+	LowestFullHeight uint64 `json:"lowest-full-height"`
 }
 
 type NodeVersion struct {
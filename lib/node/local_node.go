@@ -157,6 +157,15 @@ func (n *LocalNode) AddValidators(validators ...*Validator) error {
 	return nil
 }
 
+func (n *LocalNode) RemoveValidators(addresses ...string) {
+	n.Lock()
+	defer n.Unlock()
+
+	for _, address := range addresses {
+		delete(n.validators, address)
+	}
+}
+
 func (n *LocalNode) ClearValidators() {
 	n.Lock()
 	defer n.Unlock()
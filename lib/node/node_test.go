@@ -7,6 +7,7 @@ import (
 
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
 
 	"github.com/stretchr/testify/require"
 )
@@ -77,3 +78,42 @@ func TestNodeMarshalJSONWithValidator(t *testing.T) {
 	require.True(t, strings.Contains(str, `"alias":"v2"`), str)
 	require.True(t, strings.Contains(str, `"endpoint":"https://localhost:5002"`), str)
 }
+
+func TestNodePolicyCheckNetworkParameters(t *testing.T) {
+	defer func(v1, v2 uint64, selector string) {
+		common.BlockVersionV1Height = v1
+		common.BlockVersionV2Height = v2
+		common.ProposerSelector = selector
+	}(common.BlockVersionV1Height, common.BlockVersionV2Height, common.ProposerSelector)
+
+	common.BlockVersionV1Height = 10
+	common.BlockVersionV2Height = 20
+	common.ProposerSelector = "reputation"
+
+	policy := NodePolicy{BlockVersionV1Height: 10, BlockVersionV2Height: 20, ProposerSelector: "reputation"}
+	require.NoError(t, policy.CheckNetworkParameters())
+
+	// the node of the older version does not advertise the heights
+	require.NoError(t, NodePolicy{}.CheckNetworkParameters())
+
+	{
+		p := policy
+		p.BlockVersionV1Height = 11
+		err := p.CheckNetworkParameters()
+		require.Equal(t, errors.DiscoveryPolicyDoesNotMatch.Code, err.(*errors.Error).Code)
+	}
+
+	{
+		p := policy
+		p.BlockVersionV2Height = 21
+		err := p.CheckNetworkParameters()
+		require.Equal(t, errors.DiscoveryPolicyDoesNotMatch.Code, err.(*errors.Error).Code)
+	}
+
+	{
+		p := policy
+		p.ProposerSelector = "sequential"
+		err := p.CheckNetworkParameters()
+		require.Equal(t, errors.DiscoveryPolicyDoesNotMatch.Code, err.(*errors.Error).Code)
+	}
+}
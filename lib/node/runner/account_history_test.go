@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// TestBlockAccountHistory checks the accounts changed in the block are saved
+// as the `BlockAccountHistory` of the block height.
+func TestBlockAccountHistory(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, _ := createNodeRunnerForTesting(5, conf, nil)
+
+	tx, _ := GetTransaction()
+	nr.TransactionPool.Add(tx)
+
+	round := uint64(0)
+	_, err := nr.proposeNewBallot(round)
+	require.NoError(t, err)
+
+	b := nr.Consensus().LatestBlock()
+	basis := voting.Basis{
+		Round:     round,
+		Height:    b.Height,
+		BlockHash: b.Hash,
+		TotalTxs:  b.TotalTxs,
+	}
+	proposer := nr.localNode
+
+	st := nr.Storage()
+	before, err := block.GetBlockAccount(st, tx.B.Source)
+	require.NoError(t, err)
+
+	for _, n := range nodes[1:] {
+		require.NoError(t, ReceiveBallot(nr, GenerateBallot(proposer, basis, tx, ballot.StateSIGN, n, conf)))
+	}
+	for _, n := range nodes[:4] {
+		require.NoError(t, ReceiveBallot(nr, GenerateBallot(proposer, basis, tx, ballot.StateACCEPT, n, conf)))
+	}
+
+	blk := nr.Consensus().LatestBlock()
+	require.Equal(t, b.Height+1, blk.Height)
+
+	target := tx.B.Operations[0].B.(operation.Targetable).TargetAddress()
+	for _, address := range []string{tx.B.Source, target} {
+		ba, err := block.GetBlockAccount(st, address)
+		require.NoError(t, err)
+
+		h, err := block.GetBlockAccountHistoryAt(st, address, blk.Height)
+		require.NoError(t, err)
+		require.Equal(t, blk.Height, h.Height)
+		require.Equal(t, ba.Balance, h.Balance)
+		require.Equal(t, ba.SequenceID, h.SequenceID)
+	}
+
+	// before the block, the source has the previous balance
+	h, err := block.GetBlockAccountHistoryAt(st, tx.B.Source, b.Height)
+	require.NoError(t, err)
+	require.Equal(t, before.Balance, h.Balance)
+	require.Equal(t, before.SequenceID, h.SequenceID)
+
+	// the target did not exist before the block
+	_, err = block.GetBlockAccountHistoryAt(st, target, b.Height)
+	require.Error(t, err)
+}
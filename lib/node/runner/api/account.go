@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
@@ -12,6 +13,7 @@ import (
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network/httputils"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
+	"boscoin.io/sebak/lib/storage/statedb"
 	"boscoin.io/sebak/lib/transaction/operation"
 )
 
@@ -20,6 +22,24 @@ func (api NetworkHandlerAPI) GetAccountHandler(w http.ResponseWriter, r *http.Re
 	address := vars["id"]
 
 	readFunc := func() (payload interface{}, err error) {
+		// with `height`, the balance and sequence ID after the block of the
+		// height is returned.
+		if s := r.URL.Query().Get("height"); len(s) > 0 {
+			height, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				return nil, errors.BadRequestParameter.Clone().SetData("error", err.Error())
+			}
+			if height > block.GetLatestBlock(api.storage).Height {
+				return nil, errors.BlockNotFound
+			}
+
+			h, err := block.GetBlockAccountHistoryAt(api.storage, address, height)
+			if err != nil {
+				return nil, err
+			}
+			return resource.NewAccountHistory(&h, height), nil
+		}
+
 		found, err := block.ExistsBlockAccount(api.storage, address)
 		if err != nil {
 			return nil, err
@@ -44,6 +64,37 @@ func (api NetworkHandlerAPI) GetAccountHandler(w http.ResponseWriter, r *http.Re
 	httputils.MustWriteJSON(w, 200, payload)
 }
 
+// GetAccountProofHandler returns the account in the state trie of the block
+// with the proof nodes, which can be checked with the `StateRoot` of block
+// header. Without `height`, the latest block is used.
+func (api NetworkHandlerAPI) GetAccountProofHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	address := vars["id"]
+
+	var blk block.Block
+	if s := r.URL.Query().Get("height"); len(s) > 0 {
+		height, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			httputils.WriteJSONError(w, errors.BadRequestParameter.Clone().SetData("error", err.Error()))
+			return
+		}
+		if blk, err = block.GetBlockByHeight(api.storage, height); err != nil {
+			httputils.WriteJSONError(w, err)
+			return
+		}
+	} else {
+		blk = block.GetLatestBlock(api.storage)
+	}
+
+	ba, proof, err := statedb.GetAccountProof(api.storage, blk.StateRoot, address)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	httputils.MustWriteJSON(w, 200, resource.NewAccountProof(&blk, ba, proof))
+}
+
 func (api NetworkHandlerAPI) GetAccountsHandler(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
@@ -87,6 +138,76 @@ func (api NetworkHandlerAPI) GetAccountsHandler(w http.ResponseWriter, r *http.R
 	httputils.MustWriteJSON(w, 200, resource.NewResourceList(rs, "", "", ""))
 }
 
+func (api NetworkHandlerAPI) GetAccountDataHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	address := vars["id"]
+
+	p, err := NewPageQuery(r)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	if found, err := block.ExistsBlockAccount(api.storage, address); err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	} else if !found {
+		httputils.WriteJSONError(w, errors.BlockAccountDoesNotExists)
+		return
+	}
+
+	var options = p.ListOptions()
+	var firstCursor []byte
+	var cursor []byte
+	var rs []resource.Resource
+	iterFunc, closeFunc := block.GetBlockAccountDataByAddress(api.storage, address, options)
+	for {
+		d, hasNext, c := iterFunc()
+		if !hasNext {
+			break
+		}
+		cursor = append([]byte{}, c...)
+		if len(firstCursor) == 0 {
+			firstCursor = append(firstCursor, c...)
+		}
+		rs = append(rs, resource.NewAccountData(d))
+	}
+	closeFunc()
+
+	list := p.ResourceList(rs, firstCursor, cursor)
+	httputils.MustWriteJSON(w, 200, list)
+}
+
+func (api NetworkHandlerAPI) GetAccountDataByKeyHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	address := vars["id"]
+	key := vars["key"]
+
+	readFunc := func() (payload interface{}, err error) {
+		found, err := block.ExistsBlockAccountData(api.storage, address, key)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, errors.BlockAccountDataDoesNotExists
+		}
+		d, err := block.GetBlockAccountData(api.storage, address, key)
+		if err != nil {
+			return nil, err
+		}
+		payload = resource.NewAccountData(d)
+		return payload, nil
+	}
+
+	payload, err := readFunc()
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	httputils.MustWriteJSON(w, 200, payload)
+}
+
 func (api NetworkHandlerAPI) GetFrozenAccountsByAccountHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	address := vars["id"]
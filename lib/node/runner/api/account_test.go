@@ -9,10 +9,13 @@ import (
 	"testing"
 
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/client"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network/httputils"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/voting"
 
 	"github.com/stretchr/testify/require"
 )
@@ -208,3 +211,254 @@ func TestGetAccountsHandler(t *testing.T) {
 	}
 
 }
+
+func TestGetAccountDataHandler(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	ba := block.TestMakeBlockAccount()
+	ba.DataEntries = 3
+	ba.MustSave(storage)
+
+	keys := []string{"endpoint", "kyc", "name"}
+	for _, key := range keys {
+		require.NoError(t, block.NewBlockAccountData(ba.Address, key, "showme-"+key).Save(storage))
+	}
+
+	{ // list
+		url := strings.Replace(GetAccountDataHandlerPattern, "{id}", ba.Address, -1)
+		respBody := request(ts, url, false)
+		defer respBody.Close()
+
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+		recv := make(map[string]interface{})
+		common.MustUnmarshalJSON(readByte, &recv)
+		records := recv["_embedded"].(map[string]interface{})["records"].([]interface{})
+
+		require.Equal(t, len(keys), len(records))
+		for i, r := range records {
+			d := r.(map[string]interface{})
+			require.Equal(t, ba.Address, d["address"])
+			require.Equal(t, keys[i], d["key"])
+			require.Equal(t, "showme-"+keys[i], d["value"])
+		}
+	}
+
+	{ // by key
+		url := strings.Replace(GetAccountDataByKeyHandlerPattern, "{id}", ba.Address, -1)
+		url = strings.Replace(url, "{key}", "kyc", -1)
+		respBody := request(ts, url, false)
+		defer respBody.Close()
+
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+		recv := make(map[string]interface{})
+		common.MustUnmarshalJSON(readByte, &recv)
+
+		require.Equal(t, "kyc", recv["key"])
+		require.Equal(t, "showme-kyc", recv["value"])
+	}
+
+	{ // unknown key
+		url := strings.Replace(GetAccountDataByKeyHandlerPattern, "{id}", ba.Address, -1)
+		url = strings.Replace(url, "{key}", "unknown", -1)
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	{ // unknown account
+		url := strings.Replace(GetAccountDataHandlerPattern, "{id}", keypair.Random().Address(), -1)
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestGetAccountProofHandler(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	genesis := block.GetLatestBlock(storage)
+
+	ba := block.TestMakeBlockAccount()
+	ba.MustSave(storage)
+
+	// makeBlock stores the next block with the state root of current accounts
+	makeBlock := func() block.Block {
+		latest := block.GetLatestBlock(storage)
+		root, err := statedb.CommitAccounts(storage, latest.StateRoot, []string{ba.Address})
+		require.NoError(t, err)
+
+		basis := voting.Basis{Height: latest.Height + 1, BlockHash: latest.Hash}
+		blk := *block.NewBlockWithVersion(block.GetBlockVersion(basis.Height), "proposer", basis, "", nil, common.NowISO8601(), root)
+		blk.MustSave(storage)
+		return blk
+	}
+
+	getProof := func(height uint64) (proof client.AccountProof, code int) {
+		url := strings.Replace(GetAccountProofHandlerPattern, "{id}", ba.Address, -1)
+		url += "?height=" + strconv.FormatUint(height, 10)
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		readByte, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if resp.StatusCode == http.StatusOK {
+			common.MustUnmarshalJSON(readByte, &proof)
+		}
+		return proof, resp.StatusCode
+	}
+
+	// the first state trie is made from all the accounts
+	blk := makeBlock()
+	{
+		header := client.BlockHeader{StateRoot: blk.StateRoot}
+
+		proof, code := getProof(blk.Height)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, blk.Hash, proof.Block)
+		require.Equal(t, blk.StateRoot, proof.Header.StateRoot)
+		require.Equal(t, ba.Balance, proof.Account.Balance)
+		require.True(t, proof.Verify(header))
+
+		// the proof does not match with the different balance
+		proof.Account.Balance++
+		require.False(t, proof.Verify(header))
+	}
+
+	// balance is changed in the next block
+	ba.Balance++
+	ba.MustSave(storage)
+	next := makeBlock()
+	{
+		proof, code := getProof(next.Height)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, ba.Balance, proof.Account.Balance)
+		require.True(t, proof.Verify(client.BlockHeader{StateRoot: next.StateRoot}))
+		require.False(t, proof.Verify(client.BlockHeader{StateRoot: blk.StateRoot}))
+
+		// the proof of previous block has the previous balance
+		proof, code = getProof(blk.Height)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, ba.Balance-1, proof.Account.Balance)
+	}
+
+	{ // genesis block does not have the state root
+		_, code := getProof(genesis.Height)
+		require.Equal(t, httputils.StatusCode(errors.AccountProofNotAvailable), code)
+	}
+
+	{ // unknown address
+		url := strings.Replace(GetAccountProofHandlerPattern, "{id}", keypair.Random().Address(), -1)
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+}
+
+func TestGetAccountHandlerWithHeight(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	// the account is changed in the next two blocks
+	ba := block.TestMakeBlockAccount()
+	var blocks []block.Block
+	for i := 0; i < 2; i++ {
+		blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(storage), nil)
+		blk.MustSave(storage)
+		blocks = append(blocks, blk)
+
+		ba.Balance++
+		ba.SequenceID++
+		ba.MustSave(storage)
+		require.NoError(t, block.SaveBlockAccountHistories(storage, blk.Height, []string{ba.Address}))
+	}
+	latest := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(storage), nil)
+	latest.MustSave(storage)
+
+	getAccount := func(height uint64) (recv map[string]interface{}, code int) {
+		url := strings.Replace(GetAccountHandlerPattern, "{id}", ba.Address, -1)
+		url += "?height=" + strconv.FormatUint(height, 10)
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		readByte, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		common.MustUnmarshalJSON(readByte, &recv)
+		return recv, resp.StatusCode
+	}
+
+	{ // before the account is created
+		_, code := getAccount(blocks[0].Height - 1)
+		require.Equal(t, http.StatusNotFound, code)
+	}
+
+	for i, blk := range blocks {
+		recv, code := getAccount(blk.Height)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, ba.Address, recv["address"])
+		require.Equal(t, float64(i+1), recv["sequence_id"])
+		require.Equal(t, (ba.Balance - common.Amount(len(blocks)-i-1)).String(), recv["balance"])
+	}
+
+	{ // not changed in the latest block
+		recv, code := getAccount(latest.Height)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, float64(latest.Height), recv["height"])
+		require.Equal(t, float64(blocks[1].Height), recv["changed"])
+		require.Equal(t, ba.Balance.String(), recv["balance"])
+	}
+
+	{ // unknown height
+		_, code := getAccount(latest.Height + 1)
+		require.Equal(t, httputils.StatusCode(errors.BlockNotFound), code)
+	}
+
+	{ // invalid height
+		url := strings.Replace(GetAccountHandlerPattern, "{id}", ba.Address, -1) + "?height=showme"
+		req, _ := http.NewRequest("GET", ts.URL+url, nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+
+	{ // the merged account
+		require.NoError(t, ba.Remove(storage))
+		require.NoError(t, block.SaveBlockAccountHistories(storage, latest.Height, []string{ba.Address}))
+
+		_, code := getAccount(latest.Height)
+		require.Equal(t, http.StatusNotFound, code)
+		recv, code := getAccount(blocks[1].Height)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, ba.Balance.String(), recv["balance"])
+	}
+
+	{ // before the history is available
+		require.NoError(t, block.SaveBlockAccountHistoryStart(storage, blocks[1].Height))
+
+		_, code := getAccount(blocks[0].Height)
+		require.Equal(t, http.StatusGone, code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
 	obs "boscoin.io/sebak/lib/common/observer"
 	"boscoin.io/sebak/lib/network"
 	"boscoin.io/sebak/lib/node"
@@ -21,12 +22,16 @@ const (
 	GetAccountsHandlerPattern              = "/accounts"
 	GetAccountOperationsHandlerPattern     = "/accounts/{id}/operations"
 	GetAccountFrozenAccountHandlerPattern  = "/accounts/{id}/frozen-accounts"
+	GetAccountDataHandlerPattern           = "/accounts/{id}/data"
+	GetAccountDataByKeyHandlerPattern      = "/accounts/{id}/data/{key}"
+	GetAccountProofHandlerPattern          = "/accounts/{id}/proof"
 	GetFrozenAccountHandlerPattern         = "/frozen-accounts"
 	GetTransactionsHandlerPattern          = "/transactions"
 	GetTransactionByHashHandlerPattern     = "/transactions/{id}"
 	GetTransactionOperationsHandlerPattern = "/transactions/{id}/operations"
 	GetTransactionOperationHandlerPattern  = "/transactions/{id}/operations/{opindex}"
 	GetTransactionStatusHandlerPattern     = "/transactions/{id}/status"
+	GetTransactionProofHandlerPattern      = "/transactions/{id}/proof"
 	PostTransactionPattern                 = "/transactions"
 	GetBlocksHandlerPattern                = "/blocks"
 	GetBlockHandlerPattern                 = "/blocks/{hashOrHeight}"
@@ -37,14 +42,17 @@ const (
 type NetworkHandlerAPI struct {
 	localNode      *node.LocalNode
 	network        network.Network
-	storage        *storage.LevelDBBackend
+	storage        storage.Backend
 	urlPrefix      string
 	version        string
 	nodeInfo       node.NodeInfo
 	GetLatestBlock func() block.Block
+	GetBaseFee     func() common.Amount
+
+	RejectedTransactions *block.RejectedTransactions
 }
 
-func NewNetworkHandlerAPI(localNode *node.LocalNode, network network.Network, storage *storage.LevelDBBackend, urlPrefix string, nodeInfo node.NodeInfo) *NetworkHandlerAPI {
+func NewNetworkHandlerAPI(localNode *node.LocalNode, network network.Network, storage storage.Backend, urlPrefix string, nodeInfo node.NodeInfo) *NetworkHandlerAPI {
 	return &NetworkHandlerAPI{
 		localNode: localNode,
 		network:   network,
@@ -59,7 +67,7 @@ func (api NetworkHandlerAPI) HandlerURLPattern(pattern string) string {
 	return fmt.Sprintf("%s/%s%s", api.urlPrefix, api.version, pattern)
 }
 
-func TriggerEvent(st *storage.LevelDBBackend, transactions []*transaction.Transaction) {
+func TriggerEvent(st storage.Backend, transactions []*transaction.Transaction) {
 	var (
 		t    = obs.ResourceObserver.Trigger
 		cond = obs.NewCondition
@@ -20,7 +20,7 @@ const (
 	QueryPattern = "cursor={cursor}&limit={limit}&reverse={reverse}&type={type}"
 )
 
-func prepareAPIServer() (*httptest.Server, *storage.LevelDBBackend) {
+func prepareAPIServer() (*httptest.Server, storage.Backend) {
 	storage := block.InitTestBlockchain()
 	apiHandler := NetworkHandlerAPI{storage: storage}
 
@@ -29,11 +29,15 @@ func prepareAPIServer() (*httptest.Server, *storage.LevelDBBackend) {
 	router.HandleFunc(GetAccountsHandlerPattern, apiHandler.GetAccountsHandler).Methods("POST")
 	router.HandleFunc(GetAccountTransactionsHandlerPattern, apiHandler.GetTransactionsByAccountHandler).Methods("GET")
 	router.HandleFunc(GetAccountOperationsHandlerPattern, apiHandler.GetOperationsByAccountHandler).Methods("GET")
+	router.HandleFunc(GetAccountDataHandlerPattern, apiHandler.GetAccountDataHandler).Methods("GET")
+	router.HandleFunc(GetAccountDataByKeyHandlerPattern, apiHandler.GetAccountDataByKeyHandler).Methods("GET")
+	router.HandleFunc(GetAccountProofHandlerPattern, apiHandler.GetAccountProofHandler).Methods("GET")
 	router.HandleFunc(GetTransactionOperationHandlerPattern, apiHandler.GetOperationsByTxHashOpIndexHandler).Methods("GET")
 	router.HandleFunc(GetTransactionsHandlerPattern, apiHandler.GetTransactionsHandler).Methods("GET")
 	router.HandleFunc(GetTransactionByHashHandlerPattern, apiHandler.GetTransactionByHashHandler).Methods("GET")
 	router.HandleFunc(GetTransactionStatusHandlerPattern, apiHandler.GetTransactionStatusByHashHandler).Methods("GET")
 	router.HandleFunc(GetTransactionOperationsHandlerPattern, apiHandler.GetOperationsByTxHandler).Methods("GET")
+	router.HandleFunc(GetTransactionProofHandlerPattern, apiHandler.GetTransactionProofHandler).Methods("GET")
 	router.HandleFunc(GetBlocksHandlerPattern, apiHandler.GetBlocksHandler).Methods("GET")
 	router.HandleFunc(GetBlockHandlerPattern, apiHandler.GetBlockHandler).Methods("GET")
 	router.HandleFunc(PostSubscribePattern, apiHandler.PostSubscribeHandler).Methods("POST")
@@ -41,7 +45,7 @@ func prepareAPIServer() (*httptest.Server, *storage.LevelDBBackend) {
 	return ts, storage
 }
 
-func prepareTxsOps(storage *storage.LevelDBBackend, count int) (*keypair.Full, *keypair.Full, []block.BlockTransaction, []block.BlockOperation) {
+func prepareTxsOps(storage storage.Backend, count int) (*keypair.Full, *keypair.Full, []block.BlockTransaction, []block.BlockOperation) {
 	kp, kpTarget, btList := prepareTxs(storage, count)
 	var boList []block.BlockOperation
 	for _, bt := range btList {
@@ -55,7 +59,7 @@ func prepareTxsOps(storage *storage.LevelDBBackend, count int) (*keypair.Full, *
 	return kp, kpTarget, btList, boList
 }
 
-func prepareOps(storage *storage.LevelDBBackend, count int) (*keypair.Full, *keypair.Full, []block.BlockOperation) {
+func prepareOps(storage storage.Backend, count int) (*keypair.Full, *keypair.Full, []block.BlockOperation) {
 	kp, kpTarget, btList := prepareTxs(storage, count)
 	var boList []block.BlockOperation
 	for _, bt := range btList {
@@ -68,7 +72,7 @@ func prepareOps(storage *storage.LevelDBBackend, count int) (*keypair.Full, *key
 
 	return kp, kpTarget, boList
 }
-func prepareOpsWithoutSave(count int, st *storage.LevelDBBackend) (*keypair.Full, block.Block, []block.BlockOperation) {
+func prepareOpsWithoutSave(count int, st storage.Backend) (*keypair.Full, block.Block, []block.BlockOperation) {
 	kp := keypair.Random()
 	var txs []transaction.Transaction
 	var txHashes []string
@@ -93,7 +97,7 @@ func prepareOpsWithoutSave(count int, st *storage.LevelDBBackend) (*keypair.Full
 	return kp, theBlock, boList
 }
 
-func prepareBlkTxOpWithoutSave(st *storage.LevelDBBackend) (*keypair.Full, block.Block, block.BlockTransaction, block.BlockOperation) {
+func prepareBlkTxOpWithoutSave(st storage.Backend) (*keypair.Full, block.Block, block.BlockTransaction, block.BlockOperation) {
 	kp := keypair.Random()
 	var txHashes []string
 	tx := transaction.TestMakeTransactionWithKeypair(networkID, 1, kp)
@@ -109,7 +113,7 @@ func prepareBlkTxOpWithoutSave(st *storage.LevelDBBackend) (*keypair.Full, block
 
 	return kp, theBlock, bt, bo
 }
-func prepareTxsWithKeyPair(storage *storage.LevelDBBackend, source, target *keypair.Full, count int) (*keypair.Full, *keypair.Full, []block.BlockTransaction) {
+func prepareTxsWithKeyPair(storage storage.Backend, source, target *keypair.Full, count int) (*keypair.Full, *keypair.Full, []block.BlockTransaction) {
 	if source == nil {
 		source = keypair.Random()
 	}
@@ -140,11 +144,11 @@ func prepareTxsWithKeyPair(storage *storage.LevelDBBackend, source, target *keyp
 
 }
 
-func prepareTxs(storage *storage.LevelDBBackend, count int) (*keypair.Full, *keypair.Full, []block.BlockTransaction) {
+func prepareTxs(storage storage.Backend, count int) (*keypair.Full, *keypair.Full, []block.BlockTransaction) {
 	return prepareTxsWithKeyPair(storage, nil, nil, count)
 }
 
-func prepareTxWithOperations(storage *storage.LevelDBBackend, count int) (*keypair.Full, *keypair.Full, block.BlockTransaction) {
+func prepareTxWithOperations(storage storage.Backend, count int) (*keypair.Full, *keypair.Full, block.BlockTransaction) {
 	source := keypair.Random()
 	target := keypair.Random()
 	tx := transaction.TestMakeTransactionWithKeypair(networkID, count, source, target)
@@ -159,7 +163,7 @@ func prepareTxWithOperations(storage *storage.LevelDBBackend, count int) (*keypa
 	return source, target, bt
 }
 
-func prepareTxsWithoutSave(count int, st *storage.LevelDBBackend) (*keypair.Full, []block.BlockTransaction) {
+func prepareTxsWithoutSave(count int, st storage.Backend) (*keypair.Full, []block.BlockTransaction) {
 	kp := keypair.Random()
 	var txs []transaction.Transaction
 	var txHashes []string
@@ -178,7 +182,7 @@ func prepareTxsWithoutSave(count int, st *storage.LevelDBBackend) (*keypair.Full
 	return kp, btList
 }
 
-func prepareTxWithoutSave(st *storage.LevelDBBackend) (*keypair.Full, *transaction.Transaction, *block.BlockTransaction) {
+func prepareTxWithoutSave(st storage.Backend) (*keypair.Full, *transaction.Transaction, *block.BlockTransaction) {
 	kp := keypair.Random()
 	tx := transaction.TestMakeTransactionWithKeypair(networkID, 1, kp)
 
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/node"
 )
@@ -33,6 +34,12 @@ func (api NetworkHandlerAPI) GetNodeInfoHandler(w http.ResponseWriter, r *http.R
 		}
 	}
 
+	if api.GetBaseFee != nil {
+		nodeInfo.Block.BaseFee = api.GetBaseFee()
+	}
+
+	nodeInfo.Block.LowestFullHeight = block.GetLowestFullBlockHeight(api.storage)
+
 	var b []byte
 	var err error
 	if b, err = common.JSONMarshalIndent(nodeInfo); err != nil {
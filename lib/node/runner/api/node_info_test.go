@@ -77,6 +77,9 @@ func TestAPIGetNodeInfoHandler(t *testing.T) {
 		GetLatestBlock: func() block.Block {
 			return block.GetLatestBlock(st)
 		},
+		GetBaseFee: func() common.Amount {
+			return common.BaseFee.MustMult(2)
+		},
 	}
 
 	router := mux.NewRouter()
@@ -105,6 +108,7 @@ func TestAPIGetNodeInfoHandler(t *testing.T) {
 	require.Equal(t, latestBlock.Hash, receivedNodeInfo.Block.Hash)
 	require.Equal(t, latestBlock.TotalTxs, receivedNodeInfo.Block.TotalTxs)
 	require.Equal(t, latestBlock.TotalOps, receivedNodeInfo.Block.TotalOps)
+	require.Equal(t, common.BaseFee.MustMult(2), receivedNodeInfo.Block.BaseFee)
 
 	js, _ := json.Marshal(policy)
 	rjs, _ := json.Marshal(receivedNodeInfo.Policy)
@@ -34,7 +34,7 @@ func (api NetworkHandlerAPI) GetOperationsByTxHashOpIndexHandler(w http.Response
 
 	payload, err := readFunc()
 	if err != nil {
-		httputils.WriteJSONError(w, err)
+		httputils.WriteJSONError(w, api.checkPrunedTransaction(txHash, err))
 		return
 	}
 
@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/nvellon/hal"
@@ -21,10 +22,13 @@ func NewAccount(ba *block.BlockAccount) *Account {
 
 func (a Account) GetMap() hal.Entry {
 	return hal.Entry{
-		"address":     a.ba.Address,
-		"sequence_id": a.ba.SequenceID,
-		"balance":     a.ba.Balance,
-		"linked":      a.ba.Linked,
+		"address":      a.ba.Address,
+		"sequence_id":  a.ba.SequenceID,
+		"balance":      a.ba.Balance,
+		"linked":       a.ba.Linked,
+		"signers":      a.ba.Signers,
+		"thresholds":   a.ba.Thresholds,
+		"data_entries": a.ba.DataEntries,
 	}
 }
 
@@ -33,8 +37,9 @@ func (a Account) Resource() *hal.Resource {
 	accountID := a.ba.Address
 
 	r := hal.NewResource(a, a.LinkSelf())
-	r.AddLink("transactions", hal.NewLink(strings.Replace(URLAccountTransactions, "{id}", address, -1)+"{?cursor,limit,order}", hal.LinkAttr{"templated": true}))
+	r.AddLink("transactions", hal.NewLink(strings.Replace(URLAccountTransactions, "{id}", address, -1)+"{?cursor,limit,order,memo,memo_type}", hal.LinkAttr{"templated": true}))
 	r.AddLink("operations", hal.NewLink(strings.Replace(URLAccountOperations, "{id}", accountID, -1)+"{?cursor,limit,order}", hal.LinkAttr{"templated": true}))
+	r.AddLink("data", hal.NewLink(strings.Replace(URLAccountData, "{id}", accountID, -1)+"{?cursor,limit,order}", hal.LinkAttr{"templated": true}))
 	return r
 }
 
@@ -42,3 +47,75 @@ func (a Account) LinkSelf() string {
 	address := a.ba.Address
 	return strings.Replace(URLAccounts, "{id}", address, -1)
 }
+
+// AccountHistory is the balance and sequence ID of account at the block
+// height; `Height` is the requested height and `BlockAccountHistory.Height` is
+// the height of the block, which changed the account last.
+type AccountHistory struct {
+	h      *block.BlockAccountHistory
+	Height uint64
+}
+
+func NewAccountHistory(h *block.BlockAccountHistory, height uint64) *AccountHistory {
+	return &AccountHistory{
+		h:      h,
+		Height: height,
+	}
+}
+
+func (a AccountHistory) GetMap() hal.Entry {
+	return hal.Entry{
+		"address":     a.h.Address,
+		"height":      a.Height,
+		"changed":     a.h.Height,
+		"sequence_id": a.h.SequenceID,
+		"balance":     a.h.Balance,
+	}
+}
+
+func (a AccountHistory) Resource() *hal.Resource {
+	r := hal.NewResource(a, a.LinkSelf())
+	r.AddLink("account", hal.NewLink(strings.Replace(URLAccounts, "{id}", a.h.Address, -1)))
+	return r
+}
+
+func (a AccountHistory) LinkSelf() string {
+	return fmt.Sprintf("%s?height=%d", strings.Replace(URLAccounts, "{id}", a.h.Address, -1), a.Height)
+}
+
+// AccountProof has the account in the state trie of the block and the proof
+// nodes of trie, which can be checked with the `StateRoot` of header.
+type AccountProof struct {
+	ba    block.BlockAccount
+	blk   *block.Block
+	proof [][]byte
+}
+
+func NewAccountProof(blk *block.Block, ba block.BlockAccount, proof [][]byte) *AccountProof {
+	return &AccountProof{
+		ba:    ba,
+		blk:   blk,
+		proof: proof,
+	}
+}
+
+func (a AccountProof) GetMap() hal.Entry {
+	return hal.Entry{
+		"address": a.ba.Address,
+		"block":   a.blk.Hash,
+		"header":  a.blk.Header,
+		"account": a.ba,
+		"proof":   a.proof,
+	}
+}
+
+func (a AccountProof) Resource() *hal.Resource {
+	r := hal.NewResource(a, a.LinkSelf())
+	r.AddLink("account", hal.NewLink(strings.Replace(URLAccounts, "{id}", a.ba.Address, -1)))
+	r.AddLink("block", hal.NewLink(strings.Replace(URLBlocks, "{id}", a.blk.Hash, -1)))
+	return r
+}
+
+func (a AccountProof) LinkSelf() string {
+	return fmt.Sprintf("%s?height=%d", strings.Replace(URLAccountProof, "{id}", a.ba.Address, -1), a.blk.Height)
+}
@@ -0,0 +1,40 @@
+package resource
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/nvellon/hal"
+
+	"boscoin.io/sebak/lib/block"
+)
+
+type AccountData struct {
+	d *block.BlockAccountData
+}
+
+func NewAccountData(d *block.BlockAccountData) *AccountData {
+	a := &AccountData{
+		d: d,
+	}
+	return a
+}
+
+func (a AccountData) GetMap() hal.Entry {
+	return hal.Entry{
+		"address": a.d.Address,
+		"key":     a.d.Key,
+		"value":   a.d.Value,
+	}
+}
+
+func (a AccountData) Resource() *hal.Resource {
+	r := hal.NewResource(a, a.LinkSelf())
+	r.AddLink("account", hal.NewLink(strings.Replace(URLAccounts, "{id}", a.d.Address, -1)))
+	return r
+}
+
+func (a AccountData) LinkSelf() string {
+	l := strings.Replace(URLAccountDataByKey, "{id}", a.d.Address, -1)
+	return strings.Replace(l, "{key}", url.PathEscape(a.d.Key), -1)
+}
@@ -8,12 +8,16 @@ const (
 	URLAccountTransactions   = APIPrefix + APIVersionV1 + "/accounts/{id}/transactions"
 	URLAccountOperations     = APIPrefix + APIVersionV1 + "/accounts/{id}/operations"
 	URLAccountFrozenAccounts = APIPrefix + APIVersionV1 + "/accounts/{id}/frozen-accounts"
+	URLAccountData           = APIPrefix + APIVersionV1 + "/accounts/{id}/data"
+	URLAccountDataByKey      = APIPrefix + APIVersionV1 + "/accounts/{id}/data/{key}"
+	URLAccountProof          = APIPrefix + APIVersionV1 + "/accounts/{id}/proof"
 	URLFrozenAccounts        = APIPrefix + APIVersionV1 + "/frozen-accounts"
 	URLTransactions          = APIPrefix + APIVersionV1 + "/transactions"
 	URLTransactionByHash     = APIPrefix + APIVersionV1 + "/transactions/{id}"
 	URLTransactionOperations = APIPrefix + APIVersionV1 + "/transactions/{id}/operations"
 	URLTransactionOperation  = APIPrefix + APIVersionV1 + "/transactions/{id}/operations/{opindex}"
 	URLTransactionStatus     = APIPrefix + APIVersionV1 + "/transactions/{id}/status"
+	URLTransactionProof      = APIPrefix + APIVersionV1 + "/transactions/{id}/proof"
 	URLOperations            = APIPrefix + APIVersionV1 + "/operations/{id}"
 	URLBlocks                = APIPrefix + APIVersionV1 + "/blocks/{id}"
 )
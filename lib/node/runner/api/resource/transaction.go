@@ -2,6 +2,8 @@ package resource
 
 import (
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/transaction"
 	"github.com/nvellon/hal"
 	"strings"
@@ -21,7 +23,7 @@ func NewTransaction(bt *block.BlockTransaction, tx transaction.Transaction) *Tra
 }
 
 func (t Transaction) GetMap() hal.Entry {
-	return hal.Entry{
+	entry := hal.Entry{
 		"hash":            t.bt.Hash,
 		"block":           t.bt.Block,
 		"source":          t.bt.Source,
@@ -31,6 +33,11 @@ func (t Transaction) GetMap() hal.Entry {
 		"operation_count": len(t.bt.Operations),
 		"operations":      t.tx.B.Operations,
 	}
+	if t.tx.B.Memo != nil {
+		entry["memo"] = t.tx.B.Memo
+	}
+
+	return entry
 }
 func (t Transaction) Resource() *hal.Resource {
 
@@ -45,8 +52,9 @@ func (t Transaction) LinkSelf() string {
 }
 
 type TransactionStatus struct {
-	Hash   string
-	Status string
+	Hash     string
+	Status   string
+	rejected *block.RejectedTransaction
 }
 
 func NewTransactionStatus(hash, status string) *TransactionStatus {
@@ -57,11 +65,35 @@ func NewTransactionStatus(hash, status string) *TransactionStatus {
 	return t
 }
 
+// NewRejectedTransactionStatus makes the "rejected" status, which has the
+// reason and the result of each operation. The transaction evicted from the
+// pool has the "replaced" or "evicted" status.
+func NewRejectedTransactionStatus(rt *block.RejectedTransaction) *TransactionStatus {
+	status := "rejected"
+	switch rt.Code {
+	case errors.TransactionReplaced.Code:
+		status = "replaced"
+	case errors.TransactionEvictedFromPool.Code:
+		status = "evicted"
+	}
+
+	t := NewTransactionStatus(rt.Hash, status)
+	t.rejected = rt
+	return t
+}
+
 func (t TransactionStatus) GetMap() hal.Entry {
-	return hal.Entry{
+	entry := hal.Entry{
 		"hash":   t.Hash,
 		"status": t.Status,
 	}
+	if t.rejected != nil {
+		entry["code"] = t.rejected.Code
+		entry["message"] = t.rejected.Message
+		entry["operations"] = t.rejected.Operations
+		entry["rejected"] = t.rejected.Rejected
+	}
+	return entry
 }
 func (t TransactionStatus) Resource() *hal.Resource {
 
@@ -73,3 +105,39 @@ func (t TransactionStatus) Resource() *hal.Resource {
 func (t TransactionStatus) LinkSelf() string {
 	return strings.Replace(URLTransactionStatus, "{id}", t.Hash, -1)
 }
+
+// TransactionProof has the Merkle proof of the transaction and the header of
+// the block, which includes the transaction.
+type TransactionProof struct {
+	Hash  string
+	blk   *block.Block
+	proof common.MerkleProof
+}
+
+func NewTransactionProof(hash string, blk *block.Block, proof common.MerkleProof) *TransactionProof {
+	return &TransactionProof{
+		Hash:  hash,
+		blk:   blk,
+		proof: proof,
+	}
+}
+
+func (t TransactionProof) GetMap() hal.Entry {
+	return hal.Entry{
+		"hash":   t.Hash,
+		"block":  t.blk.Hash,
+		"header": t.blk.Header,
+		"proof":  t.proof,
+	}
+}
+
+func (t TransactionProof) Resource() *hal.Resource {
+	r := hal.NewResource(t, t.LinkSelf())
+	r.AddLink("transaction", hal.NewLink(strings.Replace(URLTransactionByHash, "{id}", t.Hash, -1)))
+	r.AddLink("block", hal.NewLink(strings.Replace(URLBlocks, "{id}", t.blk.Hash, -1)))
+	return r
+}
+
+func (t TransactionProof) LinkSelf() string {
+	return strings.Replace(URLTransactionProof, "{id}", t.Hash, -1)
+}
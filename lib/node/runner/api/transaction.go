@@ -12,6 +12,7 @@ import (
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network/httputils"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
+	"boscoin.io/sebak/lib/transaction"
 )
 
 func (api NetworkHandlerAPI) GetTransactionsHandler(w http.ResponseWriter, r *http.Request) {
@@ -48,6 +49,16 @@ func (api NetworkHandlerAPI) GetTransactionsHandler(w http.ResponseWriter, r *ht
 	httputils.MustWriteJSON(w, 200, list)
 }
 
+// checkPrunedTransaction returns `errors.BlockTransactionPruned` instead of
+// `err`, if the transaction was pruned.
+func (api NetworkHandlerAPI) checkPrunedTransaction(hash string, err error) error {
+	if pruned, _ := block.IsPrunedBlockTransaction(api.storage, hash); pruned {
+		return errors.BlockTransactionPruned
+	}
+
+	return err
+}
+
 func (api NetworkHandlerAPI) GetTransactionByHashHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	key := vars["id"]
@@ -58,7 +69,7 @@ func (api NetworkHandlerAPI) GetTransactionByHashHandler(w http.ResponseWriter,
 		return
 	}
 	if !found {
-		httputils.WriteJSONError(w, errors.BlockTransactionDoesNotExists)
+		httputils.WriteJSONError(w, api.checkPrunedTransaction(key, errors.BlockTransactionDoesNotExists))
 		return
 	}
 	bt, err := block.GetBlockTransaction(api.storage, key)
@@ -76,6 +87,41 @@ func (api NetworkHandlerAPI) GetTransactionByHashHandler(w http.ResponseWriter,
 	httputils.MustWriteJSON(w, 200, tx)
 }
 
+// GetTransactionProofHandler returns the Merkle proof of the confirmed
+// transaction with the header of block; the proof can be checked with the
+// `TransactionsRoot` of header.
+func (api NetworkHandlerAPI) GetTransactionProofHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	key := vars["id"]
+
+	found, err := block.ExistsBlockTransaction(api.storage, key)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+	if !found {
+		httputils.WriteJSONError(w, api.checkPrunedTransaction(key, errors.BlockTransactionDoesNotExists))
+		return
+	}
+	bt, err := block.GetBlockTransaction(api.storage, key)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+	blk, err := block.GetBlock(api.storage, bt.Block)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+	proof, err := blk.TransactionProof(bt.Hash)
+	if err != nil {
+		httputils.WriteJSONError(w, err)
+		return
+	}
+
+	httputils.MustWriteJSON(w, 200, resource.NewTransactionProof(bt.Hash, &blk, proof))
+}
+
 func (api NetworkHandlerAPI) GetTransactionsByAccountHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	address := vars["id"]
@@ -90,7 +136,26 @@ func (api NetworkHandlerAPI) GetTransactionsByAccountHandler(w http.ResponseWrit
 	var firstCursor []byte
 	var cursor []byte
 	var txs []resource.Resource
-	iterFunc, closeFunc := block.GetBlockTransactionsByAccount(api.storage, address, options)
+
+	var iterFunc func() (block.BlockTransaction, bool, []byte)
+	var closeFunc func()
+	if value := r.URL.Query().Get("memo"); len(value) > 0 {
+		// `memo_type` is required with `memo`; same value of the different
+		// memo types is indexed separately.
+		var memoType transaction.MemoType
+		if err = memoType.UnmarshalText([]byte(r.URL.Query().Get("memo_type"))); err != nil {
+			httputils.WriteJSONError(w, errors.InvalidQueryString)
+			return
+		}
+		memo := transaction.NewMemo(memoType, value)
+		if err = memo.IsWellFormed(); err != nil {
+			httputils.WriteJSONError(w, errors.InvalidQueryString)
+			return
+		}
+		iterFunc, closeFunc = block.GetBlockTransactionsByAccountMemo(api.storage, address, memo, options)
+	} else {
+		iterFunc, closeFunc = block.GetBlockTransactionsByAccount(api.storage, address, options)
+	}
 	for {
 		t, hasNext, c := iterFunc()
 		if !hasNext {
@@ -117,16 +182,29 @@ func (api NetworkHandlerAPI) GetTransactionStatusByHashHandler(w http.ResponseWr
 	key := vars["id"]
 
 	status := "notfound"
+	var rejected *block.RejectedTransaction
+	if rt, found := api.RejectedTransactions.Get(key); found {
+		status = "rejected"
+		rejected = &rt
+	}
 	if found, _ := block.ExistsTransactionPool(api.storage, key); found {
 		status = "submitted"
 	}
 	if found, _ := block.ExistsBlockTransaction(api.storage, key); found {
 		status = "confirmed"
 	}
+	if found, _ := block.IsPrunedBlockTransaction(api.storage, key); found {
+		status = "confirmed"
+	}
 
-	payload := resource.NewTransactionStatus(key, status)
+	var payload *resource.TransactionStatus
+	if status == "rejected" {
+		payload = resource.NewRejectedTransactionStatus(rejected)
+	} else {
+		payload = resource.NewTransactionStatus(key, status)
+	}
 
-	if httputils.IsEventStream(r) && status != "confirmed" {
+	if httputils.IsEventStream(r) && status != "confirmed" && status != "rejected" {
 
 		txStatusRenderFunc := func(args ...interface{}) ([]byte, error) {
 			if len(args) <= 1 {
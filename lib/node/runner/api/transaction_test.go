@@ -5,13 +5,20 @@ import (
 	"bufio"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/node/runner/api/resource"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/voting"
 )
 
 func TestGetTransactionByHashHandler(t *testing.T) {
@@ -94,6 +101,72 @@ func TestGetTransactionStatusByHashHandler(t *testing.T) {
 	}
 }
 
+func TestGetTransactionStatusByHashHandlerRejected(t *testing.T) {
+	storage := block.InitTestBlockchain()
+	defer storage.Close()
+
+	rejected := block.NewRejectedTransactions(10, time.Hour)
+	apiHandler := NetworkHandlerAPI{storage: storage, RejectedTransactions: rejected}
+	router := mux.NewRouter()
+	router.HandleFunc(GetTransactionStatusHandlerPattern, apiHandler.GetTransactionStatusByHashHandler).Methods("GET")
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	_, tx, _ := prepareTxWithoutSave(storage)
+	results := []transaction.OperationResult{
+		transaction.NewOperationResult(0, tx.B.Operations[0], errors.BlockAccountDoesNotExists),
+	}
+	rejected.Add(block.NewRejectedTransaction(tx.GetHash(), errors.BlockAccountDoesNotExists, results))
+
+	{ // rejected
+		respBody := request(ts, strings.Replace(GetTransactionStatusHandlerPattern, "{id}", tx.GetHash(), -1), false)
+		defer respBody.Close()
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		var status map[string]interface{}
+		common.MustUnmarshalJSON(readByte, &status)
+		require.Equal(t, tx.GetHash(), status["hash"])
+		require.Equal(t, "rejected", status["status"])
+		require.Equal(t, float64(errors.BlockAccountDoesNotExists.Code), status["code"])
+
+		operations := status["operations"].([]interface{})
+		require.Equal(t, 1, len(operations))
+		op := operations[0].(map[string]interface{})
+		require.Equal(t, float64(0), op["index"])
+		require.Equal(t, float64(errors.BlockAccountDoesNotExists.Code), op["code"])
+	}
+
+	{ // submitted again after rejected
+		block.SaveTransactionPool(storage, *tx)
+
+		respBody := request(ts, strings.Replace(GetTransactionStatusHandlerPattern, "{id}", tx.GetHash(), -1), false)
+		defer respBody.Close()
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		var status map[string]interface{}
+		common.MustUnmarshalJSON(readByte, &status)
+		require.Equal(t, "submitted", status["status"])
+		require.Nil(t, status["operations"])
+	}
+
+	{ // replaced by the higher fee transaction
+		hash := "findme"
+		rejected.Add(block.NewRejectedTransaction(hash, errors.TransactionReplaced, nil))
+
+		respBody := request(ts, strings.Replace(GetTransactionStatusHandlerPattern, "{id}", hash, -1), false)
+		defer respBody.Close()
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		var status map[string]interface{}
+		common.MustUnmarshalJSON(readByte, &status)
+		require.Equal(t, "replaced", status["status"])
+		require.Equal(t, float64(errors.TransactionReplaced.Code), status["code"])
+	}
+}
+
 func TestGetTransactionsHandler(t *testing.T) {
 	ts, storage := prepareAPIServer()
 	defer storage.Close()
@@ -169,6 +242,87 @@ func TestGetTransactionsByAccountHandler(t *testing.T) {
 	}
 }
 
+func TestGetTransactionsByAccountHandlerWithMemo(t *testing.T) {
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	kp, target, _ := prepareTxs(storage, 5)
+
+	memo := transaction.NewMemo(transaction.MemoText, "showme")
+	var txs []transaction.Transaction
+	var txHashes []string
+	for i := 0; i < 3; i++ {
+		tx := transaction.TestMakeTransactionWithKeypair(networkID, 1, kp, target)
+		tx.B.Memo = &memo
+		tx.Sign(kp, networkID)
+		txs = append(txs, tx)
+		txHashes = append(txHashes, tx.GetHash())
+	}
+
+	theBlock := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(storage), txHashes)
+	theBlock.MustSave(storage)
+	for _, tx := range txs {
+		bt := block.NewBlockTransactionFromTransaction(theBlock.Hash, theBlock.Height, theBlock.ProposedTime, tx)
+		bt.MustSave(storage)
+		block.SaveTransactionPool(storage, tx)
+	}
+
+	for _, address := range []string{kp.Address(), target.Address()} {
+		url := strings.Replace(GetAccountTransactionsHandlerPattern, "{id}", address, -1) + "?memo_type=text&memo=showme"
+		respBody := request(ts, url, false)
+		defer respBody.Close()
+
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		recv := make(map[string]interface{})
+		common.MustUnmarshalJSON(readByte, &recv)
+		records := recv["_embedded"].(map[string]interface{})["records"].([]interface{})
+
+		require.Equal(t, len(txHashes), len(records), "length is not the same")
+		for i, r := range records {
+			bt := r.(map[string]interface{})
+			require.Equal(t, txHashes[i], bt["hash"].(string), "hash is not the same")
+
+			m := bt["memo"].(map[string]interface{})
+			require.Equal(t, "text", m["type"])
+			require.Equal(t, memo.Value, m["value"])
+		}
+	}
+
+	getRecv := func(query string) map[string]interface{} {
+		url := strings.Replace(GetAccountTransactionsHandlerPattern, "{id}", kp.Address(), -1) + query
+		respBody := request(ts, url, false)
+		defer respBody.Close()
+
+		readByte, err := ioutil.ReadAll(bufio.NewReader(respBody))
+		require.NoError(t, err)
+
+		recv := make(map[string]interface{})
+		common.MustUnmarshalJSON(readByte, &recv)
+		return recv
+	}
+
+	{ // value is not valid for the memo type
+		recv := getRecv("?memo_type=hash&memo=" + memo.Value)
+		require.Equal(t, http.StatusBadRequest, int(recv["status"].(float64)))
+
+		recv = getRecv("?memo_type=none&memo=" + memo.Value)
+		require.Equal(t, http.StatusBadRequest, int(recv["status"].(float64)))
+	}
+
+	{ // without memo type
+		recv := getRecv("?memo=" + memo.Value)
+		require.Equal(t, http.StatusBadRequest, int(recv["status"].(float64)))
+	}
+
+	{ // unknown memo type
+		recv := getRecv("?memo_type=unknown&memo=" + memo.Value)
+		require.Equal(t, http.StatusBadRequest, int(recv["status"].(float64)))
+	}
+}
+
 func TestGetTransactionsHandlerPage(t *testing.T) {
 	ts, storage := prepareAPIServer()
 	defer storage.Close()
@@ -262,3 +416,86 @@ func TestGetTransactionsHandlerPage(t *testing.T) {
 		}
 	}
 }
+
+func TestGetTransactionProofHandler(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	ts, storage := prepareAPIServer()
+	defer storage.Close()
+	defer ts.Close()
+
+	kp := keypair.Random()
+	var txs []transaction.Transaction
+	var hashes []string
+	for i := 0; i < 3; i++ {
+		tx := transaction.TestMakeTransactionWithKeypair(networkID, 1, kp)
+		txs = append(txs, tx)
+		hashes = append(hashes, tx.GetHash())
+	}
+
+	theBlock := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(storage), hashes)
+	theBlock.MustSave(storage)
+	for _, tx := range txs {
+		bt := block.NewBlockTransactionFromTransaction(theBlock.Hash, theBlock.Height, theBlock.ProposedTime, tx)
+		bt.MustSave(storage)
+	}
+
+	{ // unknown transaction
+		req, _ := http.NewRequest("GET", ts.URL+strings.Replace(GetTransactionProofHandlerPattern, "{id}", "findme", -1), nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	}
+
+	for _, hash := range hashes {
+		respBody := request(ts, strings.Replace(GetTransactionProofHandlerPattern, "{id}", hash, -1), false)
+		defer respBody.Close()
+
+		readByte, err := ioutil.ReadAll(respBody)
+		require.NoError(t, err)
+
+		var recv struct {
+			Hash   string             `json:"hash"`
+			Block  string             `json:"block"`
+			Header block.Header       `json:"header"`
+			Proof  common.MerkleProof `json:"proof"`
+		}
+		common.MustUnmarshalJSON(readByte, &recv)
+
+		require.Equal(t, hash, recv.Hash)
+		require.Equal(t, theBlock.Hash, recv.Block)
+		require.Equal(t, theBlock.Header, recv.Header)
+		require.Equal(t, len(hashes)+1, recv.Proof.Leaves) // with proposer transaction
+		require.True(t, recv.Proof.Verify(recv.Header.TransactionsRoot, hash))
+		require.False(t, recv.Proof.Verify(recv.Header.TransactionsRoot, hashes[0]+hashes[1]))
+	}
+
+	{ // the block of previous version does not support the proof
+		tx := transaction.TestMakeTransactionWithKeypair(networkID, 1, kp)
+		latest := block.GetLatestBlock(storage)
+		old := *block.NewBlockWithVersion(
+			block.BlockVersionV0,
+			kp.Address(),
+			voting.Basis{Height: latest.Height + 1, BlockHash: latest.Hash},
+			"",
+			[]string{tx.GetHash()},
+			common.NowISO8601(),
+			"",
+		)
+		old.MustSave(storage)
+		bt := block.NewBlockTransactionFromTransaction(old.Hash, old.Height, old.ProposedTime, tx)
+		bt.MustSave(storage)
+
+		req, _ := http.NewRequest("GET", ts.URL+strings.Replace(GetTransactionProofHandlerPattern, "{id}", tx.GetHash(), -1), nil)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		readByte, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(readByte), errors.TransactionProofNotAvailable.Message)
+	}
+}
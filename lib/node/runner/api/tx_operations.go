@@ -70,7 +70,10 @@ func (api NetworkHandlerAPI) getBlockByTx(hash string) (*block.Block, error) {
 	if found, err := block.ExistsBlockTransaction(api.storage, hash); err != nil {
 		return nil, err
 	} else if !found {
-		return nil, errors.BlockTransactionDoesNotExists.Clone().SetData("status", http.StatusNotFound)
+		return nil, api.checkPrunedTransaction(
+			hash,
+			errors.BlockTransactionDoesNotExists.Clone().SetData("status", http.StatusNotFound),
+		)
 	}
 
 	var bt block.BlockTransaction
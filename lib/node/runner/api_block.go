@@ -4,9 +4,13 @@ import (
 	"net/http"
 	"strconv"
 
+	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
 	api "boscoin.io/sebak/lib/node/runner/node_api"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
 )
 
 const GetBlocksPattern = "/blocks"
@@ -96,17 +100,35 @@ func (nh NetworkHandlerNode) GetBlocksHandler(w http.ResponseWriter, r *http.Req
 	// response.
 	w.Header().Set("X-SEBAK-RESULT-COUNT", strconv.FormatInt(int64(len(bs)), 10))
 
+	// with the headers, the transactions of `UpdateValidators` are given, so
+	// the light client can follow the validator set changes.
+	var changes map[uint64][]string
+	if options.Mode == GetBlocksOptionsModeHeader {
+		changes = nh.getValidatorSetChangeTransactions()
+	}
+
+	lowestFullHeight := block.GetLowestFullBlockHeight(nh.storage)
 	for _, b := range bs {
 		var itemType api.NodeItemDataType
 		if options.Mode == GetBlocksOptionsModeHeader {
 			itemType = api.NodeItemBlockHeader
 			nh.renderNodeItem(w, itemType, b.Header)
+
+			for _, hash := range changes[b.Height] {
+				if p, err := newValidatorSetChangeProof(nh.storage, *b, hash); err != nil {
+					nh.renderNodeItem(w, api.NodeItemError, err)
+				} else {
+					nh.renderNodeItem(w, api.NodeItemValidatorSetChange, p)
+				}
+			}
 		} else {
 			itemType = api.NodeItemBlock
 			nh.renderNodeItem(w, itemType, b)
 		}
 
-		if options.Mode == GetBlocksOptionsModeFull {
+		if options.Mode == GetBlocksOptionsModeFull && b.Height > common.GenesisBlockHeight && b.Height < lowestFullHeight {
+			nh.renderNodeItem(w, api.NodeItemError, errors.BlockTransactionPruned)
+		} else if options.Mode == GetBlocksOptionsModeFull {
 			var err error
 			var tx block.BlockTransaction
 			var tp block.TransactionPool
@@ -132,6 +154,59 @@ func (nh NetworkHandlerNode) GetBlocksHandler(w http.ResponseWriter, r *http.Req
 				}
 			}
 		}
+
+		// genesis block does not have the certificate
+		if exists, err := ballot.ExistsCertificate(nh.storage, b.Hash); err != nil {
+			nh.renderNodeItem(w, api.NodeItemError, err)
+		} else if exists {
+			if c, err := ballot.GetCertificate(nh.storage, b.Hash); err != nil {
+				nh.renderNodeItem(w, api.NodeItemError, err)
+			} else {
+				nh.renderNodeItem(w, api.NodeItemBlockCertificate, c)
+			}
+		}
+	}
+
+	return
+}
+
+// getValidatorSetChangeTransactions returns the hashes of the transactions,
+// which have `UpdateValidators` operations, by block height; only the
+// congress account can make them.
+func (nh NetworkHandlerNode) getValidatorSetChangeTransactions() map[uint64][]string {
+	txs := map[uint64][]string{}
+
+	iterFunc, closeFunc := block.GetBlockOperationsBySourceAndType(
+		nh.storage,
+		nh.conf.CongressAccountAddress,
+		operation.TypeUpdateValidators,
+		nil,
+	)
+	defer closeFunc()
+
+	for {
+		bo, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		if _, found := common.InStringArray(txs[bo.Height], bo.TxHash); !found {
+			txs[bo.Height] = append(txs[bo.Height], bo.TxHash)
+		}
+	}
+
+	return txs
+}
+
+func newValidatorSetChangeProof(st storage.Backend, blk block.Block, hash string) (p block.ValidatorSetChangeProof, err error) {
+	var tp block.TransactionPool
+	if tp, err = block.GetTransactionPool(st, hash); err != nil {
+		return
+	}
+
+	p.Block = blk.Hash
+	p.Transaction = tp.Transaction()
+	if blk.Version >= block.BlockVersionV1 {
+		p.Proof, err = blk.TransactionProof(hash)
 	}
 
 	return
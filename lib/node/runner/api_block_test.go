@@ -23,7 +23,7 @@ import (
 )
 
 type HelperTestGetBlocksHandler struct {
-	st     *storage.LevelDBBackend
+	st     storage.Backend
 	server *httptest.Server
 	blocks []block.Block
 }
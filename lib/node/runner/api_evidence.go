@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"net/http"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/client"
+	"boscoin.io/sebak/lib/errors"
+	api "boscoin.io/sebak/lib/node/runner/node_api"
+)
+
+const GetEvidencesPattern = "/evidences"
+
+// GetEvidencesHandler returns the stored `ballot.Evidence`s in the order of
+// block height; it supports `reverse` and `limit` query.
+func (nh NetworkHandlerNode) GetEvidencesHandler(w http.ResponseWriter, r *http.Request) {
+	options, err := client.NewDefaultListOptionsFromQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, errors.InvalidQueryString.Error(), http.StatusBadRequest)
+		return
+	}
+	options.SetCursor(nil)
+
+	iterFunc, closeFunc := ballot.GetEvidences(nh.storage, options)
+	defer closeFunc()
+
+	for {
+		e, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		nh.renderNodeItem(w, api.NodeItemEvidence, e)
+	}
+
+	return
+}
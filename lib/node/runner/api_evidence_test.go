@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network"
+	api "boscoin.io/sebak/lib/node/runner/node_api"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// In TestBallotCheckEquivocation test, the validator sends the SIGN ballots of
+// different votes at the same voting basis.
+func TestBallotCheckEquivocation(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, _ := createNodeRunnerForTesting(5, conf, nil)
+
+	tx, _ := GetTransaction()
+	nr.TransactionPool.Add(tx)
+
+	round := uint64(0)
+	_, err := nr.proposeNewBallot(round)
+	require.NoError(t, err)
+
+	b := nr.Consensus().LatestBlock()
+	basis := voting.Basis{
+		Round:     round,
+		Height:    b.Height,
+		BlockHash: b.Hash,
+		TotalTxs:  b.TotalTxs,
+	}
+	proposer := nr.localNode
+
+	ballotSIGN := GenerateBallot(proposer, basis, tx, ballot.StateSIGN, nodes[1], conf)
+	require.NoError(t, ReceiveBallot(nr, ballotSIGN))
+
+	// same ballot again is not conflicting
+	require.Equal(t, errors.BallotAlreadyVoted, ReceiveBallot(nr, ballotSIGN))
+
+	conflicting := GenerateBallot(proposer, basis, tx, ballot.StateSIGN, nodes[1], conf)
+	conflicting.SetVote(ballot.StateSIGN, voting.NO)
+	conflicting.Sign(nodes[1].Keypair(), networkID)
+	require.Equal(t, errors.BallotConflicted, ReceiveBallot(nr, conflicting))
+
+	// the conflicting ballot is not voted
+	rr := nr.Consensus().RunningRounds[basis.Index()]
+	voted := rr.Voted[proposer.Address()].GetResult(ballot.StateSIGN)[nodes[1].Address()]
+	require.Equal(t, ballotSIGN.GetHash(), voted.GetHash())
+
+	// expired ballot is not conflicting
+	expired := GenerateBallot(proposer, basis, tx, ballot.StateSIGN, nodes[1], conf)
+	expired.SetVote(ballot.StateSIGN, voting.EXP)
+	expired.Sign(nodes[1].Keypair(), networkID)
+	require.NotEqual(t, errors.BallotConflicted, ReceiveBallot(nr, expired))
+
+	iterFunc, closeFunc := ballot.GetEvidences(nr.Storage(), nil)
+	var evidences []ballot.Evidence
+	for {
+		e, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		evidences = append(evidences, e)
+	}
+	closeFunc()
+
+	require.Equal(t, 1, len(evidences))
+	require.Equal(t, nodes[1].Address(), evidences[0].Source)
+	require.Equal(t, ballot.StateSIGN, evidences[0].State)
+	require.Equal(t, basis.Index(), evidences[0].VotingBasis.Index())
+	require.Equal(t, ballot.NewEvidence(*ballotSIGN, *conflicting).Hash, evidences[0].Hash)
+
+	// same conflict is stored only once
+	require.Equal(t, errors.BallotConflicted, ReceiveBallot(nr, conflicting))
+
+	nodeHandler := NewNetworkHandlerNode(
+		nr.localNode,
+		nr.network,
+		nr.storage,
+		nr.consensus,
+		nr.TransactionPool,
+		network.UrlPathPrefixNode,
+		conf,
+	)
+
+	req := httptest.NewRequest("GET", GetEvidencesPattern, nil)
+	w := httptest.NewRecorder()
+	nodeHandler.GetEvidencesHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	rbs, err := unmarshalFromNodeItemResponseBody(w.Result().Body)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(rbs[api.NodeItemEvidence]))
+
+	evidence := rbs[api.NodeItemEvidence][0].(ballot.Evidence)
+	require.Equal(t, evidences[0].Hash, evidence.Hash)
+	require.Equal(t, 2, len(evidence.Ballots))
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strings"
 
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/consensus"
 	"boscoin.io/sebak/lib/network"
@@ -29,14 +30,18 @@ const (
 type NetworkHandlerNode struct {
 	localNode       *node.LocalNode
 	network         network.Network
-	storage         *storage.LevelDBBackend
+	storage         storage.Backend
 	consensus       *consensus.ISAAC
 	transactionPool *transaction.Pool
 	urlPrefix       string
 	conf            common.Config
+
+	// RejectedTransactions keeps the transactions rejected by
+	// `MessageValidate`.
+	RejectedTransactions *block.RejectedTransactions
 }
 
-func NewNetworkHandlerNode(localNode *node.LocalNode, network network.Network, storage *storage.LevelDBBackend, consensus *consensus.ISAAC, transactionPool *transaction.Pool, urlPrefix string, conf common.Config) *NetworkHandlerNode {
+func NewNetworkHandlerNode(localNode *node.LocalNode, network network.Network, storage storage.Backend, consensus *consensus.ISAAC, transactionPool *transaction.Pool, urlPrefix string, conf common.Config) *NetworkHandlerNode {
 	return &NetworkHandlerNode{
 		localNode:       localNode,
 		network:         network,
@@ -131,6 +136,8 @@ func (api NetworkHandlerNode) ReceiveTransaction(body []byte, funcs []common.Che
 		Message:         message,
 		Log:             log,
 		Conf:            api.conf,
+
+		RejectedTransactions: api.RejectedTransactions,
 	}
 
 	err := common.RunChecker(checker, common.DefaultDeferFunc)
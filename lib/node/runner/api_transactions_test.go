@@ -27,7 +27,7 @@ import (
 
 type HelperTestGetNodeTransactionsHandler struct {
 	localNode         *node.LocalNode
-	st                *storage.LevelDBBackend
+	st                storage.Backend
 	server            *httptest.Server
 	blocks            []block.Block
 	transactionHashes []string
@@ -14,14 +14,14 @@ import (
 )
 
 type SavingBlockOperations struct {
-	st  *storage.LevelDBBackend
+	st  storage.Backend
 	log logging.Logger
 
 	saveBlock          chan block.Block
 	checkedBlockHeight uint64 // block.Block.Height
 }
 
-func NewSavingBlockOperations(st *storage.LevelDBBackend, logger logging.Logger) *SavingBlockOperations {
+func NewSavingBlockOperations(st storage.Backend, logger logging.Logger) *SavingBlockOperations {
 	if logger == nil {
 		logger = log
 	}
@@ -43,15 +43,26 @@ func (sb *SavingBlockOperations) getCheckedBlockKey() string {
 }
 
 func (sb *SavingBlockOperations) getCheckedBlockHeight() uint64 {
-	var checked uint64
-	if err := sb.st.Get(sb.getCheckedBlockKey(), &checked); err != nil {
+	checked, err := sb.loadCheckedBlockHeight()
+	if err != nil {
 		sb.log.Error("failed to check CheckedBlock", "error", err)
+
+		// the storage, which is imported from snapshot, does not have the
+		// transactions until the pruned block.
+		if pruned, _ := block.GetPrunedBlockHeight(sb.st); pruned > common.GenesisBlockHeight {
+			return pruned
+		}
 		return common.GenesisBlockHeight
 	}
 
 	return checked
 }
 
+func (sb *SavingBlockOperations) loadCheckedBlockHeight() (checked uint64, err error) {
+	err = sb.st.Get(sb.getCheckedBlockKey(), &checked)
+	return
+}
+
 func (sb *SavingBlockOperations) saveCheckedBlock(height uint64) {
 	sb.log.Debug("save CheckedBlock", "height", height)
 
@@ -114,7 +125,7 @@ func (sb *SavingBlockOperations) continuousCheck() {
 
 func (sb *SavingBlockOperations) checkBlockWorker(id int, blocks <-chan block.Block, errChan chan<- error) {
 	var err error
-	var st *storage.LevelDBBackend
+	var st storage.Backend
 
 	for blk := range blocks {
 		if st, err = sb.st.OpenBatch(); err != nil {
@@ -222,13 +233,13 @@ errorCheck:
 	return
 }
 
-func (sb *SavingBlockOperations) savingBlockOperationsWorker(id int, st *storage.LevelDBBackend, blk block.Block, txs <-chan string, errChan chan<- error) {
+func (sb *SavingBlockOperations) savingBlockOperationsWorker(id int, st storage.Backend, blk block.Block, txs <-chan string, errChan chan<- error) {
 	for hash := range txs {
 		errChan <- sb.CheckTransactionByBlock(st, blk, hash)
 	}
 }
 
-func (sb *SavingBlockOperations) CheckByBlock(st *storage.LevelDBBackend, blk block.Block) (err error) {
+func (sb *SavingBlockOperations) CheckByBlock(st storage.Backend, blk block.Block) (err error) {
 	if blk.Height > common.GenesisBlockHeight { // ProposerTransaction
 		if err = sb.CheckTransactionByBlock(st, blk, blk.ProposerTransaction); err != nil {
 			return
@@ -284,7 +295,7 @@ errorCheck:
 	return
 }
 
-func (sb *SavingBlockOperations) CheckTransactionByBlock(st *storage.LevelDBBackend, blk block.Block, hash string) (err error) {
+func (sb *SavingBlockOperations) CheckTransactionByBlock(st storage.Backend, blk block.Block, hash string) (err error) {
 	var bt block.BlockTransaction
 	if bt, err = block.GetBlockTransaction(st, hash); err != nil {
 		sb.log.Error("failed to get BlockTransaction", "block", blk.Hash, "transaction", hash, "error", err)
@@ -362,7 +373,7 @@ func (sb *SavingBlockOperations) save(blk block.Block) (err error) {
 		}
 	}()
 
-	var st *storage.LevelDBBackend
+	var st storage.Backend
 	if st, err = sb.st.OpenBatch(); err != nil {
 		return
 	}
@@ -15,7 +15,7 @@ import (
 )
 
 type TestSavingBlockOperationHelper struct {
-	st *storage.LevelDBBackend
+	st storage.Backend
 }
 
 func (p *TestSavingBlockOperationHelper) Prepare() {
@@ -179,3 +179,16 @@ func TestSavingBlockOperationWithEmptyTransactions(t *testing.T) {
 		require.True(t, exists)
 	}
 }
+
+func TestSavingBlockOperationFromPrunedBlock(t *testing.T) {
+	p := &TestSavingBlockOperationHelper{}
+	p.Prepare()
+	defer p.Done()
+
+	// the storage, which is imported from snapshot, has the pruned block
+	// height without CheckedBlock
+	require.NoError(t, block.SavePrunedBlockHeight(p.st, 10))
+
+	sb := NewSavingBlockOperations(p.st, nil)
+	require.Equal(t, uint64(10), sb.checkedBlockHeight)
+}
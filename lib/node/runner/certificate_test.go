@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/network"
+	api "boscoin.io/sebak/lib/node/runner/node_api"
+	"boscoin.io/sebak/lib/voting"
+)
+
+// TestBlockCertificate checks the ACCEPT ballots, which confirmed the block,
+// are stored as the certificate of the block and served through the `/blocks`
+// API.
+func TestBlockCertificate(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, _ := createNodeRunnerForTesting(5, conf, nil)
+
+	tx, _ := GetTransaction()
+	nr.TransactionPool.Add(tx)
+
+	round := uint64(0)
+	_, err := nr.proposeNewBallot(round)
+	require.NoError(t, err)
+
+	b := nr.Consensus().LatestBlock()
+	basis := voting.Basis{
+		Round:     round,
+		Height:    b.Height,
+		BlockHash: b.Hash,
+		TotalTxs:  b.TotalTxs,
+	}
+	proposer := nr.localNode
+
+	for _, n := range nodes[1:] {
+		require.NoError(t, ReceiveBallot(nr, GenerateBallot(proposer, basis, tx, ballot.StateSIGN, n, conf)))
+	}
+	for _, n := range nodes[:4] {
+		require.NoError(t, ReceiveBallot(nr, GenerateBallot(proposer, basis, tx, ballot.StateACCEPT, n, conf)))
+	}
+
+	blk := nr.Consensus().LatestBlock()
+	require.Equal(t, b.Height+1, blk.Height)
+
+	c, err := ballot.GetCertificate(nr.Storage(), blk.Hash)
+	require.NoError(t, err)
+	require.Equal(t, blk.Hash, c.Block)
+	require.Equal(t, 4, len(c.Ballots))
+
+	var validators []string
+	for _, n := range nodes {
+		validators = append(validators, n.Address())
+	}
+	require.NoError(t, c.Verify(blk, validators, nr.policy.Threshold(), conf.NetworkID))
+
+	// genesis block does not have the certificate
+	exists, err := ballot.ExistsCertificate(nr.Storage(), b.Hash)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	nodeHandler := NewNetworkHandlerNode(
+		nr.localNode,
+		nr.network,
+		nr.storage,
+		nr.consensus,
+		nr.TransactionPool,
+		network.UrlPathPrefixNode,
+		conf,
+	)
+
+	url := fmt.Sprintf("%s?mode=%s&height-range=%d-%d", GetBlocksPattern, GetBlocksOptionsModeFull, b.Height, blk.Height+1)
+	req := httptest.NewRequest("GET", url, nil)
+	w := httptest.NewRecorder()
+	nodeHandler.GetBlocksHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	rbs, err := unmarshalFromNodeItemResponseBody(w.Result().Body)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(rbs[api.NodeItemBlock]))
+	require.Equal(t, 1, len(rbs[api.NodeItemBlockCertificate]))
+
+	served := rbs[api.NodeItemBlockCertificate][0].(ballot.Certificate)
+	require.Equal(t, blk.Hash, served.Block)
+	require.NoError(t, served.Verify(blk, validators, nr.policy.Threshold(), conf.NetworkID))
+}
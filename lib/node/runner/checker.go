@@ -11,6 +11,7 @@ import (
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/consensus"
 	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/metrics"
 	"boscoin.io/sebak/lib/node"
 	"boscoin.io/sebak/lib/node/runner/api"
 	node_api "boscoin.io/sebak/lib/node/runner/node_api"
@@ -293,6 +294,10 @@ func BallotCheckSYNC(c common.Checker, args ...interface{}) error {
 			log.Debug("failed to finish current ballot; latestHeight == syncHeight-1", "current-ballot", b, "error", err)
 			return err
 		}
+		if err = saveCertificate(checker.NodeRunner.Storage(), *blk, result); err != nil {
+			log.Error("failed to store certificate", "block", blk.Hash, "error", err)
+			return err
+		}
 		checker.NodeRunner.SavingBlockOperations().Save(*blk)
 
 		checker.NodeRunner.NextHeight()
@@ -308,7 +313,12 @@ func isBallotAcceptYesOrExp(b ballot.Ballot) bool {
 }
 
 func hasBallotValidProposer(is *consensus.ISAAC, b ballot.Ballot) bool {
-	return b.Proposer() == is.SelectProposer(b.VotingBasis().Height, b.VotingBasis().Round)
+	proposer, err := is.SelectProposer(b.VotingBasis().Height, b.VotingBasis().Round)
+	if err != nil {
+		return false
+	}
+
+	return b.Proposer() == proposer
 }
 
 // BallotCheckBasis checks the incoming ballot in
@@ -341,6 +351,31 @@ func BallotAlreadyVoted(c common.Checker, args ...interface{}) (err error) {
 	return
 }
 
+// BallotCheckEquivocation checks the incoming ballot conflicts with the ballot,
+// which is already voted by the same validator. The conflicting ballots are
+// stored as `ballot.Evidence` and the incoming ballot is ignored.
+func BallotCheckEquivocation(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotChecker)
+
+	voted, found := checker.NodeRunner.Consensus().ConflictingBallot(checker.Ballot)
+	if !found {
+		return
+	}
+
+	evidence := ballot.NewEvidence(voted, checker.Ballot)
+
+	var saved bool
+	if saved, err = evidence.Save(checker.NodeRunner.Storage()); err != nil {
+		return
+	}
+	if saved {
+		metrics.Consensus.AddEquivocation()
+		checker.Log.Error("conflicting ballot found", "evidence", evidence.Hash, "voted", voted.GetHash())
+	}
+
+	return errors.BallotConflicted
+}
+
 // BallotVote vote by incoming ballot; if the ballot is new
 // and the round of ballot is not yet registered, this will make new
 // `RunningRound`.
@@ -543,7 +578,7 @@ func insertMissingTransaction(nr *NodeRunner, ballot ballot.Ballot) (err error)
 		receivedTransaction = append(receivedTransaction, tx)
 	}
 
-	var bs *storage.LevelDBBackend
+	var bs storage.Backend
 	bs, err = nr.Storage().OpenBatch()
 	for _, tx := range receivedTransaction {
 		if _, err = block.SaveTransactionPool(bs, tx); err != nil {
@@ -583,6 +618,7 @@ var INITBallotTransactionCheckerFuncs = []common.CheckerFunc{
 	CheckMissingTransaction,
 	BallotTransactionsOperationLimit,
 	BallotTransactionsSameSource,
+	BallotTransactionsMergedAccount,
 	BallotTransactionsOperationBodyCollectTxFee,
 	BallotTransactionsAllValid,
 }
@@ -617,6 +653,7 @@ func INITBallotValidateTransactions(c common.Checker, args ...interface{}) (err
 		Conf:           checker.Conf,
 		LocalNode:      checker.LocalNode,
 		Ballot:         checker.Ballot,
+		Height:         checker.Ballot.VotingBasis().Height + 1,
 		Transactions:   checker.Ballot.Transactions(),
 		VotingHole:     voting.NOTYET,
 		transactionCache: NewTransactionCache(
@@ -739,6 +776,7 @@ func FinishedBallotStore(c common.Checker, args ...interface{}) error {
 		checker.NodeRunner.Consensus().SetLatestVotingBasis(basis)
 
 		checker.NodeRunner.TransactionPool.RemoveFromSources(checker.LatestBlockSources...)
+		checker.NodeRunner.TransactionPool.RemoveExpired(basis.Height + 2) // height of the next block
 		checker.NodeRunner.Consensus().RemoveRunningRoundsLowerOrEqualHeight(basis.Height)
 		checker.NodeRunner.RemoveSendRecordsLowerThanOrEqualHeight(basis.Height)
 
@@ -769,6 +807,10 @@ func saveBlock(checker *BallotChecker) error {
 	}
 
 	checker.Log.Debug("ballot was stored", "block", blk.Hash)
+	if err = saveCertificate(checker.NodeRunner.Storage(), *blk, checker.Result); err != nil {
+		checker.Log.Error("failed to store certificate", "block", blk.Hash, "error", err)
+		return err
+	}
 	if checker.LocalNode.State() != node.StateCONSENSUS {
 		checker.NodeRunner.Log().Debug("node state transits sync to consensus", "height", checker.Ballot.VotingBasis().Height)
 		checker.LocalNode.SetConsensus()
@@ -784,7 +826,20 @@ func saveBlock(checker *BallotChecker) error {
 	return nil
 }
 
-func isValidRound(st *storage.LevelDBBackend, r voting.Basis, log logging.Logger) (bool, error) {
+// saveCertificate stores the ACCEPT YES ballots of the voting result as the
+// `ballot.Certificate` of the block.
+func saveCertificate(st storage.Backend, blk block.Block, result consensus.RoundVoteResult) error {
+	var ballots []ballot.Ballot
+	for _, b := range result {
+		if b.State() == ballot.StateACCEPT && b.Vote() == voting.YES {
+			ballots = append(ballots, b)
+		}
+	}
+
+	return ballot.NewCertificate(blk, ballots).Save(st)
+}
+
+func isValidRound(st storage.Backend, r voting.Basis, log logging.Logger) (bool, error) {
 	latestBlock := block.GetLatestBlock(st)
 	if latestBlock.Height != r.Height {
 		log.Error(
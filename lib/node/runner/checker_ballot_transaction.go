@@ -26,6 +26,7 @@ type BallotTransactionChecker struct {
 	NetworkID  []byte
 
 	Ballot                ballot.Ballot
+	Height                uint64 // height of the block, which includes the transactions
 	Transactions          []string
 	VotingHole            voting.Hole
 	ValidTransactions     []string
@@ -50,6 +51,20 @@ func (checker *BallotTransactionChecker) allTransactionsValid() bool {
 	return len(checker.Transactions) == len(checker.validTransactionsMap)
 }
 
+func (checker *BallotTransactionChecker) allTransactionsInTimeBounds() bool {
+	for _, hash := range checker.ValidTransactions {
+		tx, found, err := checker.transactionCache.Get(hash)
+		if err != nil || !found {
+			return false
+		}
+		if !tx.IsValidBlockHeight(checker.Height) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (checker *BallotTransactionChecker) setValidTransactions(hashes []string) {
 	checker.ValidTransactions = hashes
 
@@ -68,9 +83,9 @@ func IsNew(c common.Checker, args ...interface{}) (err error) {
 
 	var validTransactions []string
 	for _, hash := range checker.Transactions {
-		// check transaction is already stored
+		// check transaction is already stored or pruned
 		var found bool
-		if found, err = block.ExistsBlockTransaction(checker.NodeRunner.Storage(), hash); err != nil || found {
+		if found, err = block.IsConfirmedBlockTransaction(checker.NodeRunner.Storage(), hash); err != nil || found {
 			if !checker.CheckTransactionsOnly {
 				err = errors.NewButKnownMessage
 				return
@@ -163,6 +178,57 @@ func BallotTransactionsSameSource(c common.Checker, args ...interface{}) (err er
 	return
 }
 
+// BallotTransactionsMergedAccount checks there are transactions, which send
+// to the account merged by the other transaction in the `Transactions`.
+func BallotTransactionsMergedAccount(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotTransactionChecker)
+
+	var txs []transaction.Transaction
+	merged := map[string]bool{}
+
+	var tx transaction.Transaction
+	var found bool
+	for _, hash := range checker.ValidTransactions {
+		if tx, found, err = checker.transactionCache.Get(hash); err != nil {
+			return
+		} else if !found {
+			continue
+		}
+
+		txs = append(txs, tx)
+		for _, op := range tx.B.Operations {
+			if op.H.Type == operation.TypeAccountMerge {
+				merged[tx.B.Source] = true
+			}
+		}
+	}
+
+	var validTransactions []string
+	for _, tx := range txs {
+		var toMerged bool
+		for _, op := range tx.B.Operations {
+			if pop, ok := op.B.(operation.Targetable); ok && common.InStringMap(merged, pop.TargetAddress()) {
+				toMerged = true
+				break
+			}
+		}
+
+		if toMerged {
+			if !checker.CheckTransactionsOnly {
+				err = errors.BlockAccountMergedInBallot
+				return
+			}
+			continue
+		}
+
+		validTransactions = append(validTransactions, tx.GetHash())
+	}
+	err = nil
+	checker.setValidTransactions(validTransactions)
+
+	return
+}
+
 // BallotTransactionsOperationBodyCollectTxFee validates the
 // `BallotTransactionsOperationBodyCollectTxFee.Amount` is matched with the
 // collected fee of all transactions.
@@ -203,11 +269,43 @@ func BallotTransactionsOperationBodyCollectTxFee(c common.Checker, args ...inter
 	return
 }
 
+// BallotTransactionsTimeBounds checks the transactions can be included in the
+// next block by their time bounds.
+func BallotTransactionsTimeBounds(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*BallotTransactionChecker)
+
+	var validTransactions []string
+	var tx transaction.Transaction
+	var found bool
+	for _, hash := range checker.ValidTransactions {
+		if tx, found, err = checker.transactionCache.Get(hash); err != nil {
+			return
+		} else if !found {
+			continue
+		}
+
+		if !tx.IsValidBlockHeight(checker.Height) {
+			if !checker.CheckTransactionsOnly {
+				err = errors.TransactionOutOfTimeBounds
+				return
+			}
+			continue
+		}
+
+		validTransactions = append(validTransactions, hash)
+	}
+	err = nil
+	checker.setValidTransactions(validTransactions)
+
+	return
+}
+
 // BallotTransactionsAllValid checks all the transactions are valid or not.
+// The expired transactions by time bounds are also treated as invalid.
 func BallotTransactionsAllValid(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*BallotTransactionChecker)
 
-	if checker.allTransactionsValid() {
+	if checker.allTransactionsValid() && checker.allTransactionsInTimeBounds() {
 		checker.VotingHole = voting.YES
 	} else {
 		checker.VotingHole = voting.NO
@@ -235,11 +333,22 @@ func BallotTransactionsAllValid(c common.Checker, args ...interface{}) (err erro
 //   config = consist of configuration of the network. common address, congress address, etc.
 //   tx = Transaction to check
 //
-func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction.Transaction) (err error) {
+func ValidateTx(st storage.Backend, config common.Config, tx transaction.Transaction) (err error) {
+	_, err = ValidateTxWithResults(st, config, tx)
+	return
+}
+
+// ValidateTxWithResults validates the transaction like `ValidateTx`, but the
+// operations are validated independently each other and the result of each
+// operation is returned. If the transaction itself is invalid, for example,
+// the wrong sequenceID, the results are empty. The returned error is the error
+// of the first invalid operation.
+func ValidateTxWithResults(st storage.Backend, config common.Config, tx transaction.Transaction) (results []transaction.OperationResult, err error) {
 	// check, source exists
 	var ba *block.BlockAccount
 	if ba, err = block.GetBlockAccount(st, tx.B.Source); err != nil {
-		return errors.BlockAccountDoesNotExists
+		err = errors.BlockAccountDoesNotExists
+		return
 	}
 
 	// check, version is correct
@@ -248,6 +357,19 @@ func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction
 		return
 	}
 
+	// check, the next block is in the time bounds
+	if tx.HasTimeBounds() {
+		if !tx.IsValidBlockHeight(block.GetLatestBlock(st).Height + 1) {
+			err = errors.TransactionOutOfTimeBounds
+			return
+		}
+	}
+
+	// check, the weight of signatures reaches the threshold of operations
+	if err = ValidateTxSignatures(ba, tx); err != nil {
+		return
+	}
+
 	// check, sequenceID is based on latest sequenceID
 	if !tx.IsValidSequenceID(ba.SequenceID) {
 		err = errors.TransactionInvalidSequenceID
@@ -262,10 +384,129 @@ func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction
 		return
 	}
 
+	// check, the fee covers the base fee by the recent blocks
+	if err = ValidateTxFee(st, config, tx); err != nil {
+		return
+	}
+
+	// check, the balance covers the reserve of data entries
+	if err = ValidateTxDataEntries(st, ba, tx); err != nil {
+		return
+	}
+
+	for i, op := range tx.B.Operations {
+		opErr := ValidateOp(st, config, ba, op)
+		if opErr != nil && err == nil {
+			err = opErr
+		}
+		results = append(results, transaction.NewOperationResult(i, op, opErr))
+	}
+
+	return
+}
+
+// ValidateTxFee checks the fee of transaction covers the base fee, which
+// depends on the fullness of the recent blocks; see `block.GetBaseFee`.
+func ValidateTxFee(st storage.Backend, config common.Config, tx transaction.Transaction) (err error) {
+	var baseFee common.Amount
+	if baseFee, err = block.GetBaseFee(st); err != nil {
+		return
+	}
+
+	if tx.B.Fee < tx.MinimumFee(baseFee) {
+		err = errors.InvalidFee
+		return
+	}
+
+	return
+}
+
+// ValidateTxSignatures checks the sum of the weights of transaction signers
+// reaches the threshold of the operations. The signatures themselves are
+// already verified by `transaction.CheckVerifySignature`.
+func ValidateTxSignatures(ba *block.BlockAccount, tx transaction.Transaction) (err error) {
+	// without signers, only the signature of source is allowed and it is
+	// already verified.
+	if !ba.HasSigners() {
+		if len(tx.H.Signatures) > 0 {
+			return errors.TransactionUnknownSigner
+		}
+		return
+	}
+
+	var weight uint64
+	if len(tx.H.Signature) > 0 {
+		weight += uint64(ba.SignerWeight(ba.Address))
+	}
+
+	for _, s := range tx.H.Signatures {
+		w := ba.SignerWeight(s.Signer)
+		if w < 1 {
+			return errors.TransactionUnknownSigner
+		}
+		weight += uint64(w)
+	}
+
+	if weight < uint64(ba.RequiredThreshold(tx.B.Operations...)) {
+		return errors.TransactionInsufficientSignatureWeight
+	}
+
+	return
+}
+
+// ValidateTxDataEntries checks the `ManageData` operations of transaction;
+// the entry to be deleted should exist and the balance after transaction
+// should cover the reserve of the data entries of account.
+func ValidateTxDataEntries(st storage.Backend, ba *block.BlockAccount, tx transaction.Transaction) (err error) {
+	entries := ba.DataEntries
+	existing := map[string]bool{} // whether the entry exists after operation
+	var merged bool
 	for _, op := range tx.B.Operations {
-		if err = ValidateOp(st, config, ba, op); err != nil {
-			return
+		if op.H.Type == operation.TypeAccountMerge {
+			merged = true
 		}
+		if op.H.Type != operation.TypeManageData {
+			continue
+		}
+
+		var ok bool
+		var casted operation.ManageData
+		if casted, ok = op.B.(operation.ManageData); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+
+		exists, found := existing[casted.Key]
+		if !found {
+			if exists, err = block.ExistsBlockAccountData(st, ba.Address, casted.Key); err != nil {
+				return
+			}
+		}
+
+		if casted.IsDelete() {
+			if !exists {
+				return errors.BlockAccountDataDoesNotExists
+			}
+			entries--
+		} else if !exists {
+			entries++
+		}
+		existing[casted.Key] = !casted.IsDelete()
+	}
+
+	// the merged account does not need reserve
+	if entries < 1 || merged {
+		return
+	}
+
+	var reserve, remain common.Amount
+	if reserve, err = common.BaseReserve.MultUint64(entries); err != nil {
+		return
+	}
+	if remain, err = ba.Balance.Sub(tx.TotalAmount(true)); err != nil {
+		return errors.TransactionExcessAbilityToPay
+	}
+	if remain < reserve {
+		return errors.BlockAccountInsufficientReserve
 	}
 
 	return
@@ -284,7 +525,7 @@ func ValidateTx(st *storage.LevelDBBackend, config common.Config, tx transaction
 //   source = Account from where the transaction (and ops) come from
 //   tx = Transaction to check
 //
-func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.BlockAccount, op operation.Operation) (err error) {
+func ValidateOp(st storage.Backend, config common.Config, source *block.BlockAccount, op operation.Operation) (err error) {
 
 	var funcIsFrozenPayable = func(source *block.BlockAccount) (err error) {
 		// Unfreezing must be done after X period from unfreezing request
@@ -303,6 +544,24 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 		return nil
 	}
 
+	var funcHasFrozenAccounts = func(source *block.BlockAccount) (err error) {
+		// The frozen accounts, which are already merged, are not counted
+		iterFunc, closeFunc := block.GetBlockOperationsByLinked(st, source.Address, nil)
+		defer closeFunc()
+		for {
+			bo, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			if exists, err := block.ExistsBlockAccount(st, bo.Target); err != nil {
+				return err
+			} else if exists {
+				return errors.BlockAccountHasFrozenAccounts
+			}
+		}
+		return nil
+	}
+
 	switch op.H.Type {
 	case operation.TypeCreateAccount:
 		var ok bool
@@ -488,6 +747,63 @@ func ValidateOp(st *storage.LevelDBBackend, config common.Config, source *block.
 			return err
 		}
 
+	case operation.TypeManageSigners:
+		var ok bool
+		var casted operation.ManageSigners
+		if casted, ok = op.B.(operation.ManageSigners); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+		if err = casted.IsWellFormed(config); err != nil {
+			return
+		}
+	case operation.TypeAccountMerge:
+		var ok bool
+		var casted operation.AccountMerge
+		if casted, ok = op.B.(operation.AccountMerge); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+		var taccount *block.BlockAccount
+		if taccount, err = block.GetBlockAccount(st, casted.Target); err != nil {
+			return errors.BlockAccountDoesNotExists
+		}
+		if taccount.IsFrozen() {
+			return errors.FrozenAccountNoDeposit
+		}
+		if source.IsFrozen() {
+			if err = funcIsFrozenPayable(source); err != nil {
+				return err
+			}
+		}
+		if err = funcHasFrozenAccounts(source); err != nil {
+			return err
+		}
+	case operation.TypeManageData:
+		var ok bool
+		var casted operation.ManageData
+		if casted, ok = op.B.(operation.ManageData); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+		if err = casted.IsWellFormed(config); err != nil {
+			return
+		}
+	case operation.TypeUpdateValidators:
+		//the CongressAddress is owned by blockchainOS. It is temporally check.
+		//TODO: When a node of BosNet is operated by anonymous then it will be removed.
+		if source.Address != config.CongressAccountAddress {
+			return errors.CongressAddressMisMatched
+		}
+		var ok bool
+		var casted operation.UpdateValidators
+		if casted, ok = op.B.(operation.UpdateValidators); !ok {
+			return errors.TypeOperationBodyNotMatched
+		}
+		if err = casted.IsWellFormed(config); err != nil {
+			return
+		}
+		// the validators can be updated after the block of this operation
+		if casted.Height <= block.GetLatestBlock(st).Height+1 {
+			return errors.InvalidValidatorSetChange.Clone().SetData("error", "height is too low")
+		}
 	default:
 		return errors.UnknownOperationType
 	}
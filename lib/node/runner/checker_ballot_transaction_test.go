@@ -12,7 +12,9 @@
 package runner
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
@@ -315,3 +317,573 @@ func TestOpsInBalotLimit(t *testing.T) {
 		require.Equal(t, errors.BallotHasOverMaxOperationsInBallot, err)
 	}
 }
+
+// Check the signatures of multi-signature account
+func TestValidateTxMultiSignature(t *testing.T) {
+	kps := keypair.Random()
+	kpt := keypair.Random()
+	signers := []*keypair.Full{keypair.Random(), keypair.Random(), keypair.Random()}
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bat := block.BlockAccount{
+		Address: kpt.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bas.MustSave(st)
+	bat.MustSave(st)
+
+	// 2 of 3
+	opb := operation.NewManageSigners(
+		[]operation.Signer{
+			operation.Signer{Address: signers[0].Address(), Weight: 1},
+			operation.Signer{Address: signers[1].Address(), Weight: 1},
+			operation.Signer{Address: signers[2].Address(), Weight: 1},
+		},
+		operation.Thresholds{Low: 1, Medium: 2, High: 3},
+	)
+	op, _ := operation.NewOperation(opb)
+	require.NoError(t, ValidateOp(st, common.Config{}, &bas, op))
+	require.NoError(t, finishOperation(st, kps.Address(), op, log))
+
+	ba, err := block.GetBlockAccount(st, kps.Address())
+	require.NoError(t, err)
+	require.Equal(t, opb.Signers, ba.Signers)
+	require.Equal(t, opb.Thresholds, ba.Thresholds)
+
+	tx, _ := transaction.NewTransaction(
+		kps.Address(),
+		0,
+		operation.Operation{
+			H: operation.Header{Type: operation.TypePayment},
+			B: operation.Payment{Target: kpt.Address(), Amount: common.Amount(10000)},
+		},
+	)
+
+	{ // signed by the source only; source is not signer any more
+		tx.Sign(kps, networkID)
+		require.Equal(t, errors.TransactionInsufficientSignatureWeight, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // unknown signer
+		tx.H.Signature = ""
+		tx.AddSignature(signers[0], networkID)
+		tx.AddSignature(kpt, networkID)
+		require.Equal(t, errors.TransactionUnknownSigner, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // 1 of 3
+		tx.H.Signatures = nil
+		tx.AddSignature(signers[0], networkID)
+		require.Equal(t, errors.TransactionInsufficientSignatureWeight, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // 2 of 3
+		tx.AddSignature(signers[2], networkID)
+		require.NoError(t, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // `ManageSigners` requires the `High` threshold
+		txSigners, _ := transaction.NewTransaction(kps.Address(), 0, op)
+		txSigners.AddSignature(signers[0], networkID)
+		txSigners.AddSignature(signers[1], networkID)
+		require.Equal(t, errors.TransactionInsufficientSignatureWeight, ValidateTx(st, common.Config{}, txSigners))
+
+		txSigners.AddSignature(signers[2], networkID)
+		require.NoError(t, ValidateTx(st, common.Config{}, txSigners))
+	}
+}
+
+// Normal account does not accept the signatures of others
+func TestValidateTxSignaturesWithoutSigners(t *testing.T) {
+	kps := keypair.Random()
+	kpt := keypair.Random()
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bas.MustSave(st)
+
+	tx, _ := transaction.NewTransaction(kps.Address(), 0, operation.MakeTestPayment(10000))
+	tx.Sign(kps, networkID)
+	require.NoError(t, ValidateTxSignatures(&bas, tx))
+
+	tx.AddSignature(kpt, networkID)
+	require.Equal(t, errors.TransactionUnknownSigner, ValidateTxSignatures(&bas, tx))
+}
+
+// The sum of signer weights does not wrap around
+func TestValidateTxSignaturesWeightOverflow(t *testing.T) {
+	kps := keypair.Random()
+	signers := []*keypair.Full{keypair.Random(), keypair.Random()}
+
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+		Signers: []operation.Signer{
+			operation.Signer{Address: signers[0].Address(), Weight: math.MaxUint32},
+			operation.Signer{Address: signers[1].Address(), Weight: 2},
+		},
+		Thresholds: operation.Thresholds{Low: 3, Medium: 3, High: math.MaxUint32},
+	}
+
+	tx, _ := transaction.NewTransaction(kps.Address(), 0, operation.MakeTestPayment(10000))
+	tx.AddSignature(signers[0], networkID)
+	tx.AddSignature(signers[1], networkID)
+	require.NoError(t, ValidateTxSignatures(&bas, tx))
+}
+
+// Check the time bounds of transaction with the height of next block
+func TestValidateTxTimeBounds(t *testing.T) {
+	kpt := keypair.Random()
+
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	bat := block.BlockAccount{
+		Address: kpt.Address(),
+		Balance: common.Amount(1 * common.AmountPerCoin),
+	}
+	bat.MustSave(st)
+
+	latest := block.GetLatestBlock(st)
+
+	tx, _ := transaction.NewTransaction(
+		block.GenesisKP.Address(),
+		0,
+		operation.Operation{
+			H: operation.Header{Type: operation.TypePayment},
+			B: operation.Payment{Target: kpt.Address(), Amount: common.Amount(10000)},
+		},
+	)
+	require.NoError(t, ValidateTx(st, common.Config{}, tx))
+
+	tx.B.MinHeight = latest.Height + 2
+	require.Equal(t, errors.TransactionOutOfTimeBounds, ValidateTx(st, common.Config{}, tx))
+
+	tx.B.MinHeight = latest.Height + 1
+	require.NoError(t, ValidateTx(st, common.Config{}, tx))
+
+	tx.B.MinHeight = 0
+	tx.B.MaxHeight = latest.Height
+	require.Equal(t, errors.TransactionOutOfTimeBounds, ValidateTx(st, common.Config{}, tx))
+
+	tx.B.MaxHeight = latest.Height + 1
+	require.NoError(t, ValidateTx(st, common.Config{}, tx))
+}
+
+func TestBallotTransactionsTimeBounds(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr := createTestNodeRunner(1, conf)[0]
+
+	var txs []string
+	kp, expired := transaction.TestMakeTransaction(networkID, 1)
+	expired.B.MaxHeight = 2
+	expired.Sign(kp, networkID)
+	nr.TransactionPool.Add(expired)
+	txs = append(txs, expired.GetHash())
+
+	kp, valid := transaction.TestMakeTransaction(networkID, 1)
+	valid.B.MaxHeight = 3
+	valid.Sign(kp, networkID)
+	nr.TransactionPool.Add(valid)
+	txs = append(txs, valid.GetHash())
+
+	{ // proposer excludes the expired transactions
+		checker := &BallotTransactionChecker{
+			DefaultChecker:        common.DefaultChecker{Funcs: NewBallotTransactionCheckerFuncs},
+			NodeRunner:            nr,
+			Conf:                  nr.Conf,
+			LocalNode:             nr.Node(),
+			Height:                3,
+			Transactions:          txs,
+			CheckTransactionsOnly: true,
+			VotingHole:            voting.NOTYET,
+			transactionCache:      NewTransactionCache(nr.Storage(), nr.TransactionPool),
+		}
+
+		err := common.RunChecker(checker, common.DefaultDeferFunc)
+		require.NoError(t, err)
+		require.Equal(t, []string{valid.GetHash()}, checker.ValidTransactions)
+		require.Equal(t, []string{expired.GetHash()}, checker.invalidTransactions())
+	}
+
+	{ // ballot, which has the expired transactions, is voted NO
+		checker := &BallotTransactionChecker{
+			DefaultChecker:   common.DefaultChecker{Funcs: []common.CheckerFunc{BallotTransactionsAllValid}},
+			NodeRunner:       nr,
+			Conf:             nr.Conf,
+			LocalNode:        nr.Node(),
+			Height:           3,
+			Transactions:     txs,
+			VotingHole:       voting.NOTYET,
+			transactionCache: NewTransactionCache(nr.Storage(), nr.TransactionPool),
+		}
+		checker.setValidTransactions(txs)
+
+		err := common.RunChecker(checker, common.DefaultDeferFunc)
+		require.NoError(t, err)
+		require.Equal(t, voting.NO, checker.VotingHole)
+
+		checker.Height = 2
+		checker.VotingHole = voting.NOTYET
+		err = common.RunChecker(checker, common.DefaultDeferFunc)
+		require.NoError(t, err)
+		require.Equal(t, voting.YES, checker.VotingHole)
+	}
+}
+
+func TestValidateTxManageData(t *testing.T) {
+	kps := keypair.Random()
+	kpt := keypair.Random()
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.BaseReserve.MustMult(2),
+	}
+	bat := block.BlockAccount{
+		Address: kpt.Address(),
+		Balance: common.BaseReserve,
+	}
+	bas.MustSave(st)
+	bat.MustSave(st)
+
+	makeTx := func(ops ...operation.Body) transaction.Transaction {
+		var operations []operation.Operation
+		for _, opb := range ops {
+			op, _ := operation.NewOperation(opb)
+			operations = append(operations, op)
+		}
+		ba, _ := block.GetBlockAccount(st, kps.Address())
+		tx, _ := transaction.NewTransaction(kps.Address(), ba.SequenceID, operations...)
+		return tx
+	}
+	finishTx := func(tx transaction.Transaction) {
+		for _, op := range tx.B.Operations {
+			require.NoError(t, finishOperation(st, kps.Address(), op, log))
+		}
+	}
+
+	{ // set new entry
+		tx := makeTx(operation.NewManageData("kyc", "showme"))
+		require.NoError(t, ValidateTx(st, common.Config{}, tx))
+		finishTx(tx)
+
+		ba, err := block.GetBlockAccount(st, kps.Address())
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), ba.DataEntries)
+
+		d, err := block.GetBlockAccountData(st, kps.Address(), "kyc")
+		require.NoError(t, err)
+		require.Equal(t, "showme", d.Value)
+	}
+
+	{ // new entries over the reserve
+		tx := makeTx(
+			operation.NewManageData("endpoint", "https://sebak"),
+			operation.NewManageData("name", "showme"),
+		)
+		require.Equal(t, errors.BlockAccountInsufficientReserve, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // payment over the reserve
+		tx := makeTx(operation.NewPayment(kpt.Address(), common.BaseReserve))
+		require.Equal(t, errors.BlockAccountInsufficientReserve, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // updating the existing entry does not need more reserve
+		tx := makeTx(
+			operation.NewManageData("kyc", "findme"),
+			operation.NewManageData("endpoint", "https://sebak"),
+			operation.NewManageData("endpoint", ""),
+		)
+		require.NoError(t, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // delete unknown entry
+		tx := makeTx(operation.NewManageData("endpoint", ""))
+		require.Equal(t, errors.BlockAccountDataDoesNotExists, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // delete entry
+		tx := makeTx(operation.NewManageData("kyc", ""))
+		require.NoError(t, ValidateTx(st, common.Config{}, tx))
+		finishTx(tx)
+
+		ba, err := block.GetBlockAccount(st, kps.Address())
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), ba.DataEntries)
+
+		exists, err := block.ExistsBlockAccountData(st, kps.Address(), "kyc")
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+}
+
+func TestValidateTxAccountMerge(t *testing.T) {
+	kps := keypair.Random()
+	kpt := keypair.Random()
+	kpFrozen := keypair.Random()
+
+	st := block.InitTestBlockchain()
+	defer st.Close()
+
+	bas := block.NewBlockAccount(kps.Address(), common.BaseReserve.MustMult(3))
+	bas.MustSave(st)
+
+	merge, _ := operation.NewOperation(operation.NewAccountMerge(kpt.Address()))
+	tx, _ := transaction.NewTransaction(kps.Address(), 0, merge)
+
+	{ // unknown target
+		require.Equal(t, errors.BlockAccountDoesNotExists, ValidateTx(st, common.Config{}, tx))
+	}
+
+	bat := block.NewBlockAccount(kpt.Address(), common.BaseReserve)
+	bat.MustSave(st)
+	require.NoError(t, ValidateTx(st, common.Config{}, tx))
+
+	{ // linked frozen account
+		create, _ := operation.NewOperation(operation.NewCreateAccount(kpFrozen.Address(), common.BaseReserve, kps.Address()))
+		txCreate, _ := transaction.NewTransaction(kps.Address(), 0, create)
+		bo, err := block.NewBlockOperationFromOperation(create, txCreate, 2)
+		require.NoError(t, err)
+		require.NoError(t, bo.Save(st))
+		block.NewBlockAccountLinked(kpFrozen.Address(), common.BaseReserve, kps.Address()).MustSave(st)
+
+		require.Equal(t, errors.BlockAccountHasFrozenAccounts, ValidateTx(st, common.Config{}, tx))
+
+		// the frozen account is already merged
+		ba, _ := block.GetBlockAccount(st, kpFrozen.Address())
+		require.NoError(t, ba.Remove(st))
+		require.NoError(t, ValidateTx(st, common.Config{}, tx))
+	}
+
+	{ // finish; the whole balance is transferred and the account is removed
+		require.NoError(t, block.NewBlockAccountData(kps.Address(), "kyc", "showme").Save(st))
+		latest := block.GetLatestBlock(st)
+		require.NoError(t, block.NewBlockAccountHistory(bas, latest.Height).Save(st))
+
+		tx.B.Fee = common.BaseFee
+		blk := block.TestMakeNewBlockWithPrevBlock(latest, []string{tx.GetHash()})
+		require.NoError(t, FinishTransactions(blk, []*transaction.Transaction{&tx}, st))
+
+		// the merged account does not exist in the history after the merge
+		_, err := block.GetBlockAccountHistoryAt(st, kps.Address(), blk.Height)
+		require.Equal(t, errors.BlockAccountDoesNotExists, err)
+		_, err = block.GetBlockAccountHistoryAt(st, kps.Address(), blk.Height+10)
+		require.Equal(t, errors.BlockAccountDoesNotExists, err)
+		h, err := block.GetBlockAccountHistoryAt(st, kps.Address(), latest.Height)
+		require.NoError(t, err)
+		require.Equal(t, bas.Balance, h.Balance)
+
+		exists, err := block.ExistsBlockAccount(st, kps.Address())
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		exists, err = block.ExistsBlockAccountData(st, kps.Address(), "kyc")
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		ba, err := block.GetBlockAccount(st, kpt.Address())
+		require.NoError(t, err)
+		require.Equal(t, common.BaseReserve.MustMult(4)-common.BaseFee, ba.Balance)
+
+		m, err := block.GetBlockAccountMerged(st, kps.Address())
+		require.NoError(t, err)
+		require.Equal(t, tx.B.SequenceID+1, m.SequenceID)
+	}
+
+	{ // the account created again continues the sequence ID of the merged account
+		create, _ := operation.NewOperation(operation.NewCreateAccount(kps.Address(), common.BaseReserve, ""))
+		txCreate, _ := transaction.NewTransaction(kpt.Address(), 0, create)
+		txCreate.B.Fee = common.BaseFee
+		blk := block.TestMakeNewBlockWithPrevBlock(block.GetLatestBlock(st), []string{txCreate.GetHash()})
+		require.NoError(t, FinishTransactions(blk, []*transaction.Transaction{&txCreate}, st))
+
+		ba, err := block.GetBlockAccount(st, kps.Address())
+		require.NoError(t, err)
+		require.Equal(t, tx.B.SequenceID+1, ba.SequenceID)
+
+		h, err := block.GetBlockAccountHistoryAt(st, kps.Address(), blk.Height)
+		require.NoError(t, err)
+		require.False(t, h.Merged)
+		require.Equal(t, ba.Balance, h.Balance)
+
+		// the merge transaction can not be replayed
+		require.Equal(t, errors.TransactionInvalidSequenceID, ValidateTx(st, common.Config{}, tx))
+	}
+}
+
+func TestBallotTransactionsMergedAccount(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr := createTestNodeRunner(1, conf)[0]
+
+	kpMerged := keypair.Random()
+	merge, _ := operation.NewOperation(operation.NewAccountMerge(keypair.Random().Address()))
+	txMerge, _ := transaction.NewTransaction(kpMerged.Address(), 0, merge)
+	txMerge.Sign(kpMerged, networkID)
+	nr.TransactionPool.Add(txMerge)
+
+	kp := keypair.Random()
+	txPayment := transaction.TestMakeTransactionWithKeypair(networkID, 1, kp, kpMerged)
+	nr.TransactionPool.Add(txPayment)
+
+	_, txOther := transaction.TestMakeTransaction(networkID, 1)
+	nr.TransactionPool.Add(txOther)
+
+	txs := []string{txMerge.GetHash(), txPayment.GetHash(), txOther.GetHash()}
+
+	{ // proposer excludes the transactions to the merged account
+		checker := &BallotTransactionChecker{
+			DefaultChecker:        common.DefaultChecker{Funcs: NewBallotTransactionCheckerFuncs},
+			NodeRunner:            nr,
+			Conf:                  nr.Conf,
+			LocalNode:             nr.Node(),
+			Height:                2,
+			Transactions:          txs,
+			CheckTransactionsOnly: true,
+			VotingHole:            voting.NOTYET,
+			transactionCache:      NewTransactionCache(nr.Storage(), nr.TransactionPool),
+		}
+
+		err := common.RunChecker(checker, common.DefaultDeferFunc)
+		require.NoError(t, err)
+		require.Equal(t, []string{txMerge.GetHash(), txOther.GetHash()}, checker.ValidTransactions)
+	}
+
+	{ // ballot, which has the transactions to the merged account, is invalid
+		checker := &BallotTransactionChecker{
+			DefaultChecker:   common.DefaultChecker{Funcs: []common.CheckerFunc{BallotTransactionsMergedAccount}},
+			NodeRunner:       nr,
+			Conf:             nr.Conf,
+			LocalNode:        nr.Node(),
+			Height:           2,
+			Transactions:     txs,
+			VotingHole:       voting.NOTYET,
+			transactionCache: NewTransactionCache(nr.Storage(), nr.TransactionPool),
+		}
+		checker.setValidTransactions(txs)
+
+		err := common.RunChecker(checker, common.DefaultDeferFunc)
+		require.Equal(t, errors.BlockAccountMergedInBallot, err)
+	}
+}
+
+func TestValidateTxWithResults(t *testing.T) {
+	kps := keypair.Random()
+	kpt := keypair.Random()
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.BaseReserve.MustMult(10),
+	}
+	bat := block.BlockAccount{
+		Address: kpt.Address(),
+		Balance: common.BaseReserve,
+	}
+	bas.MustSave(st)
+	bat.MustSave(st)
+
+	unknown := keypair.Random().Address()
+	var operations []operation.Operation
+	for _, target := range []string{kpt.Address(), unknown, kpt.Address()} {
+		op, _ := operation.NewOperation(operation.NewPayment(target, common.Amount(1)))
+		operations = append(operations, op)
+	}
+	tx, _ := transaction.NewTransaction(kps.Address(), bas.SequenceID, operations...)
+
+	results, err := ValidateTxWithResults(st, common.Config{}, tx)
+	require.Equal(t, errors.BlockAccountDoesNotExists, err)
+	require.Equal(t, errors.BlockAccountDoesNotExists, ValidateTx(st, common.Config{}, tx))
+	require.Equal(t, 3, len(results))
+	for i, r := range results {
+		require.Equal(t, i, r.Index)
+		require.Equal(t, operation.TypePayment, r.Type)
+	}
+	require.True(t, results[0].IsValid())
+	require.False(t, results[1].IsValid())
+	require.Equal(t, errors.BlockAccountDoesNotExists.Code, results[1].Code)
+	require.True(t, results[2].IsValid())
+
+	{ // the rejected transaction is kept with the results
+		checker := &MessageChecker{
+			Storage:     st,
+			Transaction: tx,
+			Log:         log,
+			Conf:        common.Config{},
+
+			RejectedTransactions: block.NewRejectedTransactions(10, time.Hour),
+		}
+		err = MessageValidate(checker)
+		require.Error(t, err)
+		e, ok := err.(*errors.Error)
+		require.True(t, ok)
+		require.Equal(t, errors.BlockAccountDoesNotExists.Code, e.Code)
+		require.Equal(t, results, e.Data["operations"])
+
+		rt, found := checker.RejectedTransactions.Get(tx.GetHash())
+		require.True(t, found)
+		require.Equal(t, errors.BlockAccountDoesNotExists.Code, rt.Code)
+		require.Equal(t, results, rt.Operations)
+	}
+
+	{ // the invalid source does not have results
+		tx, _ := transaction.NewTransaction(unknown, 0, operations...)
+		results, err := ValidateTxWithResults(st, common.Config{}, tx)
+		require.Equal(t, errors.BlockAccountDoesNotExists, err)
+		require.Equal(t, 0, len(results))
+	}
+}
+
+func TestValidateTxFee(t *testing.T) {
+	kps := keypair.Random()
+	kpt := keypair.Random()
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+	bas := block.BlockAccount{
+		Address: kps.Address(),
+		Balance: common.BaseReserve.MustMult(10),
+	}
+	bat := block.BlockAccount{
+		Address: kpt.Address(),
+		Balance: common.BaseReserve,
+	}
+	bas.MustSave(st)
+	bat.MustSave(st)
+
+	config := common.NewTestConfig()
+
+	op, _ := operation.NewOperation(operation.NewPayment(kpt.Address(), common.Amount(1)))
+	tx, _ := transaction.NewTransaction(kps.Address(), bas.SequenceID, op)
+	require.Equal(t, common.BaseFee, tx.B.Fee)
+
+	// without blocks
+	require.NoError(t, ValidateTx(st, config, tx))
+
+	// the recent blocks are full
+	blk := block.NewBlock(
+		kps.Address(),
+		voting.Basis{Height: common.GenesisBlockHeight, TotalOps: uint64(common.BaseFeeBlocks * common.DefaultOperationsInBallotLimit)},
+		"",
+		nil,
+		common.NowISO8601(),
+	)
+	require.NoError(t, blk.Save(st))
+
+	require.Equal(t, errors.InvalidFee, ValidateTx(st, config, tx))
+
+	tx.B.Fee = common.BaseFee.MustMult(int(common.BaseFeeMaxMultiplier))
+	require.NoError(t, ValidateTx(st, config, tx))
+}
@@ -38,8 +38,10 @@ type MessageChecker struct {
 	Log             logging.Logger
 	Consensus       *consensus.ISAAC
 	TransactionPool *transaction.Pool
-	Storage         *storage.LevelDBBackend
+	Storage         storage.Backend
 	Transaction     transaction.Transaction
+
+	RejectedTransactions *block.RejectedTransactions
 }
 
 // TransactionUnmarshal makes `Transaction` from
@@ -74,7 +76,7 @@ func HasTransaction(c common.Checker, args ...interface{}) (err error) {
 		return errors.NewButKnownMessage
 	}
 
-	if exists, err := block.ExistsBlockTransaction(checker.Storage, hash); err != nil {
+	if exists, err := block.IsConfirmedBlockTransaction(checker.Storage, hash); err != nil {
 		return err
 	} else if exists {
 		return errors.NewButKnownMessage
@@ -84,26 +86,57 @@ func HasTransaction(c common.Checker, args ...interface{}) (err error) {
 }
 
 // SameSource checks there are transactions which has same source in the
-// `Pool`.
+// `Pool`. The pending transaction can be replaced by the transaction, which
+// has the same `SequenceID` and the higher `Fee`, see
+// `Transaction.CanReplace`.
 func MessageHasSameSource(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*MessageChecker)
 
-	if checker.TransactionPool.IsSameSource(checker.Transaction.Source()) {
+	tx := checker.Transaction
+	old, found := checker.TransactionPool.GetFromSource(tx.Source())
+	if !found {
+		return
+	}
+
+	if old.B.SequenceID != tx.B.SequenceID {
 		err = errors.TransactionSameSourceInPool
 		return
 	}
 
+	if !tx.CanReplace(old) {
+		err = errors.TransactionReplacementFeeTooLow
+		return
+	}
+
+	checker.Log.Debug("transaction will replace the pending transaction", "replaced", old.GetHash())
+
 	return
 }
 
-// MessageValidate validates.
+// MessageValidate validates. If some operations are invalid, the results of
+// operations are attached to the error as "operations" and they are stored
+// as `block.RejectedTransaction` in `RejectedTransactions`.
 func MessageValidate(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*MessageChecker)
 
-	if err = ValidateTx(checker.Storage, checker.Conf, checker.Transaction); err != nil {
+	var results []transaction.OperationResult
+	if results, err = ValidateTxWithResults(checker.Storage, checker.Conf, checker.Transaction); err == nil {
+		return
+	}
+	if len(results) < 1 {
 		return
 	}
 
+	checker.RejectedTransactions.Add(
+		block.NewRejectedTransaction(checker.Transaction.GetHash(), err, results),
+	)
+
+	if e, ok := err.(*errors.Error); ok {
+		err = e.Clone().SetData("operations", results)
+	} else {
+		err = errors.InvalidOperation.Clone().SetData("error", err.Error()).SetData("operations", results)
+	}
+
 	return
 }
 
@@ -112,13 +145,7 @@ func MessageValidate(c common.Checker, args ...interface{}) (err error) {
 func PushIntoTransactionPool(c common.Checker, args ...interface{}) error {
 	checker := c.(*MessageChecker)
 
-	tx := checker.Transaction
-	err := checker.TransactionPool.Add(tx)
-	if err == errors.TransactionPoolFull {
-		return err
-	}
-
-	if _, err = block.SaveTransactionPool(checker.Storage, tx); err != nil {
+	if err := pushIntoTransactionPool(checker, checker.TransactionPool.Add); err != nil {
 		return err
 	}
 
@@ -131,13 +158,7 @@ func PushIntoTransactionPool(c common.Checker, args ...interface{}) error {
 func PushIntoTransactionPoolFromClient(c common.Checker, args ...interface{}) error {
 	checker := c.(*MessageChecker)
 
-	tx := checker.Transaction
-	err := checker.TransactionPool.AddFromClient(tx)
-	if err == errors.TransactionPoolFull {
-		return err
-	}
-
-	if _, err = block.SaveTransactionPool(checker.Storage, tx); err != nil {
+	if err := pushIntoTransactionPool(checker, checker.TransactionPool.AddFromClient); err != nil {
 		return err
 	}
 
@@ -150,18 +171,49 @@ func PushIntoTransactionPoolFromClient(c common.Checker, args ...interface{}) er
 func PushIntoTransactionPoolFromNode(c common.Checker, args ...interface{}) error {
 	checker := c.(*MessageChecker)
 
+	if err := pushIntoTransactionPool(checker, checker.TransactionPool.AddFromNode); err != nil {
+		return err
+	}
+
+	checker.Log.Debug("push transaction into TransactionPool from node")
+
+	return nil
+}
+
+// pushIntoTransactionPool adds the transaction by `add` and saves it as
+// `block.TransactionPool`. The `block.TransactionPool` of the evicted
+// transaction is deleted and the evicted one is kept in
+// `RejectedTransactions` as replaced or evicted.
+func pushIntoTransactionPool(checker *MessageChecker, add func(transaction.Transaction) (string, error)) error {
 	tx := checker.Transaction
-	err := checker.TransactionPool.AddFromNode(tx)
+
+	var old string
+	if o, found := checker.TransactionPool.GetFromSource(tx.Source()); found {
+		old = o.GetHash()
+	}
+
+	evicted, err := add(tx)
 	if err == errors.TransactionPoolFull {
 		return err
 	}
 
+	if len(evicted) > 0 {
+		reason := errors.TransactionEvictedFromPool
+		if evicted == old {
+			reason = errors.TransactionReplaced
+		}
+
+		if err = block.DeleteTransactionPool(checker.Storage, evicted); err != nil {
+			checker.Log.Error("failed to delete evicted transaction", "evicted", evicted, "error", err)
+		}
+		checker.RejectedTransactions.Add(block.NewRejectedTransaction(evicted, reason, nil))
+		checker.Log.Debug("transaction evicted from TransactionPool", "evicted", evicted, "reason", reason)
+	}
+
 	if _, err = block.SaveTransactionPool(checker.Storage, tx); err != nil {
 		return err
 	}
 
-	checker.Log.Debug("push transaction into TransactionPool from node")
-
 	return nil
 }
 
@@ -5,9 +5,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
 )
 
 func TestMessageChecker(t *testing.T) {
@@ -63,6 +65,26 @@ func TestMessageChecker(t *testing.T) {
 	require.Equal(t, err, errors.NewButKnownMessage)
 }
 
+func TestMessageCheckerPrunedTransaction(t *testing.T) {
+	_, tx := transaction.TestMakeTransaction(networkID, 1)
+	nodeRunner, localNode := MakeNodeRunner()
+	checker := &MessageChecker{
+		DefaultChecker:  common.DefaultChecker{},
+		LocalNode:       localNode,
+		Consensus:       nodeRunner.Consensus(),
+		Storage:         nodeRunner.Storage(),
+		TransactionPool: nodeRunner.TransactionPool,
+		Transaction:     tx,
+		Log:             nodeRunner.Log(),
+		Conf:            nodeRunner.Conf,
+	}
+	require.NoError(t, HasTransaction(checker))
+
+	// the pruned transaction is known
+	require.NoError(t, nodeRunner.Storage().New(block.GetPrunedBlockTransactionKey(tx.GetHash()), uint64(2)))
+	require.Equal(t, errors.NewButKnownMessage, HasTransaction(checker))
+}
+
 func TestMessageCheckerWithInvalidHash(t *testing.T) {
 	_, invalidTx := transaction.TestMakeTransaction(networkID, 1)
 	invalidTx.H.Hash = "wrong hash"
@@ -94,3 +116,54 @@ func TestMessageCheckerWithInvalidHash(t *testing.T) {
 	require.EqualError(t, err, "unexpected end of JSON input")
 	require.NotEqual(t, checker.Transaction, invalidTx)
 }
+
+func TestMessageHasSameSourceReplace(t *testing.T) {
+	nodeRunner, localNode := MakeNodeRunner()
+	kp, tx := transaction.TestMakeTransaction(networkID, 1)
+	_, err := nodeRunner.TransactionPool.Add(tx)
+	require.NoError(t, err)
+
+	check := func(newTx transaction.Transaction) error {
+		checker := &MessageChecker{
+			Consensus:       nodeRunner.Consensus(),
+			Storage:         nodeRunner.Storage(),
+			TransactionPool: nodeRunner.TransactionPool,
+			LocalNode:       localNode,
+			Log:             nodeRunner.Log(),
+			Conf:            nodeRunner.Conf,
+			Transaction:     newTx,
+		}
+		return MessageHasSameSource(checker)
+	}
+
+	{ // different sequence id
+		newTx := tx
+		newTx.B.SequenceID = tx.B.SequenceID + 1
+		newTx.B.Fee = tx.B.Fee.MustAdd(1)
+		newTx.Sign(kp, networkID)
+		require.Equal(t, errors.TransactionSameSourceInPool, check(newTx))
+	}
+
+	{ // same fee
+		newTx := tx
+		newTx.B.Operations = []operation.Operation{operation.MakeTestPayment(-1)}
+		newTx.Sign(kp, networkID)
+		require.Equal(t, errors.TransactionReplacementFeeTooLow, check(newTx))
+	}
+
+	{ // higher fee
+		newTx := tx
+		newTx.B.Fee = tx.B.Fee.MustAdd(1)
+		newTx.Sign(kp, networkID)
+		require.NoError(t, check(newTx))
+
+		evicted, err := nodeRunner.TransactionPool.Add(newTx)
+		require.NoError(t, err)
+		require.Equal(t, tx.GetHash(), evicted)
+		require.False(t, nodeRunner.TransactionPool.Has(tx.GetHash()))
+		require.True(t, nodeRunner.TransactionPool.Has(newTx.GetHash()))
+
+		// the evicted transaction can not come back
+		require.Equal(t, errors.TransactionReplacementFeeTooLow, check(tx))
+	}
+}
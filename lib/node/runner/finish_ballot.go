@@ -5,9 +5,11 @@ import (
 
 	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/metrics"
 	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb"
 	"boscoin.io/sebak/lib/transaction"
 	"boscoin.io/sebak/lib/transaction/operation"
 )
@@ -19,7 +21,7 @@ func finishBallot(nr *NodeRunner, b ballot.Ballot, log logging.Logger) (*block.B
 		return nil, nil, err
 	}
 
-	var bs *storage.LevelDBBackend
+	var bs storage.Backend
 	if bs, err = nr.Storage().OpenBatch(); err != nil {
 		return nil, nil, err
 	}
@@ -52,7 +54,7 @@ func finishBallot(nr *NodeRunner, b ballot.Ballot, log logging.Logger) (*block.B
 	return blk, proposedTxs, nil
 }
 
-func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, proposedTransactions []*transaction.Transaction, log logging.Logger) (*block.Block, error) {
+func finishBallotWithProposedTxs(st storage.Backend, b ballot.Ballot, proposedTransactions []*transaction.Transaction, log logging.Logger) (*block.Block, error) {
 	var err error
 	var isValid bool
 	if isValid, err = isValidRound(st, b.VotingBasis(), log); err != nil || !isValid {
@@ -69,12 +71,35 @@ func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, pr
 	r.TotalTxs += uint64(len(b.Transactions()) + 1) // + 1 for ProposerTransaction
 	r.TotalOps += uint64(nOps + len(b.ProposerTransaction().B.Operations))
 
-	blk := block.NewBlock(
+	// the accounts are changed before the block is made, because the block
+	// has the root of the changed state.
+	if err = applyTransactions(st, proposedTransactions, log); err != nil {
+		return nil, err
+	}
+
+	if err = ProcessProposerTransaction(st, b.ProposerTransaction(), log); err != nil {
+		log.Error("failed to process proposer transaction", "ptx", b.ProposerTransaction(), "error", err)
+		return nil, err
+	}
+
+	var stateRoot string
+	version := block.GetBlockVersion(r.Height)
+	if version >= block.BlockVersionV2 {
+		stateRoot, err = CommitState(st, block.GetLatestBlock(st).StateRoot, proposedTransactions, b.ProposerTransaction())
+		if err != nil {
+			log.Error("failed to commit state", "error", err)
+			return nil, err
+		}
+	}
+
+	blk := block.NewBlockWithVersion(
+		version,
 		b.Proposer(),
 		r,
 		b.ProposerTransaction().GetHash(),
 		b.Transactions(),
 		b.ProposerConfirmed(),
+		stateRoot,
 	)
 
 	if err = blk.Save(st); err != nil {
@@ -90,25 +115,31 @@ func finishBallotWithProposedTxs(st *storage.LevelDBBackend, b ballot.Ballot, pr
 		"total-txs", blk.TotalTxs,
 		"total-ops", blk.TotalOps,
 		"proposer", blk.Proposer,
+		"state-root", blk.StateRoot,
 	)
 	metrics.Consensus.SetHeight(blk.Height)
 	metrics.Consensus.SetRounds(blk.Round)
 	metrics.Consensus.SetTotalTxs(blk.TotalTxs)
 	metrics.Consensus.SetTotalOps(blk.TotalOps)
 
-	if err = FinishTransactions(*blk, proposedTransactions, st); err != nil {
+	if err = saveBlockTransactions(st, *blk, proposedTransactions); err != nil {
 		return nil, err
 	}
 
-	if err = FinishProposerTransaction(st, *blk, b.ProposerTransaction(), log); err != nil {
-		log.Error("failed to finish proposer transaction", "block", blk.Hash, "ptx", b.ProposerTransaction(), "error", err)
+	if err = saveProposerTransaction(st, *blk, b.ProposerTransaction()); err != nil {
+		log.Error("failed to save proposer transaction", "block", blk.Hash, "ptx", b.ProposerTransaction(), "error", err)
+		return nil, err
+	}
+
+	changed := getChangedAddresses(proposedTransactions, b.ProposerTransaction())
+	if err = block.SaveBlockAccountHistories(st, blk.Height, changed); err != nil {
 		return nil, err
 	}
 
 	return blk, nil
 }
 
-func getProposedTransactions(st *storage.LevelDBBackend, pTxHashes []string, transactionPool *transaction.Pool) ([]*transaction.Transaction, error) {
+func getProposedTransactions(st storage.Backend, pTxHashes []string, transactionPool *transaction.Pool) ([]*transaction.Transaction, error) {
 	proposedTransactions := make([]*transaction.Transaction, 0, len(pTxHashes))
 	var err error
 	for _, hash := range pTxHashes {
@@ -125,15 +156,42 @@ func getProposedTransactions(st *storage.LevelDBBackend, pTxHashes []string, tra
 	return proposedTransactions, nil
 }
 
-func FinishTransactions(blk block.Block, transactions []*transaction.Transaction, st *storage.LevelDBBackend) (err error) {
+func FinishTransactions(blk block.Block, transactions []*transaction.Transaction, st storage.Backend) (err error) {
+	if err = saveBlockTransactions(st, blk, transactions); err != nil {
+		return
+	}
+
+	if err = applyTransactions(st, transactions, log); err != nil {
+		return
+	}
+
+	return block.SaveBlockAccountHistories(st, blk.Height, getTransactionsAddresses(transactions))
+}
+
+func saveBlockTransactions(st storage.Backend, blk block.Block, transactions []*transaction.Transaction) (err error) {
 	for _, tx := range transactions {
 		bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, *tx)
 		if err = bt.Save(st); err != nil {
 			return
 		}
+	}
+
+	return
+}
+
+// applyTransactions changes the accounts by the operations of transactions.
+func applyTransactions(st storage.Backend, transactions []*transaction.Transaction, log logging.Logger) (err error) {
+	for _, tx := range transactions {
+		var mergeOps []operation.Operation
 		for _, op := range tx.B.Operations {
+			// `AccountMerge` transfers the remaining balance, so it is
+			// finished after the source account pays.
+			if op.H.Type == operation.TypeAccountMerge {
+				mergeOps = append(mergeOps, op)
+				continue
+			}
 			if err = finishOperation(st, tx.B.Source, op, log); err != nil {
-				log.Error("failed to finish operation", "block", blk.Hash, "BlockTransaction", bt.Hash, "operation", op, "error", err)
+				log.Error("failed to finish operation", "transaction", tx.GetHash(), "operation", op, "error", err)
 				return err
 			}
 		}
@@ -153,13 +211,20 @@ func FinishTransactions(blk block.Block, transactions []*transaction.Transaction
 		if err = baSource.Save(st); err != nil {
 			return
 		}
+
+		for _, op := range mergeOps {
+			if err = finishOperation(st, tx.B.Source, op, log); err != nil {
+				log.Error("failed to finish operation", "transaction", tx.GetHash(), "operation", op, "error", err)
+				return err
+			}
+		}
 	}
 
 	return
 }
 
 // finishOperation do finish the task after consensus by the type of each operation.
-func finishOperation(st *storage.LevelDBBackend, source string, op operation.Operation, log logging.Logger) (err error) {
+func finishOperation(st storage.Backend, source string, op operation.Operation, log logging.Logger) (err error) {
 	switch op.H.Type {
 	case operation.TypeCreateAccount:
 		pop, ok := op.B.(operation.CreateAccount)
@@ -188,6 +253,30 @@ func finishOperation(st *storage.LevelDBBackend, source string, op operation.Ope
 			return errors.UnknownOperationType
 		}
 		return finishInflationPF(st, source, pop, log)
+	case operation.TypeManageSigners:
+		pop, ok := op.B.(operation.ManageSigners)
+		if !ok {
+			return errors.UnknownOperationType
+		}
+		return finishManageSigners(st, source, pop, log)
+	case operation.TypeManageData:
+		pop, ok := op.B.(operation.ManageData)
+		if !ok {
+			return errors.UnknownOperationType
+		}
+		return finishManageData(st, source, pop, log)
+	case operation.TypeAccountMerge:
+		pop, ok := op.B.(operation.AccountMerge)
+		if !ok {
+			return errors.UnknownOperationType
+		}
+		return finishAccountMerge(st, source, pop, log)
+	case operation.TypeUpdateValidators:
+		pop, ok := op.B.(operation.UpdateValidators)
+		if !ok {
+			return errors.UnknownOperationType
+		}
+		return finishUpdateValidators(st, op, pop, log)
 
 	default:
 		err = errors.UnknownOperationType
@@ -195,7 +284,25 @@ func finishOperation(st *storage.LevelDBBackend, source string, op operation.Ope
 	}
 }
 
-func finishCreateAccount(st *storage.LevelDBBackend, source string, op operation.CreateAccount, log logging.Logger) (err error) {
+func finishUpdateValidators(st storage.Backend, op operation.Operation, opb operation.UpdateValidators, log logging.Logger) (err error) {
+	hash := common.MustMakeObjectHashString(op)
+
+	// the same change does not need to be stored again
+	var exists bool
+	if exists, err = st.Has(block.GetValidatorSetChangeKey(opb.Height, hash)); err != nil || exists {
+		return
+	}
+
+	c := block.NewValidatorSetChange(hash, opb.Height, opb.Add, opb.Endpoints, opb.Remove)
+	if err = c.Save(st); err != nil {
+		return
+	}
+	log.Debug("validator set change saved", "change", c)
+
+	return
+}
+
+func finishCreateAccount(st storage.Backend, source string, op operation.CreateAccount, log logging.Logger) (err error) {
 	if _, err = block.GetBlockAccount(st, source); err != nil {
 		err = errors.BlockAccountDoesNotExists
 		return
@@ -214,6 +321,20 @@ func finishCreateAccount(st *storage.LevelDBBackend, source string, op operation
 		op.GetAmount(),
 		op.Linked,
 	)
+
+	// the account created again after merged continues the sequence ID of
+	// the merged account.
+	var merged bool
+	if merged, err = block.ExistsBlockAccountMerged(st, op.TargetAddress()); err != nil {
+		return
+	} else if merged {
+		var m *block.BlockAccountMerged
+		if m, err = block.GetBlockAccountMerged(st, op.TargetAddress()); err != nil {
+			return
+		}
+		baTarget.SequenceID = m.SequenceID
+	}
+
 	if err = baTarget.Save(st); err != nil {
 		return
 	}
@@ -221,7 +342,7 @@ func finishCreateAccount(st *storage.LevelDBBackend, source string, op operation
 	return
 }
 
-func finishPayment(st *storage.LevelDBBackend, source string, op operation.Payment, log logging.Logger) (err error) {
+func finishPayment(st storage.Backend, source string, op operation.Payment, log logging.Logger) (err error) {
 	if _, err = block.GetBlockAccount(st, source); err != nil {
 		err = errors.BlockAccountDoesNotExists
 		return
@@ -243,11 +364,11 @@ func finishPayment(st *storage.LevelDBBackend, source string, op operation.Payme
 	return
 }
 
-func finishUnfreezeRequest(st *storage.LevelDBBackend, source string, opb operation.UnfreezeRequest, log logging.Logger) (err error) {
+func finishUnfreezeRequest(st storage.Backend, source string, opb operation.UnfreezeRequest, log logging.Logger) (err error) {
 	return
 }
 
-func finishInflationPF(st *storage.LevelDBBackend, source string, opb operation.InflationPF, log logging.Logger) (err error) {
+func finishInflationPF(st storage.Backend, source string, opb operation.InflationPF, log logging.Logger) (err error) {
 
 	if opb.Amount < 1 {
 		return
@@ -269,11 +390,122 @@ func finishInflationPF(st *storage.LevelDBBackend, source string, opb operation.
 	return
 }
 
-func FinishProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx ballot.ProposerTransaction, log logging.Logger) (err error) {
-	if err = ProcessProposerTransaction(st, blk, ptx, log); err != nil {
+func finishManageSigners(st storage.Backend, source string, opb operation.ManageSigners, log logging.Logger) (err error) {
+	var baSource *block.BlockAccount
+	if baSource, err = block.GetBlockAccount(st, source); err != nil {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	baSource.Signers = opb.Signers
+	baSource.Thresholds = opb.Thresholds
+
+	if err = baSource.Save(st); err != nil {
+		return
+	}
+
+	return
+}
+
+func finishManageData(st storage.Backend, source string, opb operation.ManageData, log logging.Logger) (err error) {
+	var baSource *block.BlockAccount
+	if baSource, err = block.GetBlockAccount(st, source); err != nil {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	var exists bool
+	if exists, err = block.ExistsBlockAccountData(st, source, opb.Key); err != nil {
+		return
+	}
+
+	if opb.IsDelete() {
+		if !exists {
+			err = errors.BlockAccountDataDoesNotExists
+			return
+		}
+		if err = block.RemoveBlockAccountData(st, source, opb.Key); err != nil {
+			return
+		}
+		baSource.DataEntries--
+	} else {
+		if err = block.NewBlockAccountData(source, opb.Key, opb.Value).Save(st); err != nil {
+			return
+		}
+		if !exists {
+			baSource.DataEntries++
+		}
+	}
+
+	if err = baSource.Save(st); err != nil {
+		return
+	}
+
+	return
+}
+
+func finishAccountMerge(st storage.Backend, source string, opb operation.AccountMerge, log logging.Logger) (err error) {
+	var baSource *block.BlockAccount
+	if baSource, err = block.GetBlockAccount(st, source); err != nil {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	var baTarget *block.BlockAccount
+	if baTarget, err = block.GetBlockAccount(st, opb.TargetAddress()); err != nil {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	if err = baTarget.Deposit(baSource.GetBalance()); err != nil {
+		return
+	}
+	if err = baTarget.Save(st); err != nil {
+		return
+	}
+
+	// the data entries are removed with account
+	var keys []string
+	iterFunc, closeFunc := block.GetBlockAccountDataByAddress(st, source, nil)
+	for {
+		d, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		keys = append(keys, d.Key)
+	}
+	closeFunc()
+
+	for _, key := range keys {
+		if err = block.RemoveBlockAccountData(st, source, key); err != nil {
+			return
+		}
+	}
+
+	if err = block.NewBlockAccountMerged(baSource).Save(st); err != nil {
+		return
+	}
+
+	if err = baSource.Remove(st); err != nil {
+		return
+	}
+
+	return
+}
+
+func FinishProposerTransaction(st storage.Backend, blk block.Block, ptx ballot.ProposerTransaction, log logging.Logger) (err error) {
+	if err = ProcessProposerTransaction(st, ptx, log); err != nil {
 		return err
 	}
 
+	if err = saveProposerTransaction(st, blk, ptx); err != nil {
+		return
+	}
+
+	return block.SaveBlockAccountHistories(st, blk.Height, getProposerTransactionAddresses(ptx))
+}
+
+func saveProposerTransaction(st storage.Backend, blk block.Block, ptx ballot.ProposerTransaction) (err error) {
 	bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, ptx.Transaction)
 	if err = bt.Save(st); err != nil {
 		return
@@ -285,7 +517,7 @@ func FinishProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx
 	return
 }
 
-func ProcessProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx ballot.ProposerTransaction, log logging.Logger) (err error) {
+func ProcessProposerTransaction(st storage.Backend, ptx ballot.ProposerTransaction, log logging.Logger) (err error) {
 	{
 		var opb operation.CollectTxFee
 		if opb, err = ptx.CollectTxFee(); err != nil {
@@ -309,7 +541,88 @@ func ProcessProposerTransaction(st *storage.LevelDBBackend, blk block.Block, ptx
 	return
 }
 
-func finishCollectTxFee(st *storage.LevelDBBackend, opb operation.CollectTxFee, log logging.Logger) (err error) {
+// CommitState commits the state, which is changed by the transactions and
+// the proposer transaction, into the state trie of the previous block and
+// returns the new state root; see `getStateChanges`.
+func CommitState(st storage.Backend, root string, transactions []*transaction.Transaction, ptx ballot.ProposerTransaction) (string, error) {
+	return statedb.Commit(st, root, getStateChanges(transactions, ptx))
+}
+
+// getStateChanges returns the state, which is changed by the transactions and
+// the proposer transaction. Since `block.BlockVersionV2` the state root covers
+// the changed accounts, the account data by `ManageData`, the unfreezing
+// requests by `UnfreezeRequest`, the merged accounts by `AccountMerge` and the
+// validator set changes by `UpdateValidators`, so the node from the snapshot
+// can verify the whole imported state.
+func getStateChanges(transactions []*transaction.Transaction, ptx ballot.ProposerTransaction) statedb.Changes {
+	changes := statedb.Changes{Accounts: getChangedAddresses(transactions, ptx)}
+	for _, tx := range transactions {
+		for _, op := range tx.B.Operations {
+			switch op.H.Type {
+			case operation.TypeManageData:
+				if pop, ok := op.B.(operation.ManageData); ok {
+					changes.AddAccountData(tx.B.Source, pop.Key)
+				}
+			case operation.TypeUnfreezingRequest:
+				changes.UnfreezingRequests = append(
+					changes.UnfreezingRequests,
+					block.NewBlockOperationKey(common.MustMakeObjectHashString(op), tx.GetHash()),
+				)
+			case operation.TypeAccountMerge:
+				changes.MergedAccounts = append(changes.MergedAccounts, tx.B.Source)
+			case operation.TypeUpdateValidators:
+				if pop, ok := op.B.(operation.UpdateValidators); ok {
+					changes.ValidatorSetChanges = append(
+						changes.ValidatorSetChanges,
+						block.GetValidatorSetChangeKey(pop.Height, common.MustMakeObjectHashString(op)),
+					)
+				}
+			}
+		}
+	}
+
+	return changes
+}
+
+// getChangedAddresses returns the accounts, which are changed by the
+// transactions and the proposer transaction.
+func getChangedAddresses(transactions []*transaction.Transaction, ptx ballot.ProposerTransaction) []string {
+	return append(getTransactionsAddresses(transactions), getProposerTransactionAddresses(ptx)...)
+}
+
+func getTransactionsAddresses(transactions []*transaction.Transaction) (addresses []string) {
+	for _, tx := range transactions {
+		addresses = append(addresses, tx.B.Source)
+		for _, op := range tx.B.Operations {
+			addresses = append(addresses, getOperationAddresses(op)...)
+		}
+	}
+
+	return
+}
+
+func getProposerTransactionAddresses(ptx ballot.ProposerTransaction) (addresses []string) {
+	for _, op := range ptx.B.Operations {
+		addresses = append(addresses, getOperationAddresses(op)...)
+	}
+
+	return
+}
+
+// getOperationAddresses returns the accounts, except the source, which are
+// changed by the operation.
+func getOperationAddresses(op operation.Operation) (addresses []string) {
+	if pop, ok := op.B.(operation.Targetable); ok {
+		addresses = append(addresses, pop.TargetAddress())
+	}
+	if pop, ok := op.B.(operation.InflationPF); ok {
+		addresses = append(addresses, pop.FundingAddress)
+	}
+
+	return
+}
+
+func finishCollectTxFee(st storage.Backend, opb operation.CollectTxFee, log logging.Logger) (err error) {
 	if opb.Amount < 1 {
 		return
 	}
@@ -330,7 +643,7 @@ func finishCollectTxFee(st *storage.LevelDBBackend, opb operation.CollectTxFee,
 	return
 }
 
-func finishInflation(st *storage.LevelDBBackend, opb operation.Inflation, log logging.Logger) (err error) {
+func finishInflation(st storage.Backend, opb operation.Inflation, log logging.Logger) (err error) {
 	if opb.Amount < 1 {
 		return
 	}
@@ -12,6 +12,9 @@ import (
 
 	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/node"
+	"boscoin.io/sebak/lib/transaction"
 	"boscoin.io/sebak/lib/voting"
 )
 
@@ -88,3 +91,88 @@ func TestISAACSimulationProposer(t *testing.T) {
 	require.Equal(t, 1, len(block.Transactions))
 	require.Equal(t, tx.GetHash(), block.Transactions[0])
 }
+
+/*
+TestISAACSimulationReputationProposer indicates the following:
+	1. There are 5 nodes and threshold is 4; the proposer is selected by `ReputationSelector`.
+	2. The proposer of round 0 is down, so the block is confirmed in round 1.
+	3. In the next block, the proposer of round 0 is the validator, which did not miss its proposal.
+	4. The ballot of the missed validator in round 0 has invalid proposer.
+*/
+func TestISAACSimulationReputationProposer(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, _ := createNodeRunnerForTesting(5, conf, nil)
+
+	var validators []string
+	byAddress := map[string]*node.LocalNode{}
+	for _, nd := range nodes {
+		validators = append(validators, nd.Address())
+		byAddress[nd.Address()] = nd
+	}
+	selector := consensus.NewReputationSelector(nr.Storage(), validators, consensus.ReputationWindow)
+	nr.Consensus().SetProposerSelector(selector)
+
+	confirm := func(round uint64, proposer *node.LocalNode, tx transaction.Transaction, down string) {
+		b := nr.Consensus().LatestBlock()
+		votingBasis := voting.Basis{
+			Round:     round,
+			Height:    b.Height,
+			BlockHash: b.Hash,
+			TotalTxs:  b.TotalTxs,
+			TotalOps:  b.TotalOps,
+		}
+
+		for _, state := range []ballot.State{ballot.StateSIGN, ballot.StateACCEPT} {
+			for _, nd := range nodes {
+				if nd.Address() == down {
+					continue
+				}
+				err := ReceiveBallot(nr, GenerateBallot(proposer, votingBasis, tx, state, nd, conf))
+				require.NoError(t, err)
+			}
+		}
+	}
+
+	latest := nr.Consensus().LatestBlock()
+	missed, err := nr.Consensus().SelectProposer(latest.Height, 0)
+	require.NoError(t, err)
+	proposer, err := nr.Consensus().SelectProposer(latest.Height, 1)
+	require.NoError(t, err)
+
+	tx, _ := GetTransaction()
+	nr.TransactionPool.Add(tx)
+	confirm(1, byAddress[proposer], tx, missed)
+
+	blk := nr.Consensus().LatestBlock()
+	require.Equal(t, latest.Height+1, blk.Height)
+	require.Equal(t, uint64(1), blk.Round)
+	require.Equal(t, proposer, blk.Proposer)
+
+	// the missed validator is moved behind
+	order, err := selector.Order(blk.Height)
+	require.NoError(t, err)
+	require.Equal(t, missed, order[len(order)-1])
+
+	next, err := nr.Consensus().SelectProposer(blk.Height, 0)
+	require.NoError(t, err)
+	require.NotEqual(t, missed, next)
+
+	tx, _, _ = GetCreateAccountTransaction(1, uint64(common.BaseReserve))
+	nr.TransactionPool.Add(tx)
+
+	basis := voting.Basis{
+		Height:    blk.Height,
+		BlockHash: blk.Hash,
+		TotalTxs:  blk.TotalTxs,
+		TotalOps:  blk.TotalOps,
+	}
+	require.False(t, hasBallotValidProposer(nr.Consensus(), *GenerateBallot(byAddress[missed], basis, tx, ballot.StateINIT, byAddress[missed], conf)))
+	require.True(t, hasBallotValidProposer(nr.Consensus(), *GenerateBallot(byAddress[next], basis, tx, ballot.StateINIT, byAddress[next], conf)))
+
+	confirm(0, byAddress[next], tx, missed)
+
+	blk = nr.Consensus().LatestBlock()
+	require.Equal(t, latest.Height+2, blk.Height)
+	require.Equal(t, uint64(0), blk.Round)
+	require.Equal(t, next, blk.Proposer)
+}
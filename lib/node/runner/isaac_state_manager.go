@@ -293,7 +293,13 @@ func (sm *ISAACStateManager) proposeOrWait(timer *time.Timer, round uint64) {
 	timer.Reset(time.Duration(1 * time.Hour))
 	sm.setBlockTimeBuffer()
 	height := sm.nr.consensus.LatestBlock().Height
-	proposer := sm.nr.Consensus().SelectProposer(height, round)
+	proposer, err := sm.nr.Consensus().SelectProposer(height, round)
+	if err != nil {
+		// without proposer, it waits for the timeout of round
+		log.Error("failed to select proposer", "height", height, "round", round, "error", err)
+		timer.Reset(sm.blockTimeBuffer + sm.Conf.TimeoutINIT)
+		return
+	}
 	log.Debug("selected proposer", "proposer", proposer)
 
 	if proposer == sm.nr.localNode.Address() {
@@ -79,7 +79,7 @@ func TestStateINITTimeoutNotProposer(t *testing.T) {
 	_, ok := nr.Consensus().ConnectionManager().(*TestConnectionManager)
 	require.True(t, ok)
 
-	proposer := nr.Consensus().SelectProposer(0, 0)
+	proposer, _ := nr.Consensus().SelectProposer(0, 0)
 
 	require.NotEqual(t, nr.localNode.Address(), proposer)
 
@@ -111,7 +111,7 @@ func TestStateSIGNTimeoutProposer(t *testing.T) {
 	recv := make(chan struct{})
 	nr, _, cm := createNodeRunnerForTesting(3, conf, recv)
 
-	proposer := nr.Consensus().SelectProposer(0, 0)
+	proposer, _ := nr.Consensus().SelectProposer(0, 0)
 
 	require.Equal(t, nr.localNode.Address(), proposer)
 
@@ -173,7 +173,7 @@ func TestStateSIGNTimeoutNotProposer(t *testing.T) {
 	cm, ok := nr.Consensus().ConnectionManager().(*TestConnectionManager)
 	require.True(t, ok)
 
-	proposer := nr.Consensus().SelectProposer(0, 0)
+	proposer, _ := nr.Consensus().SelectProposer(0, 0)
 
 	require.NotEqual(t, nr.localNode.Address(), proposer)
 
@@ -235,10 +235,10 @@ func TestStateACCEPTTimeoutProposerThenNotProposer(t *testing.T) {
 	cm, ok := nr.Consensus().ConnectionManager().(*TestConnectionManager)
 	require.True(t, ok)
 
-	proposer := nr.Consensus().SelectProposer(0, 0)
+	proposer, _ := nr.Consensus().SelectProposer(0, 0)
 	require.Equal(t, nr.localNode.Address(), proposer)
 
-	proposer = nr.Consensus().SelectProposer(0, 1)
+	proposer, _ = nr.Consensus().SelectProposer(0, 1)
 	require.NotEqual(t, nr.localNode.Address(), proposer)
 
 	nr.startStateManager()
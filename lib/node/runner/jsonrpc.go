@@ -67,13 +67,13 @@ type DBGetIteratorResult struct {
 }
 
 type jsonrpcDBApp struct {
-	st        *storage.LevelDBBackend
+	st        storage.Backend
 	snapshots *expireSnapshots
 }
 
 type expireSnapshots struct {
 	sync.RWMutex
-	st           *storage.LevelDBBackend
+	st           storage.Backend
 	interval     time.Duration
 	maxSnapshots uint64
 	ticker       *time.Ticker
@@ -81,7 +81,7 @@ type expireSnapshots struct {
 	expires      *syncmap.Map
 }
 
-func newExpireSnapshots(st *storage.LevelDBBackend, interval time.Duration, maxSnapshots uint64) *expireSnapshots {
+func newExpireSnapshots(st storage.Backend, interval time.Duration, maxSnapshots uint64) *expireSnapshots {
 	return &expireSnapshots{
 		st:           st,
 		interval:     interval,
@@ -101,7 +101,7 @@ func (j *expireSnapshots) len() (l int) {
 	return
 }
 
-func (j *expireSnapshots) newSnapshot() (string, *storage.LevelDBBackend, error) {
+func (j *expireSnapshots) newSnapshot() (string, storage.Backend, error) {
 	if j.len() >= int(j.maxSnapshots) {
 		return "", nil, errors.SnapshotLimitReached
 	}
@@ -122,7 +122,7 @@ func (j *expireSnapshots) newSnapshot() (string, *storage.LevelDBBackend, error)
 	return key, st, nil
 }
 
-func (j *expireSnapshots) snapshot(key string) (*storage.LevelDBBackend, bool) {
+func (j *expireSnapshots) snapshot(key string) (storage.Backend, bool) {
 	j.RLock()
 	defer j.RUnlock()
 
@@ -132,7 +132,7 @@ func (j *expireSnapshots) snapshot(key string) (*storage.LevelDBBackend, bool) {
 	}
 
 	j.updateExpire(key)
-	return s.(*storage.LevelDBBackend), true
+	return s.(storage.Backend), true
 }
 
 func (j *expireSnapshots) expire(key string) bool {
@@ -158,9 +158,7 @@ func (j *expireSnapshots) release_(key string) bool {
 	j.Lock()
 	defer j.Unlock()
 
-	if st.Core != nil {
-		st.Core.(*storage.Snapshot).Release()
-	}
+	st.Release()
 	j.snapshots.Delete(key)
 	j.expires.Delete(key)
 
@@ -206,7 +204,7 @@ func (j *expireSnapshots) stop() {
 	j.ticker.Stop()
 }
 
-func newJSONRPCDBApp(st *storage.LevelDBBackend) *jsonrpcDBApp {
+func newJSONRPCDBApp(st storage.Backend) *jsonrpcDBApp {
 	app := &jsonrpcDBApp{
 		st:        st,
 		snapshots: newExpireSnapshots(st, time.Minute*1, MaxSnapshots),
@@ -319,12 +317,12 @@ func (j *jsonrpcDBApp) GetIterator(r *http.Request, args *DBGetIteratorArgs, res
 
 type jsonrpcServer struct {
 	endpoint *common.Endpoint
-	st       *storage.LevelDBBackend
+	st       storage.Backend
 	server   *http.Server
 	app      *jsonrpcDBApp
 }
 
-func newJSONRPCServer(endpoint *common.Endpoint, st *storage.LevelDBBackend) *jsonrpcServer {
+func newJSONRPCServer(endpoint *common.Endpoint, st storage.Backend) *jsonrpcServer {
 	return &jsonrpcServer{
 		endpoint: endpoint,
 		st:       st,
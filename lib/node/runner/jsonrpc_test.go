@@ -20,7 +20,7 @@ import (
 type jsonrpcServerTestHelper struct {
 	server   *httptest.Server
 	endpoint *common.Endpoint
-	st       *storage.LevelDBBackend
+	st       storage.Backend
 	js       *jsonrpcServer
 	t        *testing.T
 }
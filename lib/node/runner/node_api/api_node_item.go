@@ -14,12 +14,15 @@ import (
 type NodeItemDataType string
 
 const (
-	NodeItemBlock            NodeItemDataType = "block"
-	NodeItemBlockHeader      NodeItemDataType = "block-header"
-	NodeItemBlockTransaction NodeItemDataType = "block-transaction"
-	NodeItemTransaction      NodeItemDataType = "transaction"
-	NodeItemBallot           NodeItemDataType = "ballot"
-	NodeItemError            NodeItemDataType = "error"
+	NodeItemBlock              NodeItemDataType = "block"
+	NodeItemBlockHeader        NodeItemDataType = "block-header"
+	NodeItemBlockTransaction   NodeItemDataType = "block-transaction"
+	NodeItemBlockCertificate   NodeItemDataType = "block-certificate"
+	NodeItemValidatorSetChange NodeItemDataType = "validator-set-change"
+	NodeItemTransaction        NodeItemDataType = "transaction"
+	NodeItemBallot             NodeItemDataType = "ballot"
+	NodeItemEvidence           NodeItemDataType = "evidence"
+	NodeItemError              NodeItemDataType = "error"
 )
 
 func UnmarshalNodeItemResponse(d []byte) (itemType NodeItemDataType, b interface{}, err error) {
@@ -51,6 +54,14 @@ func UnmarshalNodeItemResponse(d []byte) (itemType NodeItemDataType, b interface
 		var t block.BlockTransaction
 		err = unmarshal(&t)
 		b = t
+	case NodeItemBlockCertificate:
+		var t ballot.Certificate
+		err = unmarshal(&t)
+		b = t
+	case NodeItemValidatorSetChange:
+		var t block.ValidatorSetChangeProof
+		err = unmarshal(&t)
+		b = t
 	case NodeItemTransaction:
 		var t transaction.Transaction
 		err = unmarshal(&t)
@@ -59,6 +70,10 @@ func UnmarshalNodeItemResponse(d []byte) (itemType NodeItemDataType, b interface
 		var t ballot.Ballot
 		err = unmarshal(&t)
 		b = t
+	case NodeItemEvidence:
+		var t ballot.Evidence
+		err = unmarshal(&t)
+		b = t
 	case NodeItemError:
 		var t errors.Error
 		err = unmarshal(&t)
@@ -39,6 +39,7 @@ var DefaultHandleBaseBallotCheckerFuncs = []common.CheckerFunc{
 }
 
 var DefaultHandleINITBallotCheckerFuncs = []common.CheckerFunc{
+	BallotCheckEquivocation,
 	BallotAlreadyVoted,
 	BallotVote,
 	BallotIsSameProposer,
@@ -51,6 +52,7 @@ var DefaultHandleINITBallotCheckerFuncs = []common.CheckerFunc{
 }
 
 var DefaultHandleSIGNBallotCheckerFuncs = []common.CheckerFunc{
+	BallotCheckEquivocation,
 	BallotAlreadyVoted,
 	BallotVote,
 	BallotIsSameProposer,
@@ -62,6 +64,7 @@ var DefaultHandleSIGNBallotCheckerFuncs = []common.CheckerFunc{
 }
 
 var DefaultHandleACCEPTBallotCheckerFuncs = []common.CheckerFunc{
+	BallotCheckEquivocation,
 	BallotAlreadyVoted,
 	BallotVote,
 	BallotIsSameProposer,
@@ -87,7 +90,7 @@ type NodeRunner struct {
 	consensus         *consensus.ISAAC
 	TransactionPool   *transaction.Pool
 	connectionManager network.ConnectionManager
-	storage           *storage.LevelDBBackend
+	storage           storage.Backend
 	isaacStateManager *ISAACStateManager
 	ballotSendRecord  *consensus.BallotSendRecord
 
@@ -103,7 +106,14 @@ type NodeRunner struct {
 	Conf                  common.Config
 	nodeInfo              node.NodeInfo
 	savingBlockOperations *SavingBlockOperations
+	pruner                *Pruner
 	jsonrpcServer         *jsonrpcServer
+	rejectedTransactions  *block.RejectedTransactions
+
+	// initialValidators is the validators at startup; the validators are
+	// updated from them by `UpdateValidators`.
+	initialValidators map[string]*node.Validator
+	validatorsHeight  uint64
 }
 
 func NewNodeRunner(
@@ -111,7 +121,7 @@ func NewNodeRunner(
 	policy voting.ThresholdPolicy,
 	n network.Network,
 	c *consensus.ISAAC,
-	storage *storage.LevelDBBackend,
+	storage storage.Backend,
 	tp *transaction.Pool,
 	conf common.Config,
 ) (nr *NodeRunner, err error) {
@@ -126,14 +136,22 @@ func NewNodeRunner(
 		Conf:            conf,
 	}
 	nr.ballotSendRecord = consensus.NewBallotSendRecord(localNode.Alias())
+	nr.rejectedTransactions = block.NewRejectedTransactions(common.RejectedTransactionsLimit, common.RejectedTransactionsTTL)
 
 	nr.localNode.SetBooting()
 
 	nr.isaacStateManager = NewISAACStateManager(nr, conf)
 
+	nr.initialValidators = map[string]*node.Validator{}
+	for address, v := range nr.localNode.GetValidators() {
+		nr.initialValidators[address] = v
+	}
 	nr.policy.SetValidators(len(nr.localNode.GetValidators()))
 
 	nr.connectionManager = c.ConnectionManager()
+	if err = nr.UpdateValidators(); err != nil {
+		return
+	}
 	nr.savingBlockOperations = NewSavingBlockOperations(
 		nr.Storage(),
 		nr.Log(),
@@ -143,6 +161,9 @@ func NewNodeRunner(
 		nr.log.Error("failed to check BlockOperations", "error", err)
 		return
 	}
+	if conf.PruneBlocks > 0 {
+		nr.pruner = NewPruner(nr.Storage(), conf.PruneBlocks, nr.savingBlockOperations, nr.Log())
+	}
 
 	nr.SetHandleBaseBallotCheckerFuncs(DefaultHandleBaseBallotCheckerFuncs...)
 	nr.SetHandleINITBallotCheckerFuncs(DefaultHandleINITBallotCheckerFuncs...)
@@ -257,6 +278,7 @@ func (nr *NodeRunner) Ready() {
 		network.UrlPathPrefixNode,
 		nr.Conf,
 	)
+	nodeHandler.RejectedTransactions = nr.rejectedTransactions
 
 	nr.network.AddHandler(nodeHandler.HandlerURLPattern(NodeInfoHandlerPattern), nodeHandler.NodeInfoHandler)
 	nr.network.AddHandler(nodeHandler.HandlerURLPattern(ConnectHandlerPattern), nodeHandler.ConnectHandler).
@@ -279,6 +301,8 @@ func (nr *NodeRunner) Ready() {
 		MatcherFunc(common.PostAndJSONMatcher)
 	nr.network.AddHandler(nodeHandler.HandlerURLPattern(GetBallotPattern), nodeHandler.GetBallotHandler).
 		Methods("GET")
+	nr.network.AddHandler(nodeHandler.HandlerURLPattern(GetEvidencesPattern), nodeHandler.GetEvidencesHandler).
+		Methods("GET")
 	nr.network.AddHandler(network.UrlPathPrefixMetric, promhttp.Handler().ServeHTTP)
 
 	// api handlers
@@ -290,6 +314,8 @@ func (nr *NodeRunner) Ready() {
 		nr.nodeInfo,
 	)
 	apiHandler.GetLatestBlock = nr.Consensus().LatestBlock
+	apiHandler.GetBaseFee = nr.BaseFee
+	apiHandler.RejectedTransactions = nr.rejectedTransactions
 
 	nr.network.AddHandler(
 		apiHandler.HandlerURLPattern(api.GetAccountHandlerPattern),
@@ -311,6 +337,18 @@ func (nr *NodeRunner) Ready() {
 		apiHandler.HandlerURLPattern(api.GetAccountOperationsHandlerPattern),
 		listCache.WrapHandlerFunc(apiHandler.GetOperationsByAccountHandler),
 	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetAccountDataHandlerPattern),
+		listCache.WrapHandlerFunc(apiHandler.GetAccountDataHandler),
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetAccountDataByKeyHandlerPattern),
+		baCache.WrapHandlerFunc(apiHandler.GetAccountDataByKeyHandler),
+	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetAccountProofHandlerPattern),
+		baCache.WrapHandlerFunc(apiHandler.GetAccountProofHandler),
+	).Methods("GET", "OPTIONS")
 	nr.network.AddHandler(
 		apiHandler.HandlerURLPattern(api.GetFrozenAccountHandlerPattern),
 		apiHandler.GetFrozenAccountsHandler,
@@ -331,6 +369,10 @@ func (nr *NodeRunner) Ready() {
 		apiHandler.HandlerURLPattern(api.GetTransactionStatusHandlerPattern),
 		listCache.WrapHandlerFunc(apiHandler.GetTransactionStatusByHashHandler),
 	).Methods("GET", "OPTIONS")
+	nr.network.AddHandler(
+		apiHandler.HandlerURLPattern(api.GetTransactionProofHandlerPattern),
+		cache.WrapHandlerFunc(apiHandler.GetTransactionProofHandler),
+	).Methods("GET", "OPTIONS")
 	nr.network.AddHandler(
 		apiHandler.HandlerURLPattern(api.PostSubscribePattern),
 		listCache.WrapHandlerFunc(apiHandler.PostSubscribeHandler),
@@ -394,6 +436,9 @@ func (nr *NodeRunner) Start() (err error) {
 		go nr.InitRound()
 	}
 	go nr.savingBlockOperations.Start()
+	if nr.pruner != nil {
+		go nr.pruner.Start()
+	}
 
 	if nr.jsonrpcServer != nil {
 		go func() {
@@ -440,7 +485,7 @@ func (nr *NodeRunner) ConnectionManager() network.ConnectionManager {
 	return nr.connectionManager
 }
 
-func (nr *NodeRunner) Storage() *storage.LevelDBBackend {
+func (nr *NodeRunner) Storage() storage.Backend {
 	return nr.storage
 }
 
@@ -452,6 +497,18 @@ func (nr *NodeRunner) Log() logging.Logger {
 	return nr.log
 }
 
+// BaseFee returns the minimum fee of operation for the next block; see
+// `block.GetBaseFee`.
+func (nr *NodeRunner) BaseFee() common.Amount {
+	fee, err := block.GetBaseFee(nr.storage)
+	if err != nil {
+		nr.log.Error("failed to get base fee", "error", err)
+		return common.BaseFee
+	}
+
+	return fee
+}
+
 func (nr *NodeRunner) SavingBlockOperations() *SavingBlockOperations {
 	return nr.savingBlockOperations
 }
@@ -619,6 +676,9 @@ func (nr *NodeRunner) TransitISAACState(basis voting.Basis, ballotState ballot.S
 }
 
 func (nr *NodeRunner) NextHeight() {
+	if err := nr.UpdateValidators(); err != nil {
+		nr.log.Error("failed to update validators", "error", err)
+	}
 	nr.isaacStateManager.NextHeight()
 }
 
@@ -629,6 +689,8 @@ func (nr *NodeRunner) RemoveSendRecordsLowerThanOrEqualHeight(height uint64) {
 var NewBallotTransactionCheckerFuncs = []common.CheckerFunc{
 	IsNew,
 	BallotTransactionsSameSource,
+	BallotTransactionsMergedAccount,
+	BallotTransactionsTimeBounds,
 }
 
 func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
@@ -641,7 +703,9 @@ func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
 		TotalOps:  b.TotalOps,
 	}
 
-	// collect incoming transactions from `Pool`
+	// collect incoming transactions from `Pool`; the higher fee per operation
+	// comes first, so the ballot is filled by the higher fee transactions when
+	// the limits are reached.
 	availableTransactions := nr.TransactionPool.AvailableTransactions(nr.Conf.TxsLimit)
 	nr.log.Debug("new round proposed", "block-basis", basis)
 
@@ -650,6 +714,7 @@ func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
 		NodeRunner:            nr,
 		Conf:                  nr.Conf,
 		LocalNode:             nr.localNode,
+		Height:                basis.Height + 1,
 		Transactions:          availableTransactions,
 		CheckTransactionsOnly: true,
 		VotingHole:            voting.NOTYET,
@@ -699,7 +764,10 @@ func (nr *NodeRunner) proposeNewBallot(round uint64) (ballot.Ballot, error) {
 		}
 	}
 
-	proposerAddr := nr.consensus.SelectProposer(b.Height, round)
+	proposerAddr, err := nr.consensus.SelectProposer(b.Height, round)
+	if err != nil {
+		return ballot.Ballot{}, err
+	}
 	blt := ballot.NewBallot(nr.localNode.Address(), proposerAddr, basis, validTransactionHashes)
 	blt.SetVote(ballot.StateINIT, voting.YES)
 
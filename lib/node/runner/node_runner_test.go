@@ -14,6 +14,7 @@ import (
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/keypair"
 	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/network"
 	"boscoin.io/sebak/lib/node"
 	"boscoin.io/sebak/lib/transaction"
@@ -458,3 +459,104 @@ func TestProposedBallotByOpsInBallotLimit(t *testing.T) {
 		require.True(t, found)
 	}
 }
+
+// NodeRunner must propose new ballot with the higher fee transactions first.
+func TestProposedBallotByFeePriority(t *testing.T) {
+	config := common.NewTestConfig()
+	config.TxsLimit = 2
+	nr, _, _ := createNodeRunnerForTesting(1, config, nil)
+
+	_, tx0 := transaction.TestMakeTransaction(networkID, 1)
+	_, err := nr.TransactionPool.Add(tx0)
+	require.NoError(t, err)
+	_, tx1 := transaction.TestMakeTransaction(networkID, 1)
+	_, err = nr.TransactionPool.Add(tx1)
+	require.NoError(t, err)
+
+	kp2, tx2 := transaction.TestMakeTransaction(networkID, 1)
+	tx2.B.Fee = tx2.B.Fee.MustAdd(1)
+	tx2.Sign(kp2, networkID)
+	_, err = nr.TransactionPool.Add(tx2)
+	require.NoError(t, err)
+
+	blt, err := nr.proposeNewBallot(0)
+	require.NoError(t, err)
+	require.Equal(t, []string{tx2.GetHash(), tx0.GetHash()}, blt.Transactions())
+}
+
+// NodeRunner must propose new ballot from the full pool by the fee priority
+// and the transactions over the operations limit are left. The
+// `block.TransactionPool` of the evicted transactions are removed.
+func TestProposedBallotFromFullPool(t *testing.T) {
+	config := common.NewTestConfig()
+	config.TxPoolClientLimit = 3
+	config.OpsInBallotLimit = 3
+	nr, _, _ := createNodeRunnerForTesting(1, config, nil)
+
+	withFee := func(n int, extra common.Amount) transaction.Transaction {
+		kp, tx := transaction.TestMakeTransaction(networkID, n)
+		tx.B.Fee = tx.B.Fee.MustAdd(extra)
+		tx.Sign(kp, networkID)
+		return tx
+	}
+
+	push := func(tx transaction.Transaction) error {
+		checker := &MessageChecker{
+			Storage:         nr.Storage(),
+			TransactionPool: nr.TransactionPool,
+			Transaction:     tx,
+			Log:             nr.Log(),
+			Conf:            nr.Conf,
+
+			RejectedTransactions: nr.rejectedTransactions,
+		}
+		return PushIntoTransactionPoolFromClient(checker)
+	}
+
+	tx0 := withFee(1, 0)
+	tx1 := withFee(2, 200) // 100 more per operation
+	kp2, tx2 := transaction.TestMakeTransaction(networkID, 1)
+	tx2.B.Fee = tx2.B.Fee.MustAdd(10)
+	tx2.Sign(kp2, networkID)
+	for _, tx := range []transaction.Transaction{tx0, tx1, tx2} {
+		require.NoError(t, push(tx))
+	}
+
+	// the lowest fee can not get into the full pool
+	require.Equal(t, errors.TransactionPoolFull, push(withFee(1, 0)))
+
+	// the lowest fee transaction is evicted
+	tx3 := withFee(1, 50)
+	require.NoError(t, push(tx3))
+	require.Equal(t, 3, nr.TransactionPool.Len())
+	require.False(t, nr.TransactionPool.Has(tx0.GetHash()))
+
+	found, err := block.ExistsTransactionPool(nr.Storage(), tx0.GetHash())
+	require.NoError(t, err)
+	require.False(t, found)
+
+	rt, found := nr.rejectedTransactions.Get(tx0.GetHash())
+	require.True(t, found)
+	require.Equal(t, errors.TransactionEvictedFromPool.Code, rt.Code)
+
+	// the replaced transaction is also removed
+	replacement := tx2
+	replacement.B.Fee = tx2.B.Fee.MustAdd(10)
+	replacement.Sign(kp2, networkID)
+	require.NoError(t, push(replacement))
+	require.False(t, nr.TransactionPool.Has(tx2.GetHash()))
+
+	found, err = block.ExistsTransactionPool(nr.Storage(), tx2.GetHash())
+	require.NoError(t, err)
+	require.False(t, found)
+
+	rt, found = nr.rejectedTransactions.Get(tx2.GetHash())
+	require.True(t, found)
+	require.Equal(t, errors.TransactionReplaced.Code, rt.Code)
+
+	// tx1 and tx3 fill the operations limit, so the replacement is left
+	blt, err := nr.proposeNewBallot(0)
+	require.NoError(t, err)
+	require.Equal(t, []string{tx1.GetHash(), tx3.GetHash()}, blt.Transactions())
+	require.True(t, nr.TransactionPool.Has(replacement.GetHash()))
+}
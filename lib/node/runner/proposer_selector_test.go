@@ -1,24 +1,39 @@
 package runner
 
 import (
+	"sort"
 	"testing"
 
-	"boscoin.io/sebak/lib/common"
 	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/voting"
 )
 
+func mustSelectProposer(t *testing.T, selectProposer func(uint64, uint64) (string, error), blockHeight, round uint64) string {
+	proposer, err := selectProposer(blockHeight, round)
+	require.NoError(t, err)
+	return proposer
+}
+
 // In TestProposerSelector test, the proposer is always the node itself because of SelfProposerCalculator.
 func TestProposerSelector(t *testing.T) {
 	nodeRunners := createTestNodeRunner(1, common.NewTestConfig())
 
 	nodeRunner := nodeRunners[0]
 
-	require.Equal(t, nodeRunner.localNode.Address(), nodeRunner.Consensus().SelectProposer(1, 0))
-	require.Equal(t, nodeRunner.localNode.Address(), nodeRunner.Consensus().SelectProposer(2, 0))
-	require.Equal(t, nodeRunner.localNode.Address(), nodeRunner.Consensus().SelectProposer(2, 1))
+	require.Equal(t, nodeRunner.localNode.Address(), mustSelectProposer(t, nodeRunner.Consensus().SelectProposer, 1, 0))
+	require.Equal(t, nodeRunner.localNode.Address(), mustSelectProposer(t, nodeRunner.Consensus().SelectProposer, 2, 0))
+	require.Equal(t, nodeRunner.localNode.Address(), mustSelectProposer(t, nodeRunner.Consensus().SelectProposer, 2, 1))
 }
 
-// All 3 nodes have the same proposer at each round
+// All 3 nodes have the same proposers at each round
 func TestNodesHaveSameProposers(t *testing.T) {
 	numberOfNodes := 3
 
@@ -37,9 +52,9 @@ func TestNodesHaveSameProposers(t *testing.T) {
 
 	for i := uint64(0); i < maximumBlockHeight; i++ {
 		for j := uint64(0); j < maximumRoundNumber; j++ {
-			proposers0[i*maximumRoundNumber] = nr0.Consensus().SelectProposer(i, j)
-			proposers1[i*maximumRoundNumber] = nr1.Consensus().SelectProposer(i, j)
-			proposers2[i*maximumRoundNumber] = nr2.Consensus().SelectProposer(i, j)
+			proposers0[i*maximumRoundNumber] = mustSelectProposer(t, nr0.Consensus().SelectProposer, i, j)
+			proposers1[i*maximumRoundNumber] = mustSelectProposer(t, nr1.Consensus().SelectProposer, i, j)
+			proposers2[i*maximumRoundNumber] = mustSelectProposer(t, nr2.Consensus().SelectProposer, i, j)
 		}
 	}
 
@@ -47,3 +62,201 @@ func TestNodesHaveSameProposers(t *testing.T) {
 	require.Equal(t, proposers0, proposers2)
 	require.Equal(t, proposers1, proposers2)
 }
+
+func saveTestBlockWithRound(t *testing.T, st storage.Backend, prev block.Block, round uint64, proposer string) block.Block {
+	basis := voting.Basis{
+		Round:     round,
+		Height:    prev.Height + 1,
+		BlockHash: prev.Hash,
+		TotalTxs:  prev.TotalTxs,
+		TotalOps:  prev.TotalOps,
+	}
+	blk := block.NewBlock(proposer, basis, "", []string{}, common.NowISO8601())
+	require.NoError(t, blk.Save(st))
+	return *blk
+}
+
+// In TestReputationSelector test, the validators which missed their proposals
+// in the recent blocks are selected after the others.
+func TestReputationSelector(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, _, cm := createNodeRunnerForTesting(4, conf, nil)
+
+	validators := sort.StringSlice(cm.AllValidators())
+	validators.Sort()
+	n := uint64(len(validators))
+
+	var window uint64 = 2
+	selector := consensus.NewReputationSelector(nr.Storage(), validators, window)
+	nr.Consensus().SetProposerSelector(selector)
+
+	// without missed proposals, it is same with `SequentialSelector`
+	latest := nr.Consensus().LatestBlock()
+	require.Equal(t, uint64(1), latest.Height)
+	for round := uint64(0); round < n; round++ {
+		require.Equal(t, validators[(latest.Height+round)%n], mustSelectProposer(t, nr.Consensus().SelectProposer, latest.Height, round))
+	}
+
+	// the block is confirmed in round 2, so the proposers of round 0 and 1
+	// missed their proposals; the missed validators are moved behind, but
+	// still can be selected in the later rounds.
+	blk := saveTestBlockWithRound(t, nr.Storage(), latest, 2, validators[(latest.Height+2)%n])
+	h := blk.Height
+	expected := []string{
+		validators[(h+1)%n],
+		validators[(h+2)%n],
+		validators[(h+0)%n],
+		validators[(h+3)%n],
+	}
+	order, err := selector.Order(h)
+	require.NoError(t, err)
+	require.Equal(t, expected, order)
+	for round, proposer := range expected {
+		require.Equal(t, proposer, mustSelectProposer(t, nr.Consensus().SelectProposer, h, uint64(round)))
+	}
+
+	// the block is confirmed in round 1; the missed validator is the proposer
+	// of round 0 in the order of `ReputationSelector`, not of
+	// `SequentialSelector`.
+	blk = saveTestBlockWithRound(t, nr.Storage(), blk, 1, expected[1])
+	order, err = selector.Order(blk.Height)
+	require.NoError(t, err)
+	require.Equal(t, []string{validators[(h+2)%n], validators[(h+1)%n], validators[(h+3)%n], validators[(h+0)%n]}, order)
+
+	// every node selects the same proposer from the same blocks
+	other := consensus.NewReputationSelector(nr.Storage(), validators, window)
+	for height := latest.Height; height <= blk.Height; height++ {
+		for round := uint64(0); round < n; round++ {
+			require.Equal(t, mustSelectProposer(t, selector.Select, height, round), mustSelectProposer(t, other.Select, height, round))
+		}
+	}
+
+	// the missed proposals out of window are forgotten
+	blk = saveTestBlockWithRound(t, nr.Storage(), blk, 0, validators[blk.Height%n])
+	blk = saveTestBlockWithRound(t, nr.Storage(), blk, 0, validators[blk.Height%n])
+	for round := uint64(0); round < n; round++ {
+		require.Equal(t, validators[(blk.Height+round)%n], mustSelectProposer(t, nr.Consensus().SelectProposer, blk.Height, round))
+	}
+
+	// the blocks before the window are not needed, like the node from
+	// snapshot
+	expected, err = selector.Order(blk.Height)
+	require.NoError(t, err)
+	for height := latest.Height; height+window <= blk.Height; height++ {
+		require.NoError(t, nr.Storage().Remove(block.GetBlockKeyPrefixHeight(height)))
+	}
+	order, err = consensus.NewReputationSelector(nr.Storage(), validators, window).Order(blk.Height)
+	require.NoError(t, err)
+	require.Equal(t, expected, order)
+
+	// without the block, the proposer is not selected
+	_, err = selector.Select(blk.Height+1, 0)
+	require.Equal(t, errors.ProposerNotSelected.Code, err.(*errors.Error).Code)
+}
+
+// In TestReputationSelectorValidatorSet test, the order is made from the
+// validators at each height, not from the current validators.
+func TestReputationSelectorValidatorSet(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, _, cm := createNodeRunnerForTesting(4, conf, nil)
+
+	validators := sort.StringSlice(cm.AllValidators())
+	validators.Sort()
+	n := uint64(len(validators))
+
+	var window uint64 = 3
+	selector := consensus.NewReputationSelector(nr.Storage(), validators, window)
+
+	latest := nr.Consensus().LatestBlock()
+	blk := saveTestBlockWithRound(t, nr.Storage(), latest, 1, validators[(latest.Height+1)%n])
+	missed := validators[latest.Height%n]
+
+	before, err := selector.Order(blk.Height)
+	require.NoError(t, err)
+	require.Equal(t, missed, before[n-1])
+
+	// the other validator is removed from the next block
+	var removed string
+	for _, v := range validators {
+		if v != missed {
+			removed = v
+			break
+		}
+	}
+	require.NoError(t, block.NewValidatorSetChange("findme", blk.Height+2, nil, nil, []string{removed}).Save(nr.Storage()))
+
+	// the order of past height is not changed by the validator set change
+	other := consensus.NewReputationSelector(nr.Storage(), validators, window)
+	order, err := other.Order(blk.Height)
+	require.NoError(t, err)
+	require.Equal(t, before, order)
+
+	blk = saveTestBlockWithRound(t, nr.Storage(), blk, 0, before[0])
+	order, err = other.Order(blk.Height)
+	require.NoError(t, err)
+	require.Equal(t, int(n-1), len(order))
+	require.NotContains(t, order, removed)
+	require.Equal(t, missed, order[len(order)-1])
+}
+
+// In TestRandomSelector test, the proposers are selected by the seed from the
+// latest block and every validator is selected once in the rounds.
+func TestRandomSelector(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, cm := createNodeRunnerForTesting(4, conf, nil)
+
+	validators := sort.StringSlice(cm.AllValidators())
+	validators.Sort()
+	n := uint64(len(validators))
+
+	selector := consensus.NewRandomSelector(nr.Storage(), validators)
+	nr.Consensus().SetProposerSelector(selector)
+
+	latest := nr.Consensus().LatestBlock()
+	seed, err := selector.Seed(latest.Height)
+	require.NoError(t, err)
+	require.NotEmpty(t, seed)
+
+	var selected []string
+	for round := uint64(0); round < n; round++ {
+		selected = append(selected, mustSelectProposer(t, nr.Consensus().SelectProposer, latest.Height, round))
+	}
+	sort.Strings(selected)
+	require.Equal(t, []string(validators), selected)
+
+	// every node selects the same proposer from the same block
+	other := consensus.NewRandomSelector(nr.Storage(), validators)
+	for round := uint64(0); round < n*2; round++ {
+		require.Equal(t, mustSelectProposer(t, selector.Select, latest.Height, round), mustSelectProposer(t, other.Select, latest.Height, round))
+	}
+
+	// the seed is changed by the block
+	blk := saveTestBlockWithRound(t, nr.Storage(), latest, 0, nodes[0].Address())
+	nextSeed, err := selector.Seed(blk.Height)
+	require.NoError(t, err)
+	require.NotEqual(t, seed, nextSeed)
+
+	// the ballot of the other proposer is not valid
+	basis := voting.Basis{
+		Height:    blk.Height,
+		BlockHash: blk.Hash,
+		TotalTxs:  blk.TotalTxs,
+		TotalOps:  blk.TotalOps,
+	}
+	_, tx := transaction.TestMakeTransaction(networkID, 1)
+	proposer := mustSelectProposer(t, selector.Select, blk.Height, 0)
+	for _, nd := range nodes {
+		b := GenerateBallot(nd, basis, tx, ballot.StateINIT, nd, conf)
+		require.Equal(t, nd.Address() == proposer, hasBallotValidProposer(nr.Consensus(), *b))
+	}
+
+	// the removed validator is not selected from the next block
+	require.NoError(t, block.NewValidatorSetChange("findme", blk.Height+1, nil, nil, []string{proposer}).Save(nr.Storage()))
+	for round := uint64(0); round < n*2; round++ {
+		require.NotEqual(t, proposer, mustSelectProposer(t, selector.Select, blk.Height, round))
+	}
+
+	// without the block, the proposer is not selected
+	_, err = selector.Select(blk.Height+1, 0)
+	require.Equal(t, errors.ProposerNotSelected.Code, err.(*errors.Error).Code)
+}
@@ -0,0 +1,118 @@
+package runner
+
+import (
+	"time"
+
+	logging "github.com/inconshreveable/log15"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/storage"
+)
+
+// Pruner removes the transactions and operations of the old blocks, so only
+// the latest `keep` blocks are served in full; see `block.PruneBlock`.
+type Pruner struct {
+	st  storage.Backend
+	log logging.Logger
+	sb  *SavingBlockOperations
+
+	keep uint64
+}
+
+func NewPruner(st storage.Backend, keep uint64, sb *SavingBlockOperations, logger logging.Logger) *Pruner {
+	if logger == nil {
+		logger = log
+	}
+
+	return &Pruner{
+		st:   st,
+		log:  logger.New(logging.Ctx{"m": "Pruner"}),
+		sb:   sb,
+		keep: keep,
+	}
+}
+
+// target returns the height of block, which can be pruned until; the blocks,
+// whose `BlockOperation`s are not yet checked by `SavingBlockOperations` are
+// not pruned.
+func (p *Pruner) target() uint64 {
+	latest := block.GetLatestBlock(p.st).Height
+	if latest <= p.keep {
+		return 0
+	}
+
+	target := latest - p.keep
+	if p.sb != nil {
+		if checked, err := p.sb.loadCheckedBlockHeight(); err != nil {
+			return 0
+		} else if checked < target {
+			target = checked
+		}
+	}
+
+	return target
+}
+
+// Prune prunes the blocks from the last pruned block to the target. The
+// genesis block is never pruned.
+func (p *Pruner) Prune() (err error) {
+	var pruned uint64
+	if pruned, err = block.GetPrunedBlockHeight(p.st); err != nil {
+		return
+	}
+	if pruned < common.GenesisBlockHeight {
+		pruned = common.GenesisBlockHeight
+	}
+
+	target := p.target()
+	for height := pruned + 1; height <= target; height++ {
+		if err = p.pruneBlock(height); err != nil {
+			p.log.Error("failed to prune block", "height", height, "error", err)
+			return
+		}
+	}
+
+	return
+}
+
+func (p *Pruner) pruneBlock(height uint64) (err error) {
+	var blk block.Block
+	if blk, err = block.GetBlockByHeight(p.st, height); err != nil {
+		return
+	}
+
+	var st storage.Backend
+	if st, err = p.st.OpenBatch(); err != nil {
+		return
+	}
+
+	if err = block.PruneBlock(st, blk); err != nil {
+		st.Discard()
+		return
+	}
+	if err = block.SavePrunedBlockHeight(st, height); err != nil {
+		st.Discard()
+		return
+	}
+	if err = st.Commit(); err != nil {
+		st.Discard()
+		return
+	}
+
+	p.log.Debug("block pruned", "height", height, "hash", blk.Hash)
+
+	return
+}
+
+func (p *Pruner) Start() {
+	p.log.Debug("start pruning", "keep", p.keep)
+
+	for {
+		if err := p.Prune(); err != nil {
+			p.log.Error("failed to prune", "error", err)
+		}
+
+		time.Sleep(5 * time.Second)
+	}
+}
@@ -0,0 +1,85 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+)
+
+func TestPruner(t *testing.T) {
+	p := &TestSavingBlockOperationHelper{}
+	p.Prepare()
+	defer p.Done()
+
+	var blocks []block.Block
+	prevBlock := block.GetGenesis(p.st)
+	for i := 0; i < 5; i++ {
+		blk := p.makeBlock(prevBlock, 3)
+		blocks = append(blocks, blk)
+		prevBlock = blk
+	}
+
+	sb := NewSavingBlockOperations(p.st, nil)
+	pruner := NewPruner(p.st, 2, sb, nil)
+
+	{ // before `SavingBlockOperations` checks, nothing is pruned
+		require.NoError(t, pruner.Prune())
+
+		height, err := block.GetPrunedBlockHeight(p.st)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), height)
+		require.Equal(t, common.GenesisBlockHeight, block.GetLowestFullBlockHeight(p.st))
+	}
+
+	require.NoError(t, sb.Check())
+	require.NoError(t, pruner.Prune())
+
+	latest := block.GetLatestBlock(p.st)
+	height, err := block.GetPrunedBlockHeight(p.st)
+	require.NoError(t, err)
+	require.Equal(t, latest.Height-2, height)
+	require.Equal(t, latest.Height-1, block.GetLowestFullBlockHeight(p.st))
+
+	{ // genesis block is not pruned
+		genesis := block.GetGenesis(p.st)
+		require.NotEmpty(t, genesis.Transactions)
+		for _, txHash := range genesis.Transactions {
+			exists, err := block.ExistsBlockTransaction(p.st, txHash)
+			require.NoError(t, err)
+			require.True(t, exists)
+		}
+	}
+
+	for _, blk := range blocks {
+		exists, err := block.ExistsBlock(p.st, blk.Hash)
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		for _, txHash := range append([]string{blk.ProposerTransaction}, blk.Transactions...) {
+			exists, err := block.ExistsBlockTransaction(p.st, txHash)
+			require.NoError(t, err)
+
+			pruned, err := block.IsPrunedBlockTransaction(p.st, txHash)
+			require.NoError(t, err)
+
+			if blk.Height <= height {
+				require.False(t, exists)
+				require.True(t, pruned)
+			} else {
+				require.True(t, exists)
+				require.False(t, pruned)
+			}
+		}
+	}
+
+	{ // pruning again does nothing
+		require.NoError(t, pruner.Prune())
+
+		newHeight, err := block.GetPrunedBlockHeight(p.st)
+		require.NoError(t, err)
+		require.Equal(t, height, newHeight)
+	}
+}
@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestBlockStateRoot(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	conf := common.NewTestConfig()
+	nr, nodes, _ := createNodeRunnerForTesting(5, conf, nil)
+
+	tx, _ := GetTransaction()
+	nr.TransactionPool.Add(tx)
+
+	round := uint64(0)
+	_, err := nr.proposeNewBallot(round)
+	require.NoError(t, err)
+
+	b := nr.Consensus().LatestBlock()
+	require.Empty(t, b.StateRoot) // genesis block does not have the state root
+
+	basis := voting.Basis{
+		Round:     round,
+		Height:    b.Height,
+		BlockHash: b.Hash,
+		TotalTxs:  b.TotalTxs,
+	}
+	proposer := nr.localNode
+
+	for _, n := range nodes[1:] {
+		require.NoError(t, ReceiveBallot(nr, GenerateBallot(proposer, basis, tx, ballot.StateSIGN, n, conf)))
+	}
+	for _, n := range nodes[:4] {
+		require.NoError(t, ReceiveBallot(nr, GenerateBallot(proposer, basis, tx, ballot.StateACCEPT, n, conf)))
+	}
+
+	blk := nr.Consensus().LatestBlock()
+	require.Equal(t, b.Height+1, blk.Height)
+	require.Equal(t, block.BlockVersionV2, blk.Version)
+	require.NotEmpty(t, blk.StateRoot)
+
+	// the accounts in the state trie are same with the stored accounts
+	st := nr.Storage()
+	stateDB := statedb.New(statedb.DecodeRoot(blk.StateRoot), trie.NewEthDatabase(st))
+	for _, address := range []string{tx.B.Source, tx.B.Operations[0].B.(operation.Targetable).TargetAddress()} {
+		require.True(t, stateDB.ExistAccount(address))
+
+		ba, err := block.GetBlockAccount(st, address)
+		require.NoError(t, err)
+		require.Equal(t, ba.Balance, stateDB.GetBalance(address))
+		require.Equal(t, ba.SequenceID, stateDB.GetCheckPoint(address))
+	}
+
+	// same accounts make same root
+	root, err := statedb.CommitAccounts(st, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, blk.StateRoot, root)
+}
+
+// In TestGetStateChanges test, the state root covers the other state of
+// accounts, which is changed by the operations, with the accounts.
+func TestGetStateChanges(t *testing.T) {
+	kp := keypair.Random()
+	target := keypair.Random()
+
+	ops := []operation.Body{
+		operation.NewManageData("key", "value"),
+		operation.NewUnfreezeRequest(),
+		operation.NewAccountMerge(target.Address()),
+		operation.NewUpdateValidators(10, []string{target.Address()}, []string{"https://localhost:12345"}, nil),
+	}
+	var operations []operation.Operation
+	for _, body := range ops {
+		op, err := operation.NewOperation(body)
+		require.NoError(t, err)
+		operations = append(operations, op)
+	}
+	tx, err := transaction.NewTransaction(kp.Address(), 0, operations...)
+	require.NoError(t, err)
+
+	changes := getStateChanges([]*transaction.Transaction{&tx}, ballot.ProposerTransaction{})
+	require.Contains(t, changes.Accounts, kp.Address())
+	require.Contains(t, changes.Accounts, target.Address())
+	require.Equal(t, map[string][]string{kp.Address(): {"key"}}, changes.AccountData)
+	require.Equal(
+		t,
+		[]string{block.NewBlockOperationKey(common.MustMakeObjectHashString(operations[1]), tx.GetHash())},
+		changes.UnfreezingRequests,
+	)
+	require.Equal(t, []string{kp.Address()}, changes.MergedAccounts)
+	require.Equal(
+		t,
+		[]string{block.GetValidatorSetChangeKey(10, common.MustMakeObjectHashString(operations[3]))},
+		changes.ValidatorSetChanges,
+	)
+}
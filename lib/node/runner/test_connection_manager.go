@@ -58,8 +58,8 @@ type FixedSelector struct {
 	address string
 }
 
-func (s FixedSelector) Select(_ uint64, _ uint64) string {
-	return s.address
+func (s FixedSelector) Select(_ uint64, _ uint64) (string, error) {
+	return s.address, nil
 }
 
 type OtherSelector struct {
@@ -67,10 +67,10 @@ type OtherSelector struct {
 	localNode *node.LocalNode
 }
 
-func (s OtherSelector) Select(_ uint64, _ uint64) string {
+func (s OtherSelector) Select(_ uint64, _ uint64) (string, error) {
 	for _, v := range s.cm.AllValidators() {
 		if v != s.localNode.Address() {
-			return v
+			return v, nil
 		}
 	}
 	panic("There is no the other validators")
@@ -81,13 +81,13 @@ type SelfThenOtherSelector struct {
 	localNode *node.LocalNode
 }
 
-func (s SelfThenOtherSelector) Select(blockHeight uint64, round uint64) string {
+func (s SelfThenOtherSelector) Select(blockHeight uint64, round uint64) (string, error) {
 	if blockHeight < 2 && round == 0 {
-		return s.localNode.Address()
+		return s.localNode.Address(), nil
 	} else {
 		for _, v := range s.cm.AllValidators() {
 			if v != s.localNode.Address() {
-				return v
+				return v, nil
 			}
 		}
 	}
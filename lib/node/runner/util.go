@@ -13,7 +13,7 @@ import (
 	"boscoin.io/sebak/lib/version"
 )
 
-func GetGenesisTransaction(st *storage.LevelDBBackend) (bt block.BlockTransaction, err error) {
+func GetGenesisTransaction(st storage.Backend) (bt block.BlockTransaction, err error) {
 	var bk block.Block
 	if bk, err = block.GetBlockByHeight(st, common.GenesisBlockHeight); err != nil {
 		return
@@ -36,7 +36,7 @@ func GetGenesisTransaction(st *storage.LevelDBBackend) (bt block.BlockTransactio
 	return
 }
 
-func getGenesisAccount(st *storage.LevelDBBackend, operationIndex int) (account *block.BlockAccount, err error) {
+func getGenesisAccount(st storage.Backend, operationIndex int) (account *block.BlockAccount, err error) {
 	var bt block.BlockTransaction
 	if bt, err = GetGenesisTransaction(st); err != nil {
 		return
@@ -60,15 +60,15 @@ func getGenesisAccount(st *storage.LevelDBBackend, operationIndex int) (account
 	return
 }
 
-func GetGenesisAccount(st *storage.LevelDBBackend) (account *block.BlockAccount, err error) {
+func GetGenesisAccount(st storage.Backend) (account *block.BlockAccount, err error) {
 	return getGenesisAccount(st, 0)
 }
 
-func GetCommonAccount(st *storage.LevelDBBackend) (account *block.BlockAccount, err error) {
+func GetCommonAccount(st storage.Backend) (account *block.BlockAccount, err error) {
 	return getGenesisAccount(st, 1)
 }
 
-func GetGenesisBalance(st *storage.LevelDBBackend) (balance common.Amount, err error) {
+func GetGenesisBalance(st storage.Backend) (balance common.Amount, err error) {
 	var bt block.BlockTransaction
 	if bt, err = GetGenesisTransaction(st); err != nil {
 		return
@@ -131,10 +131,15 @@ func NewNodeInfo(nr *NodeRunner) node.NodeInfo {
 		OperationsLimit:           nr.Conf.OpsLimit,
 		TransactionsLimit:         nr.Conf.TxsLimit,
 		OperationsInBallotLimit:   nr.Conf.OpsInBallotLimit,
+		BaseFeeBlocks:             common.BaseFeeBlocks,
+		BaseFeeMaxMultiplier:      common.BaseFeeMaxMultiplier,
+		ProposerSelector:          common.ProposerSelector,
 		GenesisBlockConfirmedTime: common.GenesisBlockConfirmedTime,
 		InflationRatio:            common.InflationRatioString,
 		UnfreezingPeriod:          common.UnfreezingPeriod,
 		BlockHeightEndOfInflation: common.BlockHeightEndOfInflation,
+		BlockVersionV1Height:      common.BlockVersionV1Height,
+		BlockVersionV2Height:      common.BlockVersionV2Height,
 	}
 
 	return node.NodeInfo{
@@ -146,12 +151,12 @@ func NewNodeInfo(nr *NodeRunner) node.NodeInfo {
 type TransactionCache struct {
 	sync.RWMutex
 
-	st    *storage.LevelDBBackend
+	st    storage.Backend
 	pool  *transaction.Pool
 	cache map[string]transaction.Transaction
 }
 
-func NewTransactionCache(st *storage.LevelDBBackend, pool *transaction.Pool) *TransactionCache {
+func NewTransactionCache(st storage.Backend, pool *transaction.Pool) *TransactionCache {
 	return &TransactionCache{
 		st:    st,
 		pool:  pool,
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node"
+)
+
+// UpdateValidators applies the `ValidatorSetChange`s, which take effect until
+// the next block, to the validators of local node, `voting.ThresholdPolicy`
+// and `network.ConnectionManager`. The validators are calculated from the
+// validators at startup, so it is safe to be called repeatedly.
+func (nr *NodeRunner) UpdateValidators() (err error) {
+	nr.Lock()
+	defer nr.Unlock()
+
+	latest := block.GetLatestBlock(nr.storage)
+	if nr.validatorsHeight > 0 && nr.validatorsHeight == latest.Height {
+		return
+	}
+
+	validators := map[string]*node.Validator{}
+	endpoints := block.GetValidatorEndpointsAt(nr.storage, latest.Height+1)
+	for _, address := range block.GetValidatorsAt(nr.storage, nr.initialValidatorAddresses(), latest.Height+1) {
+		v, found := nr.initialValidators[address]
+		if !found {
+			var endpoint *common.Endpoint
+			if endpoint, err = common.ParseEndpoint(endpoints[address]); err != nil {
+				nr.log.Error("invalid endpoint of added validator", "validator", address, "error", err)
+				return
+			}
+			if v, err = node.NewValidator(address, endpoint, ""); err != nil {
+				return
+			}
+		}
+		validators[address] = v
+	}
+
+	if len(validators) < 1 {
+		nr.log.Error("validators are empty after validator set changes", "height", latest.Height)
+		return errors.InvalidValidatorSetChange
+	}
+
+	nr.validatorsHeight = latest.Height
+
+	var added []*node.Validator
+	var removed []string
+	current := nr.localNode.GetValidators()
+	for address, v := range validators {
+		if _, found := current[address]; !found {
+			added = append(added, v)
+		}
+	}
+	for address := range current {
+		if _, found := validators[address]; !found {
+			removed = append(removed, address)
+		}
+	}
+
+	if len(added) < 1 && len(removed) < 1 {
+		return
+	}
+
+	nr.localNode.AddValidators(added...)
+	nr.localNode.RemoveValidators(removed...)
+	nr.policy.SetValidators(len(nr.localNode.GetValidators()))
+	if nr.connectionManager != nil {
+		nr.connectionManager.UpdateValidators(added, removed)
+	}
+
+	nr.log.Debug(
+		"validators updated",
+		"height", latest.Height,
+		"added", added,
+		"removed", removed,
+		"validators", len(validators),
+	)
+
+	return
+}
+
+// initialValidatorAddresses returns the addresses of the validators at
+// startup.
+func (nr *NodeRunner) initialValidatorAddresses() (addresses []string) {
+	for address := range nr.initialValidators {
+		addresses = append(addresses, address)
+	}
+
+	return
+}
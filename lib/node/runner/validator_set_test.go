@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/network"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
+)
+
+func TestValidateOpUpdateValidators(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, _, _ := createNodeRunnerForTesting(3, conf, nil)
+	st := nr.Storage()
+
+	kpCongress := keypair.Random()
+	conf.CongressAccountAddress = kpCongress.Address()
+
+	congress := block.NewBlockAccount(kpCongress.Address(), common.Amount(common.BaseReserve))
+	congress.MustSave(st)
+	other := block.NewBlockAccount(keypair.Random().Address(), common.Amount(common.BaseReserve))
+	other.MustSave(st)
+
+	latest := block.GetLatestBlock(st)
+
+	opb := operation.NewUpdateValidators(latest.Height+2, []string{keypair.Random().Address()}, []string{"https://localhost:12345"}, nil)
+	op, _ := operation.NewOperation(opb)
+	require.NoError(t, ValidateOp(st, conf, congress, op))
+
+	// only congress account can update validators
+	require.Equal(t, errors.CongressAddressMisMatched, ValidateOp(st, conf, other, op))
+
+	// the height must be after the next block
+	opb.Height = latest.Height + 1
+	op, _ = operation.NewOperation(opb)
+	err := ValidateOp(st, conf, congress, op)
+	require.Equal(t, errors.InvalidValidatorSetChange.Code, err.(*errors.Error).Code)
+}
+
+func TestUpdateValidators(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, cm := createNodeRunnerForTesting(3, conf, nil)
+	st := nr.Storage()
+
+	latest := block.GetLatestBlock(st)
+
+	// the added validator is also in the memory network
+	_, newNode := network.CreateMemoryNetwork(nr.Network().(*network.MemoryNetwork))
+	kpNew := newNode.Keypair()
+	opb := operation.NewUpdateValidators(
+		latest.Height+2,
+		[]string{kpNew.Address()},
+		[]string{newNode.Endpoint().String()},
+		[]string{nodes[2].Address()},
+	)
+	op, _ := operation.NewOperation(opb)
+	require.NoError(t, finishOperation(st, conf.CongressAccountAddress, op, log))
+
+	// the change is not stored twice
+	require.NoError(t, finishOperation(st, conf.CongressAccountAddress, op, log))
+	require.Equal(t, 1, len(block.GetValidatorSetChangesUntil(st, opb.Height)))
+
+	// the change takes effect for the block of `opb.Height`
+	require.NoError(t, nr.UpdateValidators())
+	require.Equal(t, 3, len(nr.Node().GetValidators()))
+	require.True(t, nr.Node().HasValidators(nodes[2].Address()))
+	require.False(t, nr.Node().HasValidators(kpNew.Address()))
+
+	blk := block.NewBlock(
+		nodes[0].Address(),
+		voting.Basis{
+			Height:    latest.Height + 1,
+			BlockHash: latest.Hash,
+			TotalTxs:  latest.TotalTxs,
+			TotalOps:  latest.TotalOps,
+		},
+		"",
+		[]string{},
+		common.NowISO8601(),
+	)
+	require.NoError(t, blk.Save(st))
+
+	require.NoError(t, nr.UpdateValidators())
+	require.Equal(t, 3, len(nr.Node().GetValidators()))
+	require.False(t, nr.Node().HasValidators(nodes[2].Address()))
+	require.True(t, nr.Node().HasValidators(kpNew.Address()))
+	require.Equal(t, 3, nr.Policy().Validators())
+	require.Equal(t, newNode.Endpoint().String(), nr.Node().Validator(kpNew.Address()).Endpoint().String())
+
+	expected := []string{nodes[0].Address(), nodes[1].Address(), kpNew.Address()}
+	sort.Strings(expected)
+	validators := cm.AllValidators()
+	sort.Strings(validators)
+	require.Equal(t, expected, validators)
+
+	// the validators are updated only once in the same height
+	require.NoError(t, nr.UpdateValidators())
+	require.Equal(t, 3, len(nr.Node().GetValidators()))
+}
+
+// TestUpdateValidatorsConnect checks the added validator is connected by the
+// endpoint in the validator set change.
+func TestUpdateValidatorsConnect(t *testing.T) {
+	conf := common.NewTestConfig()
+	nr, nodes, cm := createNodeRunnerForTesting(3, conf, nil)
+	st := nr.Storage()
+
+	latest := block.GetLatestBlock(st)
+
+	_, newNode := network.CreateMemoryNetwork(nr.Network().(*network.MemoryNetwork))
+	opb := operation.NewUpdateValidators(
+		latest.Height+2,
+		[]string{newNode.Address()},
+		[]string{newNode.Endpoint().String()},
+		nil,
+	)
+	op, _ := operation.NewOperation(opb)
+	require.NoError(t, finishOperation(st, conf.CongressAccountAddress, op, log))
+
+	blk := block.NewBlock(
+		nodes[0].Address(),
+		voting.Basis{
+			Height:    latest.Height + 1,
+			BlockHash: latest.Hash,
+			TotalTxs:  latest.TotalTxs,
+			TotalOps:  latest.TotalOps,
+		},
+		"",
+		[]string{},
+		common.NowISO8601(),
+	)
+	require.NoError(t, blk.Save(st))
+
+	cm.Start()
+	require.NoError(t, nr.UpdateValidators())
+	require.True(t, nr.Node().HasValidators(newNode.Address()))
+	require.True(t, nr.Node().HasValidators(nodes[1].Address()))
+
+	var connected bool
+	timeout := time.After(time.Second * 5)
+	for !connected {
+		select {
+		case <-timeout:
+			require.Fail(t, "added validator is not connected")
+		case <-time.After(time.Millisecond * 100):
+		}
+
+		for _, address := range cm.AllConnected() {
+			if address == newNode.Address() {
+				connected = true
+			}
+		}
+	}
+}
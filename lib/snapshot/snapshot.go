@@ -0,0 +1,590 @@
+// Package snapshot exports the state of network at a block into a portable
+// file, and seeds the empty storage from it; the new node does not replay
+// the blocks from genesis, it syncs only from the next block of snapshot.
+//
+// The snapshot has,
+//   - the block, which the snapshot is made at, and its certificate
+//   - the genesis block, the first proposed block and the recent blocks
+//   - the accounts, their data entries, the merged accounts and the validator
+//     set changes
+//   - the transactions of genesis block, the proposer transactions of the
+//     blocks and the transactions related to the frozen accounts
+//
+// The snapshot is checked by the trusted block hash; the recent blocks are
+// chained by `PrevBlockHash` to the trusted block, the transactions of the
+// blocks must be in the blocks, and the imported state must make the
+// `StateRoot` of the trusted block; see `statedb.Commit`. The state covers the
+// whole records of accounts, their data entries, the unfreezing requests, the
+// merged accounts and the validator set changes, so the trusted block must be
+// `block.BlockVersionV2` or later. The genesis block is out of the chain, so
+// it is made again from the genesis transaction with the network id like
+// `block.MakeGenesisBlock`.
+package snapshot
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"boscoin.io/sebak/lib/ballot"
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// RecentBlocks is the number of blocks before the snapshot block, which are
+// included in the snapshot; the base fee and the proposer selectors read the
+// recent blocks. The imported node keeps the blocks like the pruned node.
+var RecentBlocks uint64 = common.MinimumPruneBlocks
+
+// Transaction is the confirmed transaction in the snapshot; it is saved with
+// its `BlockOperation`s like the synced transaction.
+type Transaction struct {
+	Block       string                  `json:"block"` // hash of block
+	Height      uint64                  `json:"height"`
+	Confirmed   string                  `json:"confirmed"`
+	Transaction transaction.Transaction `json:"transaction"`
+}
+
+type Body struct {
+	Block               block.Block                `json:"block"`
+	Blocks              []block.Block              `json:"blocks"` // previous blocks in height order
+	Certificates        []ballot.Certificate       `json:"certificates"`
+	Transactions        []Transaction              `json:"transactions"`
+	Accounts            []block.BlockAccount       `json:"accounts"`
+	AccountData         []block.BlockAccountData   `json:"account_data"`
+	MergedAccounts      []block.BlockAccountMerged `json:"merged_accounts"`
+	ValidatorSetChanges []block.ValidatorSetChange `json:"validator_set_changes"`
+}
+
+func (b Body) MakeHashString() string {
+	return base58.Encode(common.MakeHash(common.MustMarshalJSON(b)))
+}
+
+type Snapshot struct {
+	Hash string `json:"hash"` // hash of `Body`
+	Body Body   `json:"body"`
+}
+
+func NewSnapshot(body Body) Snapshot {
+	return Snapshot{
+		Hash: body.MakeHashString(),
+		Body: body,
+	}
+}
+
+func (s Snapshot) String() string {
+	return string(common.MustMarshalJSON(s))
+}
+
+func (s Snapshot) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+func Read(r io.Reader) (s Snapshot, err error) {
+	if err = json.NewDecoder(r).Decode(&s); err != nil {
+		err = errors.InvalidSnapshot.Clone().SetData("error", err.Error())
+	}
+
+	return
+}
+
+// Export makes the snapshot at the latest block of storage.
+func Export(st storage.Backend) (s Snapshot, err error) {
+	var exists bool
+	if exists, err = block.ExistsBlockByHeight(st, common.GenesisBlockHeight); err != nil {
+		return
+	} else if !exists {
+		err = errors.BlockNotFound
+		return
+	}
+
+	var sst storage.Backend
+	if sst, err = st.OpenSnapshot(); err != nil {
+		return
+	}
+	defer sst.Release()
+
+	var body Body
+	body.Block = block.GetLatestBlock(sst)
+
+	if body.Blocks, err = exportBlocks(sst, body.Block.Height); err != nil {
+		return
+	}
+
+	for _, blk := range append(body.Blocks, body.Block) {
+		if exists, err = ballot.ExistsCertificate(sst, blk.Hash); err != nil {
+			return
+		} else if !exists {
+			continue
+		}
+
+		var c ballot.Certificate
+		if c, err = ballot.GetCertificate(sst, blk.Hash); err != nil {
+			return
+		}
+		body.Certificates = append(body.Certificates, c)
+	}
+
+	if body.Accounts, body.AccountData, err = exportAccounts(sst); err != nil {
+		return
+	}
+
+	iterMerged, closeMerged := block.GetBlockAccountsMerged(sst, nil)
+	for {
+		m, hasNext, _ := iterMerged()
+		if !hasNext {
+			break
+		}
+		body.MergedAccounts = append(body.MergedAccounts, *m)
+	}
+	closeMerged()
+
+	iterFunc, closeFunc := block.GetValidatorSetChanges(sst, nil)
+	for {
+		c, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		body.ValidatorSetChanges = append(body.ValidatorSetChanges, *c)
+	}
+	closeFunc()
+
+	var hashes []string
+	if hashes, err = transactionsToExport(sst, body); err != nil {
+		return
+	}
+	for _, hash := range hashes {
+		var t Transaction
+		if t, err = exportTransaction(sst, hash); err != nil {
+			return
+		}
+		body.Transactions = append(body.Transactions, t)
+	}
+
+	s = NewSnapshot(body)
+	return
+}
+
+// exportBlocks returns the genesis block, the first proposed block and the
+// recent blocks before the given height.
+func exportBlocks(st storage.Backend, height uint64) (blocks []block.Block, err error) {
+	var heights []uint64
+	if height > common.GenesisBlockHeight {
+		heights = append(heights, common.GenesisBlockHeight)
+	}
+	if height > common.FirstProposedBlockHeight {
+		heights = append(heights, common.FirstProposedBlockHeight)
+	}
+
+	start := common.FirstProposedBlockHeight + 1
+	if height > RecentBlocks && height-RecentBlocks > start {
+		start = height - RecentBlocks
+	}
+	for h := start; h < height; h++ {
+		heights = append(heights, h)
+	}
+
+	for _, h := range heights {
+		var blk block.Block
+		if blk, err = block.GetBlockByHeight(st, h); err != nil {
+			return
+		}
+		blocks = append(blocks, blk)
+	}
+
+	return
+}
+
+func exportAccounts(st storage.Backend) (accounts []block.BlockAccount, data []block.BlockAccountData, err error) {
+	var addresses []string
+	iterFunc, closeFunc := block.GetBlockAccountAddressesByCreated(st, nil)
+	for {
+		address, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		addresses = append(addresses, address)
+	}
+	closeFunc()
+
+	for _, address := range addresses {
+		var ba *block.BlockAccount
+		if ba, err = block.GetBlockAccount(st, address); err != nil {
+			return
+		}
+		accounts = append(accounts, *ba)
+
+		iterFunc, closeFunc := block.GetBlockAccountDataByAddress(st, address, nil)
+		for {
+			d, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			data = append(data, *d)
+		}
+		closeFunc()
+	}
+
+	return
+}
+
+// transactionsToExport returns the hashes of transactions, which are needed by
+// the node; the genesis transaction, the proposer transactions and the
+// transactions, which created the frozen accounts or requested unfreezing.
+func transactionsToExport(st storage.Backend, body Body) (hashes []string, err error) {
+	found := map[string]bool{}
+	add := func(hash string) {
+		if len(hash) < 1 || found[hash] {
+			return
+		}
+		found[hash] = true
+		hashes = append(hashes, hash)
+	}
+
+	for _, blk := range append(body.Blocks, body.Block) {
+		if blk.Height == common.GenesisBlockHeight {
+			for _, hash := range blk.Transactions {
+				add(hash)
+			}
+		}
+		add(blk.ProposerTransaction)
+	}
+
+	iterFunc, closeFunc := block.GetBlockOperationsByFrozen(st, nil)
+	for {
+		bo, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		add(bo.TxHash)
+	}
+	closeFunc()
+
+	for _, ba := range body.Accounts {
+		if !ba.IsFrozen() {
+			continue
+		}
+
+		iterFunc, closeFunc := block.GetBlockOperationsBySource(st, ba.Address, nil)
+		for {
+			bo, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			add(bo.TxHash)
+		}
+		closeFunc()
+	}
+
+	return
+}
+
+func exportTransaction(st storage.Backend, hash string) (t Transaction, err error) {
+	var bt block.BlockTransaction
+	if bt, err = block.GetBlockTransaction(st, hash); err != nil {
+		return
+	}
+
+	var tp block.TransactionPool
+	if tp, err = block.GetTransactionPool(st, hash); err != nil {
+		return
+	}
+
+	var header block.Header
+	if header, err = block.GetBlockHeader(st, bt.Block); err != nil {
+		return
+	}
+
+	t = Transaction{
+		Block:       bt.Block,
+		Height:      header.Height,
+		Confirmed:   bt.Confirmed,
+		Transaction: tp.Transaction(),
+	}
+
+	return
+}
+
+// Verify checks the snapshot is made at the trusted block of the network.
+func (s Snapshot) Verify(trusted string, networkID []byte) error {
+	invalid := func(reason string) error {
+		return errors.InvalidSnapshot.Clone().SetData("error", reason)
+	}
+
+	if s.Hash != s.Body.MakeHashString() {
+		return invalid("hash does not match")
+	}
+	if s.Body.Block.Hash != trusted {
+		return invalid("block is not trusted")
+	}
+	if s.Body.Block.Version < block.BlockVersionV2 || len(s.Body.Block.StateRoot) < 1 {
+		return invalid("block does not have the state root")
+	}
+
+	blocks := append(s.Body.Blocks, s.Body.Block)
+	hashes := map[string]bool{}
+	byHash := map[string]block.Block{}
+	for _, blk := range blocks {
+		if !blk.VerifyHash() {
+			return invalid("wrong block hash")
+		}
+		hashes[blk.Hash] = true
+		byHash[blk.Hash] = blk
+	}
+
+	// the recent blocks must be chained to the trusted block
+	if !sort.SliceIsSorted(blocks, func(i, j int) bool { return blocks[i].Height < blocks[j].Height }) {
+		return invalid("blocks are not in height order")
+	}
+	if blocks[0].Height != common.GenesisBlockHeight {
+		return invalid("genesis block not found")
+	}
+	if err := s.Body.verifyGenesis(networkID); err != nil {
+		return err
+	}
+	for i := len(blocks) - 1; i > 0; i-- {
+		if blocks[i-1].Height == blocks[i].Height {
+			return invalid("duplicated block")
+		}
+		if blocks[i-1].Height+1 == blocks[i].Height && blocks[i-1].Hash != blocks[i].PrevBlockHash {
+			return invalid("blocks are not chained")
+		}
+	}
+
+	for _, c := range s.Body.Certificates {
+		if !hashes[c.Block] {
+			return invalid("certificate of unknown block")
+		}
+	}
+
+	for _, t := range s.Body.Transactions {
+		if t.Transaction.B.MakeHashString() != t.Transaction.GetHash() {
+			return invalid("wrong transaction hash")
+		}
+
+		// the transaction of the block in snapshot must be in the block;
+		// the others must be related to the frozen accounts, which are
+		// checked by the state root.
+		if blk, found := byHash[t.Block]; found {
+			if t.Height != blk.Height || !hasTransaction(blk, t.Transaction.GetHash()) {
+				return invalid("transaction is not in the block")
+			}
+		} else if !isFrozenTransaction(t.Transaction) {
+			return invalid("transaction of unknown block")
+		}
+	}
+
+	return nil
+}
+
+// verifyGenesis makes the genesis block from the genesis transaction of
+// snapshot; the new genesis block must be same with the snapshot.
+func (b Body) verifyGenesis(networkID []byte) (err error) {
+	invalid := errors.InvalidSnapshot.Clone().SetData("error", "wrong genesis block")
+
+	genesis := b.Blocks[0]
+	var txs []transaction.Transaction
+	for _, t := range b.Transactions {
+		if t.Block == genesis.Hash {
+			txs = append(txs, t.Transaction)
+		}
+	}
+	if len(txs) != 1 || len(txs[0].B.Operations) != 2 {
+		return invalid
+	}
+
+	var accounts []*block.BlockAccount
+	for _, op := range txs[0].B.Operations {
+		casted, ok := op.B.(operation.CreateAccount)
+		if !ok {
+			return invalid
+		}
+		accounts = append(accounts, block.NewBlockAccount(casted.Target, casted.Amount))
+	}
+
+	var config *storage.Config
+	if config, err = storage.NewConfigFromString("memory://"); err != nil {
+		return
+	}
+	var st storage.Backend
+	if st, err = storage.NewStorage(config); err != nil {
+		return
+	}
+	defer st.Close()
+
+	var blk *block.Block
+	if blk, err = block.MakeGenesisBlock(st, *accounts[0], *accounts[1], networkID); err != nil {
+		return invalid
+	}
+	if blk.Hash != genesis.Hash {
+		return invalid
+	}
+
+	return nil
+}
+
+func hasTransaction(blk block.Block, hash string) bool {
+	if blk.ProposerTransaction == hash {
+		return true
+	}
+	for _, h := range blk.Transactions {
+		if h == hash {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isFrozenTransaction checks the transaction creates the frozen account or
+// requests unfreezing.
+func isFrozenTransaction(tx transaction.Transaction) bool {
+	for _, op := range tx.B.Operations {
+		switch op.H.Type {
+		case operation.TypeUnfreezingRequest:
+			return true
+		case operation.TypeCreateAccount:
+			if pop, ok := op.B.(operation.CreateAccount); ok && len(pop.Linked) > 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Import seeds the empty storage from the snapshot, which is made at the
+// trusted block. The blocks until the snapshot block are regarded as pruned,
+// see `block.GetLowestFullBlockHeight`.
+func Import(st storage.Backend, s Snapshot, trusted string, networkID []byte) (err error) {
+	if err = s.Verify(trusted, networkID); err != nil {
+		return
+	}
+
+	var exists bool
+	if exists, err = block.ExistsBlockByHeight(st, common.GenesisBlockHeight); err != nil {
+		return
+	} else if exists {
+		return errors.InvalidSnapshot.Clone().SetData("error", "storage is not empty")
+	}
+
+	var bs storage.Backend
+	if bs, err = st.OpenBatch(); err != nil {
+		return
+	}
+
+	if err = s.Body.save(bs); err != nil {
+		bs.Discard()
+		return
+	}
+	if err = bs.Commit(); err != nil {
+		bs.Discard()
+		return
+	}
+
+	return
+}
+
+func (b Body) save(st storage.Backend) (err error) {
+	for _, blk := range append(b.Blocks, b.Block) {
+		if err = blk.Save(st); err != nil {
+			return
+		}
+	}
+
+	for _, c := range b.Certificates {
+		if err = c.Save(st); err != nil {
+			return
+		}
+	}
+
+	var changes statedb.Changes
+	frozen := map[string]string{} // frozen account: linked account
+	for _, t := range b.Transactions {
+		bt := block.NewBlockTransactionFromTransaction(t.Block, t.Height, t.Confirmed, t.Transaction)
+		if err = bt.Save(st); err != nil {
+			return
+		}
+		if _, err = block.SaveTransactionPool(st, t.Transaction); err != nil {
+			return
+		}
+		if err = bt.SaveBlockOperations(st); err != nil {
+			return
+		}
+
+		for _, op := range t.Transaction.B.Operations {
+			switch op.H.Type {
+			case operation.TypeUnfreezingRequest:
+				changes.UnfreezingRequests = append(
+					changes.UnfreezingRequests,
+					block.NewBlockOperationKey(common.MustMakeObjectHashString(op), t.Transaction.GetHash()),
+				)
+			case operation.TypeCreateAccount:
+				if pop, ok := op.B.(operation.CreateAccount); ok && len(pop.Linked) > 0 {
+					frozen[pop.Target] = pop.Linked
+				}
+			}
+		}
+	}
+
+	for _, ba := range b.Accounts {
+		ba := ba
+		if ba.IsFrozen() && frozen[ba.Address] != ba.Linked {
+			return errors.InvalidSnapshot.Clone().SetData("error", "frozen account is not created")
+		}
+
+		if err = ba.Save(st); err != nil {
+			return
+		}
+		if err = block.NewBlockAccountHistory(&ba, b.Block.Height).Save(st); err != nil {
+			return
+		}
+		changes.Accounts = append(changes.Accounts, ba.Address)
+	}
+
+	if err = block.SaveBlockAccountHistoryStart(st, b.Block.Height); err != nil {
+		return
+	}
+
+	for _, d := range b.AccountData {
+		if err = d.Save(st); err != nil {
+			return
+		}
+		changes.AddAccountData(d.Address, d.Key)
+	}
+
+	for _, m := range b.MergedAccounts {
+		m := m
+		if err = m.Save(st); err != nil {
+			return
+		}
+		changes.MergedAccounts = append(changes.MergedAccounts, m.Address)
+	}
+
+	for _, c := range b.ValidatorSetChanges {
+		if err = c.Save(st); err != nil {
+			return
+		}
+		changes.ValidatorSetChanges = append(changes.ValidatorSetChanges, block.GetValidatorSetChangeKey(c.Height, c.Hash))
+	}
+
+	// the state trie is made from the imported state; it must have the same
+	// root with the trusted block, so nothing is imported out of the state
+	// of the trusted block.
+	var root string
+	if root, err = statedb.Commit(st, "", changes); err != nil {
+		return
+	}
+	if root != b.Block.StateRoot {
+		return errors.InvalidStateRoot
+	}
+
+	return block.SavePrunedBlockHeight(st, b.Block.Height)
+}
@@ -0,0 +1,371 @@
+package snapshot
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb"
+	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/transaction/operation"
+	"boscoin.io/sebak/lib/voting"
+)
+
+var networkID = common.NewTestConfig().NetworkID
+
+type testSnapshotHelper struct {
+	st       storage.Backend
+	kpSource *keypair.Full
+	kpFrozen *keypair.Full
+	frozenTx transaction.Transaction
+}
+
+// Prepare makes the blocks after genesis; the frozen account is created in
+// the third block.
+func (p *testSnapshotHelper) Prepare(t *testing.T, n int) {
+	p.st = block.InitTestBlockchain()
+	p.kpSource = keypair.Random()
+	p.kpFrozen = keypair.Random()
+
+	root, err := statedb.CommitAccounts(p.st, "", nil)
+	require.NoError(t, err)
+
+	prev := block.GetGenesis(p.st)
+	for i := 0; i < n; i++ {
+		var changes statedb.Changes
+		var txHashes []string
+		var txs []transaction.Transaction
+
+		if prev.Height == common.FirstProposedBlockHeight {
+			source := block.NewBlockAccount(p.kpSource.Address(), common.BaseReserve*10)
+			source.MustSave(p.st)
+			require.NoError(t, block.NewBlockAccountData(source.Address, "kyc", "showme").Save(p.st))
+
+			create, _ := operation.NewOperation(operation.NewCreateAccount(p.kpFrozen.Address(), common.BaseReserve, p.kpSource.Address()))
+			p.frozenTx, _ = transaction.NewTransaction(p.kpSource.Address(), 0, create)
+			block.NewBlockAccountLinked(p.kpFrozen.Address(), common.BaseReserve, p.kpSource.Address()).MustSave(p.st)
+
+			txs = append(txs, p.frozenTx)
+			txHashes = append(txHashes, p.frozenTx.GetHash())
+			changes.Accounts = append(changes.Accounts, p.kpSource.Address(), p.kpFrozen.Address())
+			changes.AddAccountData(p.kpSource.Address(), "kyc")
+
+			merged := block.TestMakeBlockAccount()
+			merged.SequenceID = 3
+			require.NoError(t, block.NewBlockAccountMerged(merged).Save(p.st))
+			changes.MergedAccounts = append(changes.MergedAccounts, merged.Address)
+		} else {
+			ba := block.TestMakeBlockAccount()
+			ba.MustSave(p.st)
+			changes.Accounts = append(changes.Accounts, ba.Address)
+		}
+
+		root, err = statedb.Commit(p.st, root, changes)
+		require.NoError(t, err)
+
+		blk := block.NewBlockWithVersion(
+			block.GetBlockVersion(prev.Height+1),
+			keypair.Random().Address(),
+			voting.Basis{
+				Height:    prev.Height + 1,
+				BlockHash: prev.Hash,
+				TotalTxs:  prev.TotalTxs + uint64(len(txs)),
+				TotalOps:  prev.TotalOps + uint64(len(txs)),
+			},
+			"",
+			txHashes,
+			common.NowISO8601(),
+			root,
+		)
+		blk.MustSave(p.st)
+
+		for _, tx := range txs {
+			bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, tx)
+			bt.MustSave(p.st)
+			_, err := block.SaveTransactionPool(p.st, tx)
+			require.NoError(t, err)
+			require.NoError(t, bt.SaveBlockOperations(p.st))
+		}
+
+		prev = *blk
+	}
+}
+
+func (p *testSnapshotHelper) Done() {
+	p.st.Close()
+}
+
+func TestSnapshotExportImport(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	p := &testSnapshotHelper{}
+	p.Prepare(t, 5)
+	defer p.Done()
+
+	latest := block.GetLatestBlock(p.st)
+
+	s, err := Export(p.st)
+	require.NoError(t, err)
+	require.Equal(t, latest.Hash, s.Body.Block.Hash)
+	require.Equal(t, int(latest.Height-1), len(s.Body.Blocks))
+	require.NoError(t, s.Verify(latest.Hash, networkID))
+
+	// write and read
+	var buf bytes.Buffer
+	require.NoError(t, s.Write(&buf))
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	require.Equal(t, s.Hash, read.Hash)
+
+	st := storage.NewTestStorage()
+	defer st.Close()
+	require.NoError(t, Import(st, read, latest.Hash, networkID))
+
+	require.Equal(t, latest.Hash, block.GetLatestBlock(st).Hash)
+	require.Equal(t, latest.Height+1, block.GetLowestFullBlockHeight(st))
+
+	start, err := block.GetBlockAccountHistoryStart(st)
+	require.NoError(t, err)
+	require.Equal(t, latest.Height, start)
+
+	{ // accounts
+		for _, ba := range s.Body.Accounts {
+			imported, err := block.GetBlockAccount(st, ba.Address)
+			require.NoError(t, err)
+			require.Equal(t, ba, *imported)
+
+			h, err := block.GetBlockAccountHistoryAt(st, ba.Address, latest.Height)
+			require.NoError(t, err)
+			require.Equal(t, ba.Balance, h.Balance)
+
+			proved, err := statedb.VerifyAccountProof(latest.StateRoot, ba.Address, mustAccountProof(t, st, latest.StateRoot, ba.Address))
+			require.NoError(t, err)
+			require.Equal(t, ba.Balance, proved.Balance)
+		}
+
+		d, err := block.GetBlockAccountData(st, p.kpSource.Address(), "kyc")
+		require.NoError(t, err)
+		require.Equal(t, "showme", d.Value)
+	}
+
+	{ // merged accounts
+		require.Equal(t, 1, len(s.Body.MergedAccounts))
+		for _, m := range s.Body.MergedAccounts {
+			imported, err := block.GetBlockAccountMerged(st, m.Address)
+			require.NoError(t, err)
+			require.Equal(t, m, *imported)
+		}
+	}
+
+	{ // genesis transaction
+		genesis := block.GetGenesis(st)
+		bt, err := block.GetBlockTransaction(st, genesis.Transactions[0])
+		require.NoError(t, err)
+		for _, opHash := range bt.Operations {
+			exists, err := block.ExistsBlockOperation(st, opHash)
+			require.NoError(t, err)
+			require.True(t, exists)
+		}
+	}
+
+	{ // frozen account is linked
+		var linked []string
+		iterFunc, closeFunc := block.GetBlockOperationsByLinked(st, p.kpSource.Address(), nil)
+		for {
+			bo, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			linked = append(linked, bo.Target)
+		}
+		closeFunc()
+		require.Equal(t, []string{p.kpFrozen.Address()}, linked)
+	}
+
+	{ // storage is not empty
+		err := Import(st, read, latest.Hash, networkID)
+		require.Error(t, err)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+}
+
+func mustAccountProof(t *testing.T, st storage.Backend, root, address string) [][]byte {
+	_, nodes, err := statedb.GetAccountProof(st, root, address)
+	require.NoError(t, err)
+
+	return nodes
+}
+
+func TestSnapshotVerify(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	p := &testSnapshotHelper{}
+	p.Prepare(t, 5)
+	defer p.Done()
+
+	latest := block.GetLatestBlock(p.st)
+
+	s, err := Export(p.st)
+	require.NoError(t, err)
+
+	{ // not trusted block
+		err := s.Verify(block.GetGenesis(p.st).Hash, networkID)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+
+	{ // body is changed
+		changed := s
+		changed.Body.Accounts = append([]block.BlockAccount{}, s.Body.Accounts...)
+		changed.Body.Accounts[len(changed.Body.Accounts)-1].Balance += 1
+		err := changed.Verify(latest.Hash, networkID)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+
+	{ // block is changed
+		changed := s
+		changed.Body.Blocks = append([]block.Block{}, s.Body.Blocks...)
+		changed.Body.Blocks[len(changed.Body.Blocks)-1].TotalTxs += 1
+		changed = NewSnapshot(changed.Body)
+		err := changed.Verify(latest.Hash, networkID)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+
+	{ // accounts do not make the state root of block
+		changed := s
+		changed.Body.Accounts = append([]block.BlockAccount{}, s.Body.Accounts...)
+		changed.Body.Accounts[len(changed.Body.Accounts)-1].Balance += 1
+		changed = NewSnapshot(changed.Body)
+		require.NoError(t, changed.Verify(latest.Hash, networkID))
+
+		st := storage.NewTestStorage()
+		defer st.Close()
+		require.Equal(t, errors.InvalidStateRoot, Import(st, changed, latest.Hash, networkID))
+
+		exists, err := block.ExistsBlockByHeight(st, common.GenesisBlockHeight)
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+
+	{ // the block without state root
+		changed := s
+		changed.Body.Block.Version = block.BlockVersionV1
+		changed.Body.Block.StateRoot = ""
+		changed = NewSnapshot(changed.Body)
+		err := changed.Verify(latest.Hash, networkID)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+
+	{ // genesis block of the other network
+		err := s.Verify(latest.Hash, []byte("sebak-other-network"))
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+
+	{ // genesis transaction is missing
+		genesis := block.GetGenesis(p.st)
+		changed := s
+		changed.Body.Transactions = nil
+		for _, t := range s.Body.Transactions {
+			if t.Block != genesis.Hash {
+				changed.Body.Transactions = append(changed.Body.Transactions, t)
+			}
+		}
+		changed = NewSnapshot(changed.Body)
+		err := changed.Verify(latest.Hash, networkID)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+
+	{ // genesis transaction is changed
+		genesis := block.GetGenesis(p.st)
+		changed := s
+		changed.Body.Transactions = append([]Transaction{}, s.Body.Transactions...)
+		for i, t := range changed.Body.Transactions {
+			if t.Block != genesis.Hash {
+				continue
+			}
+			tx := t.Transaction
+			tx.B.Operations = append([]operation.Operation{}, tx.B.Operations...)
+			opb := tx.B.Operations[0].B.(operation.CreateAccount)
+			opb.Amount -= 1
+			tx.B.Operations[0].B = opb
+			changed.Body.Transactions[i].Transaction = tx
+		}
+		changed = NewSnapshot(changed.Body)
+		err := changed.Verify(latest.Hash, networkID)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+
+	{ // transaction is not in the block
+		changed := s
+		changed.Body.Transactions = append([]Transaction{}, s.Body.Transactions...)
+		tx := transaction.TestMakeTransactionWithKeypair(networkID, 1, p.kpSource)
+		changed.Body.Transactions = append(changed.Body.Transactions, Transaction{
+			Block:       latest.Hash,
+			Height:      latest.Height,
+			Confirmed:   latest.ProposedTime,
+			Transaction: tx,
+		})
+		changed = NewSnapshot(changed.Body)
+		err := changed.Verify(latest.Hash, networkID)
+		require.Equal(t, errors.InvalidSnapshot.Code, err.(*errors.Error).Code)
+	}
+}
+
+// TestSnapshotImportInjected checks the state, which is not in the state of
+// trusted block, can not be imported.
+func TestSnapshotImportInjected(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	p := &testSnapshotHelper{}
+	p.Prepare(t, 5)
+	defer p.Done()
+
+	latest := block.GetLatestBlock(p.st)
+
+	s, err := Export(p.st)
+	require.NoError(t, err)
+
+	injects := map[string]func(body *Body){
+		"signers": func(body *Body) {
+			body.Accounts = append([]block.BlockAccount{}, body.Accounts...)
+			body.Accounts[0].Signers = []operation.Signer{{Address: keypair.Random().Address(), Weight: 1}}
+		},
+		"linked": func(body *Body) {
+			body.Accounts = append([]block.BlockAccount{}, body.Accounts...)
+			for i := range body.Accounts {
+				if body.Accounts[i].Address == p.kpFrozen.Address() {
+					body.Accounts[i].Linked = ""
+				}
+			}
+		},
+		"account data": func(body *Body) {
+			body.AccountData = append(body.AccountData, *block.NewBlockAccountData(p.kpSource.Address(), "findme", "showme"))
+		},
+		"merged account": func(body *Body) {
+			body.MergedAccounts = append([]block.BlockAccountMerged{}, body.MergedAccounts...)
+			body.MergedAccounts[0].SequenceID = 0
+		},
+		"validator set change": func(body *Body) {
+			body.ValidatorSetChanges = append(
+				body.ValidatorSetChanges,
+				*block.NewValidatorSetChange("findme", latest.Height+1, []string{keypair.Random().Address()}, []string{"https://localhost:12345"}, nil),
+			)
+		},
+	}
+
+	for name, inject := range injects {
+		body := s.Body
+		inject(&body)
+		changed := NewSnapshot(body)
+		require.NoError(t, changed.Verify(latest.Hash, networkID), name)
+
+		st := storage.NewTestStorage()
+		require.Equal(t, errors.InvalidStateRoot, Import(st, changed, latest.Hash, networkID), name)
+		st.Close()
+	}
+}
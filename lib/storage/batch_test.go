@@ -10,103 +10,134 @@ import (
 )
 
 func TestBatchBackendNew(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	fetched := map[int]string{}
-	key := "showme"
-	input := map[int]string{
-		90: "99",
-		91: "91",
-		92: "92",
-	}
-
-	bt, err := st.OpenBatch()
-	require.NoError(t, err)
-
-	{ // `Get` failed in both
-		{ // in normal LeveldbBatch
-			err := st.Get(key, &fetched)
-			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+	testBackends(t, func(t *testing.T, st Backend) {
+		fetched := map[int]string{}
+		key := "showme"
+		input := map[int]string{
+			90: "99",
+			91: "91",
+			92: "92",
+		}
+
+		bt, err := st.OpenBatch()
+		require.NoError(t, err)
+
+		{ // `Get` failed in both
+			{ // in normal LeveldbBatch
+				err := st.Get(key, &fetched)
+				require.Equal(t, errors.StorageRecordDoesNotExist, err)
+			}
+
+			{ // in BatchBackend
+				err := bt.Get(key, &fetched)
+				require.Equal(t, errors.StorageRecordDoesNotExist, err)
+			}
+		}
+
+		{ // `New` in BatchBackend, but it does not stored in LeveldbBatch
+			{ // in BatchBackend
+				err := bt.New(key, input)
+				require.NoError(t, err)
+			}
+
+			{ // in normal LeveldbBatch
+				err := st.Get(key, &fetched)
+				require.Equal(t, errors.StorageRecordDoesNotExist, err)
+			}
 		}
 
-		{ // in BatchBackend
+		{ // `Get` must return the value of `New` in BatchBackend
 			err := bt.Get(key, &fetched)
-			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+			require.NoError(t, err)
+
+			require.True(t, reflect.DeepEqual(input, fetched))
+		}
+
+		{ // `New` must be failed because already `New`ed
+			err = bt.New(key, input)
+			require.Equal(t, errors.StorageRecordAlreadyExists.Code, err.(*errors.Error).Code)
 		}
-	}
 
-	{ // `New` in BatchBackend, but it does not stored in LeveldbBatch
-		{ // in BatchBackend
-			err := bt.New(key, input)
+		{ // `Commit` batch, it must be stored in LeveldbBatch
+			err := bt.Commit()
 			require.NoError(t, err)
+
+			err = st.Get(key, &fetched)
+			require.NoError(t, err)
+			require.True(t, reflect.DeepEqual(input, fetched))
 		}
+	})
+}
 
-		{ // in normal LeveldbBatch
-			err := st.Get(key, &fetched)
-			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+func TestBatchBackendDelete(t *testing.T) {
+	testBackends(t, func(t *testing.T, st Backend) {
+		fetched := map[int]string{}
+		key := "showme"
+		input := map[int]string{
+			90: "99",
+			91: "91",
+			92: "92",
 		}
-	}
 
-	{ // `Get` must return the value of `New` in BatchBackend
-		err := bt.Get(key, &fetched)
-		require.NoError(t, err)
+		{
+			err := st.New(key, input)
+			require.NoError(t, err)
+		}
 
-		require.True(t, reflect.DeepEqual(input, fetched))
-	}
+		bt, _ := st.OpenBatch()
 
-	{ // `New` must be failed because already `New`ed
-		err = bt.New(key, input)
-		require.Equal(t, errors.StorageRecordAlreadyExists.Code, err.(*errors.Error).Code)
-	}
+		// `Delete` must be failed because already `New`ed
+		bt.Remove(key)
 
-	{ // `Commit` batch, it must be stored in LeveldbBatch
-		err := bt.Commit()
-		require.NoError(t, err)
+		{ // in LeveldbBatch still have data
+			err := st.Get(key, &fetched)
+			require.NoError(t, err)
 
-		err = st.Get(key, &fetched)
-		require.NoError(t, err)
-		require.True(t, reflect.DeepEqual(input, fetched))
-	}
-}
+			require.True(t, reflect.DeepEqual(input, fetched))
+		}
 
-func TestBatchBackendDelete(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	fetched := map[int]string{}
-	key := "showme"
-	input := map[int]string{
-		90: "99",
-		91: "91",
-		92: "92",
-	}
-
-	{
-		err := st.New(key, input)
+		err := bt.Commit()
 		require.NoError(t, err)
-	}
 
-	bt, _ := st.OpenBatch()
+		{ // after `Commit`, it must be removed in LeveldbBatch and BatchBackend
+			err = bt.Get(key, &fetched)
+			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+
+			err = st.Get(key, &fetched)
+			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+		}
+	})
+}
 
-	// `Delete` must be failed because already `New`ed
-	bt.Remove(key)
+func TestBatchBackendGetAfterDelete(t *testing.T) {
+	testBackends(t, func(t *testing.T, st Backend) {
+		key := "showme"
+		require.NoError(t, st.New(key, "findme"))
 
-	{ // in LeveldbBatch still have data
-		err := st.Get(key, &fetched)
-		require.NoError(t, err)
+		bt, _ := st.OpenBatch()
+		require.NoError(t, bt.Remove(key))
 
-		require.True(t, reflect.DeepEqual(input, fetched))
-	}
+		{ // the removed item is not found in batch before `Commit`
+			exists, err := bt.Has(key)
+			require.NoError(t, err)
+			require.False(t, exists)
 
-	err := bt.Commit()
-	require.NoError(t, err)
+			var fetched string
+			err = bt.Get(key, &fetched)
+			require.Equal(t, errors.StorageRecordDoesNotExist, err)
+		}
 
-	{ // after `Commit`, it must be removed in LeveldbBatch and BatchBackend
-		err = bt.Get(key, &fetched)
-		require.Equal(t, errors.StorageRecordDoesNotExist, err)
+		{ // still in storage
+			exists, err := st.Has(key)
+			require.NoError(t, err)
+			require.True(t, exists)
+		}
 
-		err = st.Get(key, &fetched)
-		require.Equal(t, errors.StorageRecordDoesNotExist, err)
-	}
+		require.NoError(t, bt.New(key, "showme"))
+		{
+			var fetched string
+			require.NoError(t, bt.Get(key, &fetched))
+			require.Equal(t, "showme", fetched)
+		}
+	})
 }
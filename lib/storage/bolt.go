@@ -0,0 +1,771 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+
+	"boscoin.io/sebak/lib/errors"
+)
+
+var boltBucketName = []byte("sebak")
+
+// BoltSnapshotLimit is the maximum number of the opened snapshots of one
+// BoltDB. The snapshot holds the read-only transaction of BoltDB until it is
+// released, and the writes, which need to grow the mmap over
+// `boltInitialMmapSize`, wait until the all snapshots are released; the
+// snapshots should be released as soon as possible.
+const BoltSnapshotLimit int = 10
+
+// boltInitialMmapSize is the initial mmap size of BoltDB; the read-only
+// transactions do not block the writes until the database grows over it.
+const boltInitialMmapSize int = 1 << 30
+
+// BoltDBBackend is the `Backend` on BoltDB. BoltDB does not support batch
+// and transaction like LevelDB, so the changes of batch and transaction are
+// kept in memory and written in one BoltDB transaction by `Commit`.
+type BoltDBBackend struct {
+	DB *bolt.DB
+
+	batch     *boltBatch
+	snapshot  *boltSnapshot
+	snapshots *boltSnapshots
+}
+
+type boltBatch struct {
+	sync.RWMutex
+
+	inserted map[string][]byte
+	deleted  map[string]struct{}
+}
+
+func newBoltBatch() *boltBatch {
+	return &boltBatch{
+		inserted: map[string][]byte{},
+		deleted:  map[string]struct{}{},
+	}
+}
+
+func (bb *boltBatch) clear() {
+	bb.inserted = map[string][]byte{}
+	bb.deleted = map[string]struct{}{}
+}
+
+// boltSnapshot holds the read-only transaction of BoltDB. The transaction of
+// BoltDB is not safe for the concurrent use, so it is used with the lock.
+type boltSnapshot struct {
+	sync.Mutex
+
+	tx       *bolt.Tx
+	released bool
+}
+
+// boltSnapshots counts the opened snapshots of BoltDB.
+type boltSnapshots struct {
+	sync.Mutex
+
+	opened int
+}
+
+func (bs *boltSnapshots) open() bool {
+	bs.Lock()
+	defer bs.Unlock()
+
+	if bs.opened >= BoltSnapshotLimit {
+		return false
+	}
+	bs.opened++
+
+	return true
+}
+
+func (bs *boltSnapshots) close() {
+	bs.Lock()
+	defer bs.Unlock()
+
+	bs.opened--
+}
+
+func setBoltDBCoreError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return errors.Newf(
+		errors.StorageCoreError,
+		"%s: %s", errors.StorageCoreError.Message, err.Error(),
+	)
+}
+
+func (st *BoltDBBackend) Init(config *Config) (err error) {
+	if err = os.MkdirAll(filepath.Dir(config.Path), 0700); err != nil {
+		err = setBoltDBCoreError(err)
+		return
+	}
+
+	var db *bolt.DB
+	if db, err = bolt.Open(config.Path, 0600, &bolt.Options{InitialMmapSize: boltInitialMmapSize}); err != nil {
+		err = setBoltDBCoreError(err)
+		return
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		err = setBoltDBCoreError(err)
+		return
+	}
+
+	st.DB = db
+	st.snapshots = &boltSnapshots{}
+
+	return
+}
+
+// Close closes BoltDB; the snapshot is released instead, because it shares
+// BoltDB.
+func (st *BoltDBBackend) Close() error {
+	if st.snapshot != nil {
+		return st.Release()
+	}
+
+	return st.DB.Close()
+}
+
+func (st *BoltDBBackend) Release() error {
+	if st.snapshot == nil {
+		return nil
+	}
+
+	st.snapshot.Lock()
+	defer st.snapshot.Unlock()
+
+	if st.snapshot.released {
+		return nil
+	}
+	st.snapshot.released = true
+
+	err := st.snapshot.tx.Rollback()
+	st.snapshots.close()
+
+	return setBoltDBCoreError(err)
+}
+
+func (st *BoltDBBackend) OpenTransaction() (Backend, error) {
+	if st.batch != nil {
+		return nil, errors.AlreadyCommittable
+	}
+
+	return &BoltDBBackend{
+		DB:        st.DB,
+		batch:     newBoltBatch(),
+		snapshots: st.snapshots,
+	}, nil
+}
+
+func (st *BoltDBBackend) OpenBatch() (Backend, error) {
+	if st.batch != nil {
+		return nil, errors.AlreadyCommittable
+	}
+
+	return &BoltDBBackend{
+		DB:        st.DB,
+		batch:     newBoltBatch(),
+		snapshots: st.snapshots,
+	}, nil
+}
+
+// OpenSnapshot opens new read-only transaction of BoltDB as snapshot. Only
+// `BoltSnapshotLimit` snapshots can be opened at the same time, see
+// `BoltSnapshotLimit`.
+func (st *BoltDBBackend) OpenSnapshot() (Backend, error) {
+	if st.snapshot != nil {
+		return nil, errors.NotImplemented
+	}
+
+	if !st.snapshots.open() {
+		return nil, errors.SnapshotLimitReached
+	}
+
+	tx, err := st.DB.Begin(false)
+	if err != nil {
+		st.snapshots.close()
+		return nil, setBoltDBCoreError(err)
+	}
+
+	return &BoltDBBackend{
+		DB:        st.DB,
+		snapshot:  &boltSnapshot{tx: tx},
+		snapshots: st.snapshots,
+	}, nil
+}
+
+func (st *BoltDBBackend) Discard() error {
+	if st.batch == nil {
+		return errors.NotCommittable
+	}
+
+	st.batch.Lock()
+	defer st.batch.Unlock()
+
+	st.batch.clear()
+
+	return nil
+}
+
+func (st *BoltDBBackend) Commit() error {
+	if st.batch == nil {
+		return errors.NotCommittable
+	}
+
+	st.batch.Lock()
+	defer st.batch.Unlock()
+
+	err := st.DB.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucketName)
+		for k := range st.batch.deleted {
+			if err := bucket.Delete([]byte(k)); err != nil {
+				return err
+			}
+		}
+		for k, v := range st.batch.inserted {
+			if err := bucket.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return setBoltDBCoreError(err)
+	}
+
+	st.batch.clear()
+
+	return nil
+}
+
+// view runs `f` with the bucket in new read-only transaction or in the
+// transaction of snapshot.
+func (st *BoltDBBackend) view(f func(*bolt.Bucket) error) error {
+	if st.snapshot != nil {
+		st.snapshot.Lock()
+		defer st.snapshot.Unlock()
+
+		if st.snapshot.released {
+			return errors.SnapshotNotFound
+		}
+
+		return f(st.snapshot.tx.Bucket(boltBucketName))
+	}
+
+	return st.DB.View(func(tx *bolt.Tx) error {
+		return f(tx.Bucket(boltBucketName))
+	})
+}
+
+// write puts the values and deletes the keys, which has nil value, in
+// batch or in new BoltDB transaction.
+func (st *BoltDBBackend) write(vs map[string][]byte) error {
+	if st.snapshot != nil {
+		return errors.NotImplemented
+	}
+
+	if st.batch != nil {
+		st.batch.Lock()
+		defer st.batch.Unlock()
+
+		for k, v := range vs {
+			if v == nil {
+				delete(st.batch.inserted, k)
+				st.batch.deleted[k] = struct{}{}
+			} else {
+				st.batch.inserted[k] = v
+				delete(st.batch.deleted, k)
+			}
+		}
+
+		return nil
+	}
+
+	return st.DB.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucketName)
+		for k, v := range vs {
+			var err error
+			if v == nil {
+				err = bucket.Delete([]byte(k))
+			} else {
+				err = bucket.Put([]byte(k), v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (st *BoltDBBackend) get(k string) (b []byte, found bool, err error) {
+	if st.batch != nil {
+		st.batch.RLock()
+		b, found = st.batch.inserted[k]
+		_, deleted := st.batch.deleted[k]
+		st.batch.RUnlock()
+
+		if found {
+			return
+		} else if deleted {
+			return
+		}
+	}
+
+	err = st.view(func(bucket *bolt.Bucket) error {
+		if v := bucket.Get([]byte(k)); v != nil {
+			b = make([]byte, len(v))
+			copy(b, v)
+			found = true
+		}
+		return nil
+	})
+
+	return
+}
+
+// boltIteratorPageSize is the number of items, which the iterator of BoltDB
+// reads in one read-only transaction.
+const boltIteratorPageSize int = 64
+
+// boltIterator iterates the items by the prefix with the changes of batch
+// without loading the all items. The items of BoltDB are read by
+// `boltIteratorPageSize` in new read-only transaction, so the transaction is
+// not held between the calls of `next`.
+type boltIterator struct {
+	st      *BoltDBBackend
+	prefix  []byte
+	reverse bool
+
+	from      []byte // the items are read from `from`
+	inclusive bool   // whether the item of `from` is included
+	exhausted bool
+	items     []IterItem // read from BoltDB, but not yet returned
+
+	batch    []IterItem // the inserted items of batch in the order
+	modified map[string]struct{}
+	err      error
+}
+
+// newBoltIterator starts to iterate from `from`; if `from` is nil, it
+// starts from the first or the last item by the prefix.
+func newBoltIterator(st *BoltDBBackend, prefix, from []byte, inclusive, reverse bool) *boltIterator {
+	it := &boltIterator{
+		st:        st,
+		prefix:    prefix,
+		reverse:   reverse,
+		inclusive: inclusive,
+	}
+
+	// the position should be inside the prefix
+	if from == nil || (!reverse && bytes.Compare(from, prefix) < 0) {
+		if reverse {
+			from = boltPrefixLimit(prefix)
+			it.inclusive = false
+		} else {
+			from = prefix
+			it.inclusive = true
+		}
+	} else if reverse {
+		if limit := boltPrefixLimit(prefix); limit != nil && bytes.Compare(from, limit) >= 0 {
+			from = limit
+			it.inclusive = false
+		}
+	}
+	it.from = from
+
+	if st.batch == nil {
+		return it
+	}
+
+	st.batch.RLock()
+	defer st.batch.RUnlock()
+
+	it.modified = map[string]struct{}{}
+	for k := range st.batch.deleted {
+		if bytes.HasPrefix([]byte(k), prefix) {
+			it.modified[k] = struct{}{}
+		}
+	}
+	for k, v := range st.batch.inserted {
+		key := []byte(k)
+		if !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+		it.modified[k] = struct{}{}
+		if !it.after(key) {
+			continue
+		}
+		it.batch = append(it.batch, IterItem{Key: key, Value: v}.Clone())
+	}
+	sort.Slice(it.batch, func(i, j int) bool {
+		return it.less(it.batch[i].Key, it.batch[j].Key)
+	})
+
+	return it
+}
+
+// boltPrefixLimit returns the smallest key, which is greater than the all
+// keys by the prefix. If nil, there is no such key.
+func boltPrefixLimit(prefix []byte) []byte {
+	limit := make([]byte, len(prefix))
+	copy(limit, prefix)
+	for i := len(limit) - 1; i >= 0; i-- {
+		if limit[i] < 0xff {
+			limit[i]++
+			return limit[:i+1]
+		}
+	}
+
+	return nil
+}
+
+// less checks whether `a` comes before `b` in the order of iterator.
+func (it *boltIterator) less(a, b []byte) bool {
+	if it.reverse {
+		return bytes.Compare(a, b) > 0
+	}
+	return bytes.Compare(a, b) < 0
+}
+
+// after checks whether the key comes at or after the starting position.
+func (it *boltIterator) after(key []byte) bool {
+	if it.from == nil {
+		return true
+	}
+	if bytes.Equal(key, it.from) {
+		return it.inclusive
+	}
+	return it.less(it.from, key)
+}
+
+// read reads the next page of items from BoltDB.
+func (it *boltIterator) read() {
+	var items []IterItem
+	err := it.st.view(func(bucket *bolt.Bucket) error {
+		c := bucket.Cursor()
+
+		var k, v []byte
+		if it.from == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Seek(it.from)
+			if it.reverse {
+				if k == nil {
+					k, v = c.Last()
+				} else if !bytes.Equal(k, it.from) || !it.inclusive {
+					k, v = c.Prev()
+				}
+			} else if k != nil && bytes.Equal(k, it.from) && !it.inclusive {
+				k, v = c.Next()
+			}
+		}
+
+		for ; k != nil && bytes.HasPrefix(k, it.prefix); k, v = it.step(c) {
+			if len(items) >= boltIteratorPageSize {
+				return nil
+			}
+			items = append(items, IterItem{Key: k, Value: v}.Clone())
+		}
+		it.exhausted = true
+
+		return nil
+	})
+	if err != nil {
+		it.err = err
+		it.exhausted = true
+		return
+	}
+
+	if len(items) > 0 {
+		it.from = items[len(items)-1].Key
+		it.inclusive = false
+	}
+
+	for _, item := range items {
+		if _, found := it.modified[string(item.Key)]; found {
+			continue
+		}
+		it.items = append(it.items, item)
+	}
+}
+
+func (it *boltIterator) close() {
+	it.items = nil
+	it.batch = nil
+	it.exhausted = true
+}
+
+func (it *boltIterator) step(c *bolt.Cursor) ([]byte, []byte) {
+	if it.reverse {
+		return c.Prev()
+	}
+	return c.Next()
+}
+
+// next returns the next item; the items of batch are merged with the items
+// of BoltDB.
+func (it *boltIterator) next() (IterItem, bool) {
+	for len(it.items) < 1 && !it.exhausted {
+		it.read()
+	}
+
+	switch {
+	case len(it.items) < 1 && len(it.batch) < 1:
+		return IterItem{}, false
+	case len(it.items) < 1:
+	case len(it.batch) < 1 || it.less(it.items[0].Key, it.batch[0].Key):
+		item := it.items[0]
+		it.items = it.items[1:]
+		return item, true
+	}
+
+	item := it.batch[0]
+	it.batch = it.batch[1:]
+
+	return item, true
+}
+
+func (st *BoltDBBackend) Has(k string) (bool, error) {
+	_, found, err := st.get(k)
+	if err != nil {
+		return false, setBoltDBCoreError(err)
+	}
+
+	return found, nil
+}
+
+func (st *BoltDBBackend) GetRaw(k string) (b []byte, err error) {
+	var found bool
+	if b, found, err = st.get(k); err != nil {
+		err = setBoltDBCoreError(err)
+		return
+	} else if !found {
+		err = errors.StorageRecordDoesNotExist
+		return
+	}
+
+	return
+}
+
+func (st *BoltDBBackend) Get(k string, i interface{}) (err error) {
+	var b []byte
+	if b, err = st.GetRaw(k); err != nil {
+		return
+	}
+
+	if err = deserialize(b, i); err != nil {
+		return setBoltDBCoreError(err)
+	}
+
+	return
+}
+
+func (st *BoltDBBackend) New(k string, v interface{}) error {
+	if exists, err := st.Has(k); err != nil {
+		return err
+	} else if exists {
+		return errors.Newf(errors.StorageRecordAlreadyExists, "record {%v} already exists in storage", k)
+	}
+
+	if encoded, err := serialize(v); err != nil {
+		return setBoltDBCoreError(err)
+	} else {
+		return setBoltDBCoreError(st.write(map[string][]byte{k: encoded}))
+	}
+}
+
+func (st *BoltDBBackend) News(vs ...Item) (err error) {
+	if len(vs) < 1 {
+		err = setBoltDBCoreError(errors.New("empty values"))
+		return
+	}
+
+	var exists bool
+	for _, v := range vs {
+		if exists, err = st.Has(v.Key); exists || err != nil {
+			if exists {
+				return errors.Newf(errors.StorageRecordAlreadyExists, "record {%v} already exists in storage", v.Key)
+			}
+			return
+		}
+	}
+
+	encoded := map[string][]byte{}
+	for _, v := range vs {
+		if encoded[v.Key], err = serialize(v); err != nil {
+			return setBoltDBCoreError(err)
+		}
+	}
+
+	err = setBoltDBCoreError(st.write(encoded))
+
+	return
+}
+
+func (st *BoltDBBackend) Set(k string, v interface{}) (err error) {
+	var encoded []byte
+	if encoded, err = serialize(v); err != nil {
+		return setBoltDBCoreError(err)
+	}
+
+	var exists bool
+	if exists, err = st.Has(k); !exists || err != nil {
+		if !exists {
+			err = errors.StorageRecordDoesNotExist
+			return
+		}
+		return
+	}
+
+	err = setBoltDBCoreError(st.write(map[string][]byte{k: encoded}))
+
+	return
+}
+
+func (st *BoltDBBackend) Sets(vs ...Item) (err error) {
+	if len(vs) < 1 {
+		err = setBoltDBCoreError(errors.New("empty values"))
+		return
+	}
+
+	var exists bool
+	for _, v := range vs {
+		if exists, err = st.Has(v.Key); !exists || err != nil {
+			if !exists {
+				err = errors.StorageRecordDoesNotExist
+				return
+			}
+			return
+		}
+	}
+
+	encoded := map[string][]byte{}
+	for _, v := range vs {
+		if encoded[v.Key], err = serialize(v); err != nil {
+			return setBoltDBCoreError(err)
+		}
+	}
+
+	err = setBoltDBCoreError(st.write(encoded))
+
+	return
+}
+
+func (st *BoltDBBackend) PutRaw(k string, b []byte) error {
+	if b == nil {
+		b = []byte{}
+	}
+
+	return setBoltDBCoreError(st.write(map[string][]byte{k: b}))
+}
+
+func (st *BoltDBBackend) Remove(k string) error {
+	if exists, err := st.Has(k); err != nil {
+		return err
+	} else if !exists {
+		return errors.StorageRecordDoesNotExist
+	} else {
+		return setBoltDBCoreError(st.write(map[string][]byte{k: nil}))
+	}
+}
+
+// GetIterator follows the behavior of `LevelDBBackend.GetIterator`; the item
+// of cursor is not included.
+func (st *BoltDBBackend) GetIterator(prefix string, option ListOptions) (func() (IterItem, bool), func()) {
+	var reverse = false
+	var cursor []byte
+	var limit uint64 = 0
+	if option != nil {
+		reverse = option.Reverse()
+		cursor = option.Cursor()
+		limit = option.Limit()
+	}
+
+	// like `LevelDBBackend.GetIterator`, with cursor, the first item, which is
+	// equal or greater than cursor, is skipped in forward.
+	it := newBoltIterator(st, []byte(prefix), cursor, !reverse, reverse)
+	skip := cursor != nil && !reverse
+
+	var n uint64 = 0
+	return func() (IterItem, bool) {
+			if skip {
+				skip = false
+				it.next()
+			}
+
+			item, exists := it.next()
+			if !exists {
+				return IterItem{N: n}, false
+			}
+
+			n++
+			item.N = n
+
+			if limit != 0 && n > limit {
+				return item, false
+			}
+
+			return item, true
+		},
+		func() {
+			it.close()
+		}
+}
+
+func (st *BoltDBBackend) Walk(prefix string, option *WalkOption, walkFunc WalkFunc) error {
+	if option == nil {
+		option = &WalkOption{
+			Cursor:  prefix,
+			Reverse: false,
+			Limit:   10,
+		}
+	}
+
+	var from []byte
+	if option.Cursor != "" {
+		from = []byte(option.Cursor)
+		if option.Reverse {
+			// like `LevelDBBackend.Walk`, in reverse, it starts from the first
+			// item, which is equal or greater than cursor.
+			first, found := newBoltIterator(st, []byte(prefix), from, true, false).next()
+			if !found {
+				return nil
+			}
+			from = first.Key
+		}
+	}
+
+	it := newBoltIterator(st, []byte(prefix), from, true, option.Reverse)
+
+	var cnt uint64 = 0
+	for item, found := it.next(); found; item, found = it.next() {
+		if cnt >= option.Limit {
+			return nil
+		}
+
+		if next, err := walkFunc(item.Key, item.Value); err != nil {
+			return err
+		} else if next == false {
+			return nil
+		}
+		cnt++
+	}
+
+	return setBoltDBCoreError(it.err)
+}
@@ -73,7 +73,7 @@ func (st *LevelDBBackend) Release() error {
 	return nil
 }
 
-func (st *LevelDBBackend) OpenTransaction() (*LevelDBBackend, error) {
+func (st *LevelDBBackend) OpenTransaction() (Backend, error) {
 	_, ok := st.Core.(*leveldb.Transaction)
 	if ok {
 		return nil, errors.AlreadyCommittable
@@ -91,7 +91,7 @@ func (st *LevelDBBackend) OpenTransaction() (*LevelDBBackend, error) {
 	}, nil
 }
 
-func (st *LevelDBBackend) OpenBatch() (*LevelDBBackend, error) {
+func (st *LevelDBBackend) OpenBatch() (Backend, error) {
 	_, ok := st.Core.(*BatchCore)
 	if ok {
 		return nil, errors.AlreadyCommittable
@@ -103,7 +103,7 @@ func (st *LevelDBBackend) OpenBatch() (*LevelDBBackend, error) {
 	}, nil
 }
 
-func (st *LevelDBBackend) OpenSnapshot() (*LevelDBBackend, error) {
+func (st *LevelDBBackend) OpenSnapshot() (Backend, error) {
 	snapshot, err := NewSnapshot(st)
 	if err != nil {
 		return nil, err
@@ -278,6 +278,10 @@ func (st *LevelDBBackend) Sets(vs ...Item) (err error) {
 	return
 }
 
+func (st *LevelDBBackend) PutRaw(k string, b []byte) error {
+	return setLevelDBCoreError(st.Core.Put(st.makeKey(k), b, nil))
+}
+
 func (st *LevelDBBackend) Remove(k string) error {
 	if exists, err := st.Has(k); err != nil {
 		return err
@@ -13,449 +13,436 @@ import (
 )
 
 func TestLevelDBBackendNew(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	key := "showme"
-	input := map[int]string{
-		90: "99",
-		91: "91",
-		92: "92",
-	}
-	if err := st.New(key, input); err != nil {
-		t.Errorf("failed to 'New' in leveldb: %v", err)
-		return
-	}
+	testBackends(t, func(t *testing.T, st Backend) {
+		key := "showme"
+		input := map[int]string{
+			90: "99",
+			91: "91",
+			92: "92",
+		}
+		if err := st.New(key, input); err != nil {
+			t.Errorf("failed to 'New' in leveldb: %v", err)
+			return
+		}
 
-	fetched := map[int]string{}
-	err := st.Get(key, &fetched)
-	if err != nil {
-		t.Errorf("failed to 'Get' in leveldb: %v", err)
-		return
-	}
+		fetched := map[int]string{}
+		err := st.Get(key, &fetched)
+		if err != nil {
+			t.Errorf("failed to 'Get' in leveldb: %v", err)
+			return
+		}
 
-	if !reflect.DeepEqual(input, fetched) {
-		t.Errorf("failed to 'Get' the same input in leveldb")
-		return
-	}
+		if !reflect.DeepEqual(input, fetched) {
+			t.Errorf("failed to 'Get' the same input in leveldb")
+			return
+		}
 
-	if err := st.New(key, input); err == nil {
-		t.Errorf("'New' only for new key in leveldb")
-		return
-	}
+		if err := st.New(key, input); err == nil {
+			t.Errorf("'New' only for new key in leveldb")
+			return
+		}
+	})
 }
 
 func TestLevelDBBackendNews(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	input := map[string]int{}
-	for i := 0; i < 100; i++ {
-		input[fmt.Sprintf("%d", i)] = i
-	}
-	var args []Item
-	for k, v := range input {
-		args = append(
-			args,
-			Item{k, v},
-		)
-	}
-
-	if err := st.News(args...); err != nil {
-		t.Errorf("failed to `News`: %v", err)
-	}
-
-	for _, i := range args {
-		if exists, err := st.Has(i.Key); !exists || err != nil {
-			if !exists {
-				t.Errorf("failed to `News`, key, '%s' is missing", i.Key)
-			} else {
-				t.Errorf("failed to `News`: %v", err)
+	testBackends(t, func(t *testing.T, st Backend) {
+		input := map[string]int{}
+		for i := 0; i < 100; i++ {
+			input[fmt.Sprintf("%d", i)] = i
+		}
+		var args []Item
+		for k, v := range input {
+			args = append(
+				args,
+				Item{k, v},
+			)
+		}
+
+		if err := st.News(args...); err != nil {
+			t.Errorf("failed to `News`: %v", err)
+		}
+
+		for _, i := range args {
+			if exists, err := st.Has(i.Key); !exists || err != nil {
+				if !exists {
+					t.Errorf("failed to `News`, key, '%s' is missing", i.Key)
+				} else {
+					t.Errorf("failed to `News`: %v", err)
+				}
 			}
 		}
-	}
+	})
 }
 
 func TestLevelDBBackendHas(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	key := "showme"
-	if exists, _ := st.Has(key); exists {
-		t.Error("failed to 'Has' in leveldb")
-		return
-	}
+	testBackends(t, func(t *testing.T, st Backend) {
+		key := "showme"
+		if exists, _ := st.Has(key); exists {
+			t.Error("failed to 'Has' in leveldb")
+			return
+		}
 
-	st.New(key, 10)
+		st.New(key, 10)
 
-	if exists, _ := st.Has(key); !exists {
-		t.Error("failed to 'Has' in leveldb")
-		return
-	}
+		if exists, _ := st.Has(key); !exists {
+			t.Error("failed to 'Has' in leveldb")
+			return
+		}
 
-	st.Remove(key)
-	if exists, _ := st.Has(key); exists {
-		t.Error("failed to 'Has' in leveldb")
-		return
-	}
+		st.Remove(key)
+		if exists, _ := st.Has(key); exists {
+			t.Error("failed to 'Has' in leveldb")
+			return
+		}
+	})
 }
 
 func TestLevelDBBackendGetRaw(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
+	testBackends(t, func(t *testing.T, st Backend) {
+		st.New("showme", "input")
 
-	st.New("showme", "input")
-
-	// when record does not exist, it should return errors.StorageRecordDoesNotExist
-	if _, err := st.GetRaw("vacuum"); err != errors.StorageRecordDoesNotExist {
-		t.Errorf("failed to GetRaw: want=%v have=%v", errors.StorageRecordDoesNotExist, err)
-	}
+		// when record does not exist, it should return errors.StorageRecordDoesNotExist
+		if _, err := st.GetRaw("vacuum"); err != errors.StorageRecordDoesNotExist {
+			t.Errorf("failed to GetRaw: want=%v have=%v", errors.StorageRecordDoesNotExist, err)
+		}
+	})
 }
 
 func TestLevelDBBackendSet(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
+	testBackends(t, func(t *testing.T, st Backend) {
+		key := "showme"
+		input := 20
 
-	key := "showme"
-	input := 20
-
-	if err := st.Set(key, input); err == nil {
-		t.Errorf("'Set' must be failed with new key")
-		return
-	}
+		if err := st.Set(key, input); err == nil {
+			t.Errorf("'Set' must be failed with new key")
+			return
+		}
 
-	st.New(key, input)
+		st.New(key, input)
 
-	if err := st.Set(key, input+1); err != nil {
-		t.Errorf("failed to 'Set': %v", err)
-		return
-	}
+		if err := st.Set(key, input+1); err != nil {
+			t.Errorf("failed to 'Set': %v", err)
+			return
+		}
+	})
 }
 
 func TestLevelDBBackendRemove(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
+	testBackends(t, func(t *testing.T, st Backend) {
+		key := "showme"
+		input := 20
 
-	key := "showme"
-	input := 20
-
-	if err := st.Remove(key); err == nil {
-		t.Errorf("'Remove' must be failed with new key")
-		return
-	}
+		if err := st.Remove(key); err == nil {
+			t.Errorf("'Remove' must be failed with new key")
+			return
+		}
 
-	st.New(key, input)
+		st.New(key, input)
 
-	if err := st.Remove(key); err != nil {
-		t.Errorf("failed to 'Rmove': %v", err)
-		return
-	}
-	if exists, _ := st.Has(key); exists {
-		t.Errorf("failed to 'Rmove': key must be removed")
-		return
-	}
+		if err := st.Remove(key); err != nil {
+			t.Errorf("failed to 'Rmove': %v", err)
+			return
+		}
+		if exists, _ := st.Has(key); exists {
+			t.Errorf("failed to 'Rmove': key must be removed")
+			return
+		}
+	})
 }
 
 func TestLevelDBIterator(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	total := 300
-	filteredCount := 289
+	testBackends(t, func(t *testing.T, st Backend) {
+		total := 300
+		filteredCount := 289
 
-	expected := []string{}
-	for i := 0; i < total; i++ {
-		key := fmt.Sprintf("%03d", i)
-		st.New(key, 0)
+		expected := []string{}
+		for i := 0; i < total; i++ {
+			key := fmt.Sprintf("%03d", i)
+			st.New(key, 0)
 
-		if len(expected) < filteredCount {
-			expected = append(expected, key)
+			if len(expected) < filteredCount {
+				expected = append(expected, key)
+			}
 		}
-	}
 
-	var collected []string
-	it, closeFunc := st.GetIterator("", &DefaultListOptions{reverse: false, limit: uint64(filteredCount)})
-	for {
-		v, hasNext := it()
-		if !hasNext {
-			break
-		}
+		var collected []string
+		it, closeFunc := st.GetIterator("", &DefaultListOptions{reverse: false, limit: uint64(filteredCount)})
+		for {
+			v, hasNext := it()
+			if !hasNext {
+				break
+			}
 
-		collected = append(collected, string(v.Key))
-	}
-	closeFunc()
+			collected = append(collected, string(v.Key))
+		}
+		closeFunc()
 
-	if len(collected) != filteredCount {
-		t.Error("failed to fetch the exact number of items")
-	}
+		if len(collected) != filteredCount {
+			t.Error("failed to fetch the exact number of items")
+		}
 
-	if !reflect.DeepEqual(expected, collected) {
-		t.Log("expected", expected)
-		t.Log("collected", collected)
-		t.Error("failed to fetch the exact sequence of items")
-	}
+		if !reflect.DeepEqual(expected, collected) {
+			t.Log("expected", expected)
+			t.Log("collected", collected)
+			t.Error("failed to fetch the exact sequence of items")
+		}
 
-	return
+		return
+	})
 }
 
 func TestLevelDBIteratorSeek(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	total := 300
-
-	expected1 := []string{}
-	for i := 0; i < total; i++ {
-		key := fmt.Sprintf("%03d", i)
-		st.New(key, 0)
-		expected1 = append(expected1, key)
-	}
-	expected2 := make([]string, len(expected1))
-	copy(expected2, expected1)
-	sort.Sort(sort.Reverse(sort.StringSlice(expected2)))
-
-	type test struct {
-		name     string
-		cursor   int
-		reverse  bool
-		expected []string
-	}
-
-	tests := []test{
-		{
-			name:     "reverse=false",
-			cursor:   100,
-			reverse:  false,
-			expected: expected1[100+1:],
-		},
-		{
-			name:     "reverse=true",
-			cursor:   100,
-			reverse:  true,
-			expected: expected2[300-100:],
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			expected := tt.expected
-			var collected []string
-			it, closeFunc := st.GetIterator("", &DefaultListOptions{
-				reverse: tt.reverse,
-				cursor:  []byte(fmt.Sprintf("%03d", tt.cursor))})
-			for {
-				v, hasNext := it()
-				if !hasNext {
-					break
-				}
+	testBackends(t, func(t *testing.T, st Backend) {
+		total := 300
+
+		expected1 := []string{}
+		for i := 0; i < total; i++ {
+			key := fmt.Sprintf("%03d", i)
+			st.New(key, 0)
+			expected1 = append(expected1, key)
+		}
+		expected2 := make([]string, len(expected1))
+		copy(expected2, expected1)
+		sort.Sort(sort.Reverse(sort.StringSlice(expected2)))
+
+		type test struct {
+			name     string
+			cursor   int
+			reverse  bool
+			expected []string
+		}
 
-				collected = append(collected, string(v.Key))
-			}
-			closeFunc()
+		tests := []test{
+			{
+				name:     "reverse=false",
+				cursor:   100,
+				reverse:  false,
+				expected: expected1[100+1:],
+			},
+			{
+				name:     "reverse=true",
+				cursor:   100,
+				reverse:  true,
+				expected: expected2[300-100:],
+			},
+		}
 
-			if !reflect.DeepEqual(expected, collected) {
-				t.Log("expected", expected)
-				t.Log("collected", collected)
-				t.Error("failed to fetch the exact sequence of items")
-			}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				expected := tt.expected
+				var collected []string
+				it, closeFunc := st.GetIterator("", &DefaultListOptions{
+					reverse: tt.reverse,
+					cursor:  []byte(fmt.Sprintf("%03d", tt.cursor))})
+				for {
+					v, hasNext := it()
+					if !hasNext {
+						break
+					}
+
+					collected = append(collected, string(v.Key))
+				}
+				closeFunc()
 
-		})
-	}
+				if !reflect.DeepEqual(expected, collected) {
+					t.Log("expected", expected)
+					t.Log("collected", collected)
+					t.Error("failed to fetch the exact sequence of items")
+				}
 
-	return
+			})
+		}
+
+		return
+	})
 }
 
 func TestLevelDBIteratorLimit(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
+	testBackends(t, func(t *testing.T, st Backend) {
+		total := 300
 
-	total := 300
+		expected := []string{}
+		for i := 0; i < total; i++ {
+			key := fmt.Sprintf("%03d", i)
+			st.New(key, 0)
 
-	expected := []string{}
-	for i := 0; i < total; i++ {
-		key := fmt.Sprintf("%03d", i)
-		st.New(key, 0)
+			expected = append(expected, key)
+		}
 
-		expected = append(expected, key)
-	}
+		expected = expected[:100]
 
-	expected = expected[:100]
+		var collected []string
+		it, closeFunc := st.GetIterator("", &DefaultListOptions{reverse: false, limit: 100})
+		for {
+			v, hasNext := it()
+			if !hasNext {
+				break
+			}
 
-	var collected []string
-	it, closeFunc := st.GetIterator("", &DefaultListOptions{reverse: false, limit: 100})
-	for {
-		v, hasNext := it()
-		if !hasNext {
-			break
+			collected = append(collected, string(v.Key))
 		}
+		closeFunc()
 
-		collected = append(collected, string(v.Key))
-	}
-	closeFunc()
-
-	if !reflect.DeepEqual(expected, collected) {
-		t.Log(expected)
-		t.Log(collected)
-		t.Error("failed to fetch the exact sequence of items")
-	}
+		if !reflect.DeepEqual(expected, collected) {
+			t.Log(expected)
+			t.Log(collected)
+			t.Error("failed to fetch the exact sequence of items")
+		}
 
-	return
+		return
+	})
 }
 
 func TestLevelDBIteratorReverseOrder(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
+	testBackends(t, func(t *testing.T, st Backend) {
+		total := 30
 
-	total := 30
+		expected := []string{}
+		for i := 0; i < total; i++ {
+			key := fmt.Sprintf("%03d", i)
+			st.New(key, 0)
 
-	expected := []string{}
-	for i := 0; i < total; i++ {
-		key := fmt.Sprintf("%03d", i)
-		st.New(key, 0)
+			expected = append(expected, key)
+		}
 
-		expected = append(expected, key)
-	}
+		var collected []string
+		it, closeFunc := st.GetIterator("", &DefaultListOptions{reverse: true})
+		for {
+			v, hasNext := it()
+			if !hasNext {
+				break
+			}
 
-	var collected []string
-	it, closeFunc := st.GetIterator("", &DefaultListOptions{reverse: true})
-	for {
-		v, hasNext := it()
-		if !hasNext {
-			break
+			collected = append(collected, string(v.Key))
 		}
+		closeFunc()
 
-		collected = append(collected, string(v.Key))
-	}
-	closeFunc()
-
-	for i, a := range expected {
-		if a != collected[len(collected)-1-i] {
-			t.Error("failed to reverse `GetIterator`")
+		for i, a := range expected {
+			if a != collected[len(collected)-1-i] {
+				t.Error("failed to reverse `GetIterator`")
+			}
 		}
-	}
 
-	return
+		return
+	})
 }
 
 func TestLevelDBBackendTransactionNew(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	ts, _ := st.OpenTransaction()
+	testBackends(t, func(t *testing.T, st Backend) {
+		ts, _ := st.OpenTransaction()
+
+		key0 := common.GetUniqueIDFromUUID()
+		value0 := "findme"
+		if err := ts.New(key0, value0); err != nil {
+			t.Error(err)
+			return
+		}
 
-	key0 := common.GetUniqueIDFromUUID()
-	value0 := "findme"
-	if err := ts.New(key0, value0); err != nil {
-		t.Error(err)
-		return
-	}
+		var returned string
+		if err := ts.Get(key0, &returned); err != nil {
+			t.Error(err)
+			return
+		}
+		if returned != value0 {
+			t.Errorf("wrong value returned; '%s' != '%s'", value0, returned)
+			return
+		}
 
-	var returned string
-	if err := ts.Get(key0, &returned); err != nil {
-		t.Error(err)
-		return
-	}
-	if returned != value0 {
-		t.Errorf("wrong value returned; '%s' != '%s'", value0, returned)
-		return
-	}
+		ts.Commit()
 
-	ts.Commit()
+		var returnedAgain string
+		if err := st.Get(key0, &returnedAgain); err != nil {
+			t.Errorf("failed to get after 'Commit()': %v", err)
+			return
+		}
+		if returnedAgain != value0 {
+			t.Errorf("wrong value returned after 'Commit()'; '%s' != '%s'", value0, returnedAgain)
+			return
+		}
 
-	var returnedAgain string
-	if err := st.Get(key0, &returnedAgain); err != nil {
-		t.Errorf("failed to get after 'Commit()': %v", err)
 		return
-	}
-	if returnedAgain != value0 {
-		t.Errorf("wrong value returned after 'Commit()'; '%s' != '%s'", value0, returnedAgain)
-		return
-	}
-
-	return
+	})
 }
 
 func TestLevelDBBackendTransactionDiscard(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	ts, _ := st.OpenTransaction()
+	testBackends(t, func(t *testing.T, st Backend) {
+		ts, _ := st.OpenTransaction()
+
+		key0 := common.GetUniqueIDFromUUID()
+		value0 := "findme"
+		if err := ts.New(key0, value0); err != nil {
+			t.Error(err)
+			return
+		}
 
-	key0 := common.GetUniqueIDFromUUID()
-	value0 := "findme"
-	if err := ts.New(key0, value0); err != nil {
-		t.Error(err)
-		return
-	}
+		var returned string
+		if err := ts.Get(key0, &returned); err != nil {
+			t.Error(err)
+			return
+		}
+		if returned != value0 {
+			t.Errorf("wrong value returned; '%s' != '%s'", value0, returned)
+			return
+		}
 
-	var returned string
-	if err := ts.Get(key0, &returned); err != nil {
-		t.Error(err)
-		return
-	}
-	if returned != value0 {
-		t.Errorf("wrong value returned; '%s' != '%s'", value0, returned)
-		return
-	}
+		ts.Discard()
 
-	ts.Discard()
+		var returnedAgain string
+		if err := st.Get(key0, &returnedAgain); err == nil {
+			t.Errorf("value is stored after 'Discard()': %v", err)
+			return
+		}
 
-	var returnedAgain string
-	if err := st.Get(key0, &returnedAgain); err == nil {
-		t.Errorf("value is stored after 'Discard()': %v", err)
 		return
-	}
-
-	return
+	})
 }
 
 //TODO(anarcher): SubTests
 func TestLevelDBWalk(t *testing.T) {
-	st := NewTestStorage()
-	defer st.Close()
-
-	kv := map[string]string{
-		"test-1": "1",
-		"test-2": "2",
-		"test-3": "3",
-		"test-4": "4",
-		"test-5": "5",
-	}
-	for k, v := range kv {
-		if err := st.New(k, v); err != nil {
-			t.Fatal(err)
+	testBackends(t, func(t *testing.T, st Backend) {
+		kv := map[string]string{
+			"test-1": "1",
+			"test-2": "2",
+			"test-3": "3",
+			"test-4": "4",
+			"test-5": "5",
+		}
+		for k, v := range kv {
+			if err := st.New(k, v); err != nil {
+				t.Fatal(err)
+			}
 		}
-	}
 
-	if err := st.New("notest-1", "notest-1"); err != nil {
-		t.Fatal(err)
-	}
+		if err := st.New("notest-1", "notest-1"); err != nil {
+			t.Fatal(err)
+		}
 
-	var (
-		walkedKeys []string
-		cnt        int
-	)
+		var (
+			walkedKeys []string
+			cnt        int
+		)
 
-	walkOption := NewWalkOption("test-1", 10, false)
-	err := st.Walk("test-", walkOption, func(k, v []byte) (bool, error) {
-		cnt++
-		walkedKeys = append(walkedKeys, string(k))
-		return true, nil
-	})
-	if err != nil {
-		t.Fatal(err)
-	}
+		walkOption := NewWalkOption("test-1", 10, false)
+		err := st.Walk("test-", walkOption, func(k, v []byte) (bool, error) {
+			cnt++
+			walkedKeys = append(walkedKeys, string(k))
+			return true, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	if cnt != len(kv) {
-		t.Errorf("want: %v have: %v", len(kv), cnt)
-	}
+		if cnt != len(kv) {
+			t.Errorf("want: %v have: %v", len(kv), cnt)
+		}
 
-	var keys []string
-	for k, _ := range kv {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
+		var keys []string
+		for k, _ := range kv {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
 
-	require.Equal(t, keys, walkedKeys)
+		require.Equal(t, keys, walkedKeys)
 
+	})
 }
@@ -10,11 +10,11 @@ import (
 )
 
 type StateDB struct {
-	levelDB     *LevelDBBackend
+	levelDB     Backend
 	changedkeys map[string]struct{}
 }
 
-func NewStateDB(st *LevelDBBackend) *StateDB {
+func NewStateDB(st Backend) *StateDB {
 	db := &StateDB{
 		levelDB: st,
 		// If we need thread safety, we should use sync.Map insteads map
@@ -0,0 +1,278 @@
+package statedb
+
+import (
+	"encoding/json"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/trieproof"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/storage/statedb/trie"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// EncodeRoot returns the base58 encoded root of trie, which is stored in
+// `block.Header.StateRoot`.
+func EncodeRoot(root common.Hash) string {
+	return base58.Encode(root.Bytes())
+}
+
+// DecodeRoot returns the root of trie from `block.Header.StateRoot`; the
+// empty string is the empty trie.
+var DecodeRoot = trieproof.DecodeRoot
+
+// Changes is the state changed by a block, which is committed into the state
+// trie by `Commit`; the changed records are read from storage by their keys,
+// so the changes can be committed with the batch storage, which can not
+// iterate the new records.
+type Changes struct {
+	Accounts []string // addresses of accounts
+
+	// AccountData is the keys of the data entries by address; the entries
+	// are committed into the storage trie of account.
+	AccountData map[string][]string
+
+	// UnfreezingRequests is the hashes of `block.BlockOperation`s of the
+	// unfreezing requests; they are committed into the storage trie of the
+	// frozen account, because the unfreezing is validated with them.
+	UnfreezingRequests []string
+
+	// ValidatorSetChanges is the storage keys of `block.ValidatorSetChange`s;
+	// they are committed under their keys, which are not addresses.
+	ValidatorSetChanges []string
+
+	// MergedAccounts is the addresses of the merged accounts; their
+	// `block.BlockAccountMerged`s are committed under their keys.
+	MergedAccounts []string
+}
+
+// AddAccountData adds the key of data entry of the account.
+func (c *Changes) AddAccountData(address, key string) {
+	if c.AccountData == nil {
+		c.AccountData = map[string][]string{}
+	}
+	c.AccountData[address] = append(c.AccountData[address], key)
+}
+
+// CommitAccounts commits the given accounts into the state trie of `root`
+// and returns the new root; see `Commit`.
+func CommitAccounts(st storage.Backend, root string, addresses []string) (newRoot string, err error) {
+	return Commit(st, root, Changes{Accounts: addresses})
+}
+
+// Commit commits the changes into the state trie of `root` and returns the
+// new root. The whole record of account is committed, and the account, which
+// does not exist in storage, is deleted from the trie.
+//
+// If `root` is empty, the trie is made from all the stored state with the
+// changes; the genesis block and the blocks before `block.BlockVersionV2` do
+// not have the state root.
+func Commit(st storage.Backend, root string, changes Changes) (newRoot string, err error) {
+	stateDB := New(DecodeRoot(root), trie.NewEthDatabase(st))
+
+	if len(root) < 1 {
+		if err = addStoredChanges(st, &changes); err != nil {
+			return
+		}
+	}
+
+	for _, address := range changes.Accounts {
+		var exists bool
+		if exists, err = block.ExistsBlockAccount(st, address); err != nil {
+			return
+		} else if !exists {
+			if err = stateDB.DeleteAccount(address); err != nil {
+				return
+			}
+			continue
+		}
+
+		var ba *block.BlockAccount
+		if ba, err = block.GetBlockAccount(st, address); err != nil {
+			return
+		}
+
+		stateDB.SetAccount(*ba)
+	}
+
+	for address, keys := range changes.AccountData {
+		if !stateDB.ExistAccount(address) {
+			continue
+		}
+
+		for _, key := range keys {
+			var value common.Hash
+			var exists bool
+			if exists, err = block.ExistsBlockAccountData(st, address, key); err != nil {
+				return
+			} else if exists {
+				var d *block.BlockAccountData
+				if d, err = block.GetBlockAccountData(st, address, key); err != nil {
+					return
+				}
+				value = common.BytesToHash(common.MakeHash(common.MustMarshalJSON(d)))
+			}
+
+			stateDB.SetState(address, accountDataStateKey(key), value)
+		}
+	}
+
+	for _, hash := range changes.UnfreezingRequests {
+		var bo block.BlockOperation
+		if bo, err = block.GetBlockOperation(st, hash); err != nil {
+			return
+		}
+		if !stateDB.ExistAccount(bo.Source) {
+			continue
+		}
+
+		stateDB.SetState(
+			bo.Source,
+			unfreezingRequestStateKey(hash),
+			common.BytesToHash(common.MakeHash(common.MustMarshalJSON(bo))),
+		)
+	}
+
+	for _, key := range changes.ValidatorSetChanges {
+		var c block.ValidatorSetChange
+		if err = st.Get(key, &c); err != nil {
+			return
+		}
+		if err = stateDB.trie.TryUpdate([]byte(key), common.MakeHash(common.MustMarshalJSON(c))); err != nil {
+			return
+		}
+	}
+
+	for _, address := range changes.MergedAccounts {
+		var m *block.BlockAccountMerged
+		if m, err = block.GetBlockAccountMerged(st, address); err != nil {
+			return
+		}
+		key := block.GetBlockAccountMergedKey(address)
+		if err = stateDB.trie.TryUpdate([]byte(key), common.MakeHash(common.MustMarshalJSON(m))); err != nil {
+			return
+		}
+	}
+
+	var hash common.Hash
+	if hash, err = stateDB.Commit(); err != nil {
+		return
+	}
+
+	newRoot = EncodeRoot(hash)
+	return
+}
+
+func accountDataStateKey(key string) common.Hash {
+	return common.BytesToHash(common.MakeHash([]byte("data-" + key)))
+}
+
+func unfreezingRequestStateKey(hash string) common.Hash {
+	return common.BytesToHash(common.MakeHash([]byte("unfreezing-request-" + hash)))
+}
+
+// addStoredChanges adds the whole stored state into the changes.
+func addStoredChanges(st storage.Backend, changes *Changes) (err error) {
+	var addresses []string
+	iterFunc, closeFunc := block.GetBlockAccountAddressesByCreated(st, nil)
+	for {
+		address, hasNext, _ := iterFunc()
+		if !hasNext {
+			break
+		}
+		addresses = append(addresses, address)
+	}
+	closeFunc()
+
+	for _, address := range addresses {
+		changes.Accounts = append(changes.Accounts, address)
+
+		iterFunc, closeFunc := block.GetBlockAccountDataByAddress(st, address, nil)
+		for {
+			d, hasNext, _ := iterFunc()
+			if !hasNext {
+				break
+			}
+			changes.AddAccountData(address, d.Key)
+		}
+		closeFunc()
+
+		iterOps, closeOps := block.GetBlockOperationsBySource(st, address, nil)
+		for {
+			bo, hasNext, _ := iterOps()
+			if !hasNext {
+				break
+			}
+			if bo.Type == operation.TypeUnfreezingRequest {
+				changes.UnfreezingRequests = append(changes.UnfreezingRequests, bo.Hash)
+			}
+		}
+		closeOps()
+	}
+
+	iterChanges, closeChanges := block.GetValidatorSetChanges(st, nil)
+	for {
+		c, hasNext, _ := iterChanges()
+		if !hasNext {
+			break
+		}
+		changes.ValidatorSetChanges = append(changes.ValidatorSetChanges, block.GetValidatorSetChangeKey(c.Height, c.Hash))
+	}
+	closeChanges()
+
+	iterMerged, closeMerged := block.GetBlockAccountsMerged(st, nil)
+	for {
+		m, hasNext, _ := iterMerged()
+		if !hasNext {
+			break
+		}
+		changes.MergedAccounts = append(changes.MergedAccounts, m.Address)
+	}
+	closeMerged()
+
+	return
+}
+
+// GetAccountProof returns the account in the state trie of `root` and the
+// proof nodes, which can be checked by `VerifyAccountProof`.
+func GetAccountProof(st storage.Backend, root string, address string) (ba block.BlockAccount, nodes [][]byte, err error) {
+	if len(root) < 1 {
+		err = errors.AccountProofNotAvailable
+		return
+	}
+
+	t := trie.NewTrie(DecodeRoot(root), trie.NewEthDatabase(st))
+
+	var value []byte
+	if value, err = t.TryGet([]byte(address)); err != nil {
+		return
+	} else if len(value) < 1 {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	if err = json.Unmarshal(value, &ba); err != nil {
+		return
+	}
+
+	nodes, err = t.MakeProof([]byte(address))
+	return
+}
+
+// VerifyAccountProof returns the account from the proof nodes, which are
+// checked by the state root.
+func VerifyAccountProof(root string, address string, nodes [][]byte) (ba block.BlockAccount, err error) {
+	var value []byte
+	if value, err = trieproof.VerifyProof(DecodeRoot(root), []byte(address), nodes); err != nil {
+		return
+	} else if len(value) < 1 {
+		err = errors.BlockAccountDoesNotExists
+		return
+	}
+
+	err = json.Unmarshal(value, &ba)
+	return
+}
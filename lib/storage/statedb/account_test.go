@@ -0,0 +1,127 @@
+package statedb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/block"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/storage"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestCommitAccounts(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	ba0 := block.TestMakeBlockAccount()
+	ba0.MustSave(st)
+	ba1 := block.TestMakeBlockAccount()
+	ba1.MustSave(st)
+
+	// with empty root, all the accounts are committed
+	root, err := CommitAccounts(st, "", nil)
+	require.NoError(t, err)
+
+	for _, ba := range []*block.BlockAccount{ba0, ba1} {
+		account, proof, err := GetAccountProof(st, root, ba.Address)
+		require.NoError(t, err)
+		require.Equal(t, ba.Balance, account.Balance)
+		require.Equal(t, ba.SequenceID, account.SequenceID)
+
+		verified, err := VerifyAccountProof(root, ba.Address, proof)
+		require.NoError(t, err)
+		require.Equal(t, account, verified)
+	}
+
+	// the removed account is deleted from trie
+	require.NoError(t, ba1.Remove(st))
+	ba0.SequenceID++
+	ba0.MustSave(st)
+
+	newRoot, err := CommitAccounts(st, root, []string{ba0.Address, ba1.Address})
+	require.NoError(t, err)
+	require.NotEqual(t, root, newRoot)
+
+	account, _, err := GetAccountProof(st, newRoot, ba0.Address)
+	require.NoError(t, err)
+	require.Equal(t, ba0.SequenceID, account.SequenceID)
+
+	_, _, err = GetAccountProof(st, newRoot, ba1.Address)
+	require.Equal(t, errors.BlockAccountDoesNotExists, err)
+
+	// the previous root is kept
+	_, _, err = GetAccountProof(st, root, ba1.Address)
+	require.NoError(t, err)
+
+	// same accounts make same root
+	sameRoot, err := CommitAccounts(st, "", nil)
+	require.NoError(t, err)
+	require.Equal(t, newRoot, sameRoot)
+}
+
+func TestCommitChanges(t *testing.T) {
+	st := storage.NewTestStorage()
+	defer st.Close()
+
+	ba := block.TestMakeBlockAccount()
+	ba.MustSave(st)
+
+	root, err := CommitAccounts(st, "", nil)
+	require.NoError(t, err)
+
+	{ // the whole record of account is committed
+		changed := *ba
+		changed.Signers = []operation.Signer{{Address: ba.Address, Weight: 1}}
+		changed.MustSave(st)
+
+		newRoot, err := CommitAccounts(st, root, []string{ba.Address})
+		require.NoError(t, err)
+		require.NotEqual(t, root, newRoot)
+
+		account, _, err := GetAccountProof(st, newRoot, ba.Address)
+		require.NoError(t, err)
+		require.Equal(t, changed.Signers, account.Signers)
+
+		ba.MustSave(st)
+		sameRoot, err := CommitAccounts(st, newRoot, []string{ba.Address})
+		require.NoError(t, err)
+		require.Equal(t, root, sameRoot)
+	}
+
+	{ // account data
+		require.NoError(t, block.NewBlockAccountData(ba.Address, "key", "value").Save(st))
+
+		var changes Changes
+		changes.AddAccountData(ba.Address, "key")
+		dataRoot, err := Commit(st, root, changes)
+		require.NoError(t, err)
+		require.NotEqual(t, root, dataRoot)
+
+		// same with the trie from empty root
+		sameRoot, err := Commit(st, "", Changes{})
+		require.NoError(t, err)
+		require.Equal(t, dataRoot, sameRoot)
+
+		// removed entry
+		require.NoError(t, block.RemoveBlockAccountData(st, ba.Address, "key"))
+		removedRoot, err := Commit(st, dataRoot, changes)
+		require.NoError(t, err)
+		require.Equal(t, root, removedRoot)
+	}
+
+	{ // validator set change
+		c := block.NewValidatorSetChange("hash", 10, []string{ba.Address}, []string{"https://localhost:12345"}, nil)
+		require.NoError(t, c.Save(st))
+
+		changes := Changes{ValidatorSetChanges: []string{block.GetValidatorSetChangeKey(c.Height, c.Hash)}}
+		changedRoot, err := Commit(st, root, changes)
+		require.NoError(t, err)
+		require.NotEqual(t, root, changedRoot)
+
+		sameRoot, err := Commit(st, "", Changes{})
+		require.NoError(t, err)
+		require.Equal(t, changedRoot, sameRoot)
+	}
+}
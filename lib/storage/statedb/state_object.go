@@ -112,6 +112,24 @@ func (so *stateObject) SetState(key, value common.Hash) {
 
 }
 
+func (so *stateObject) SetAccount(ba block.BlockAccount) {
+	ba.CodeHash = so.data.CodeHash
+	ba.RootHash = so.data.RootHash
+	so.data = ba
+	if so.onDirty != nil {
+		so.onDirty(so.Address())
+		so.onDirty = nil
+	}
+}
+
+func (so *stateObject) SetBalance(amount common.Amount) {
+	so.data.Balance = amount
+	if so.onDirty != nil {
+		so.onDirty(so.Address())
+		so.onDirty = nil
+	}
+}
+
 func (so *stateObject) AddBalance(amount common.Amount) error {
 	val, err := so.Balance().Add(amount)
 	if err != nil {
@@ -177,6 +195,7 @@ func (so *stateObject) updateTrie() {
 	for key, value := range so.dirtyStorage {
 		delete(so.dirtyStorage, key)
 		if (value == common.Hash{}) {
+			so.storageTrie.TryDelete(key[:])
 			continue
 		}
 		so.storageTrie.TryUpdate(key[:], value[:])
@@ -203,32 +222,7 @@ func (so *stateObject) CommitDB(root common.Hash) (err error) {
 }
 
 func (so *stateObject) Save() (err error) {
-	st := so.db.BackEnd()
-	key := block.GetBlockAccountKey(so.Address())
-
-	var exists bool
-	exists, err = st.Has(key)
-	if err != nil {
-		return
-	}
-
-	if exists {
-		err = st.Set(key, so.data)
-	} else {
-		// TODO consider to use, [`Transaction`](https://godoc.org/github.com/syndtr/goleveldb/leveldb#DB.OpenTransaction)
-		err = st.New(key, so.data)
-		createdKey := block.GetBlockAccountCreatedKey(common.GetUniqueIDFromUUID())
-		err = st.New(createdKey, so.Address())
-	}
-
-	bac := block.BlockAccountSequenceID{
-		SequenceID: so.data.SequenceID,
-		Address:    so.Address(),
-		Balance:    so.data.Balance,
-	}
-	err = bac.Save(st)
-
-	return
+	return so.data.Save(so.db.BackEnd())
 }
 
 /*
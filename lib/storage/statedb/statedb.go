@@ -97,6 +97,13 @@ func (stateDB *StateDB) SetSequenceID(addr string, sequenceID uint64) {
 	}
 }
 
+func (stateDB *StateDB) SetBalance(addr string, amount common.Amount) {
+	stateObject := stateDB.GetOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.SetBalance(amount)
+	}
+}
+
 func (stateDB *StateDB) AddBalance(addr string, amount common.Amount) {
 	stateObject := stateDB.GetOrNewStateObject(addr)
 	if stateObject != nil {
@@ -139,6 +146,24 @@ func (stateDB *StateDB) SetState(addr string, key, value common.Hash) {
 	}
 }
 
+// SetAccount sets the record of account; the roots of storage and code are
+// kept by the state object.
+func (stateDB *StateDB) SetAccount(ba block.BlockAccount) {
+	stateObject := stateDB.GetOrNewStateObject(ba.Address)
+	if stateObject != nil {
+		stateObject.SetAccount(ba)
+	}
+}
+
+// DeleteAccount removes the account from the trie.
+func (stateDB *StateDB) DeleteAccount(addr string) error {
+	delete(stateDB.stateObjects, addr)
+	delete(stateDB.stateObjectsDirty, addr)
+	delete(stateDB.stateObjectsCommitDirty, addr)
+
+	return stateDB.trie.TryDelete([]byte(addr))
+}
+
 func (stateDB *StateDB) getStateObject(addr string) (stateObject *stateObject) {
 	if obj := stateDB.stateObjects[addr]; obj != nil {
 		return obj
@@ -208,3 +233,22 @@ func (stateDB *StateDB) CommitDB(root common.Hash) (err error) {
 	}
 	return stateDB.trie.CommitDB(root)
 }
+
+// Commit commits the trie and writes the trie nodes and the storage trie
+// nodes of accounts into the storage. Unlike `CommitDB`, the accounts are not
+// saved into the storage, they are already saved by the operations.
+func (stateDB *StateDB) Commit() (root common.Hash, err error) {
+	if root, err = stateDB.CommitTrie(); err != nil {
+		return
+	}
+	for addr := range stateDB.stateObjectsCommitDirty {
+		stateObject := stateDB.stateObjects[addr]
+		if err = stateObject.storageTrie.CommitDB(stateObject.data.RootHash); err != nil {
+			return
+		}
+		delete(stateDB.stateObjectsCommitDirty, addr)
+	}
+
+	err = stateDB.trie.CommitDB(root)
+	return
+}
@@ -1,6 +1,8 @@
 package trie
 
 import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
 	"boscoin.io/sebak/lib/storage"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -8,26 +10,32 @@ import (
 )
 
 type EthDatabase struct {
-	ldbBackend *storage.LevelDBBackend
+	ldbBackend storage.Backend
 	quitLock   sync.Mutex // Mutex protecting the quit channel access
 }
 
-func NewEthDatabase(ldb *storage.LevelDBBackend) *EthDatabase {
+func NewEthDatabase(ldb storage.Backend) *EthDatabase {
 	return &EthDatabase{
 		ldbBackend: ldb,
 	}
 }
 
+// getKey prefixes the key of trie node, so the nodes are not mixed with the
+// other items of storage.
+func getKey(key []byte) []byte {
+	return append([]byte(common.StateTriePrefixHash), key...)
+}
+
 func (db *EthDatabase) Put(key []byte, value []byte) error {
-	return db.ldbBackend.Core.Put(key, value, nil)
+	return db.ldbBackend.PutRaw(string(getKey(key)), value)
 }
 
 func (db *EthDatabase) Has(key []byte) (bool, error) {
-	return db.ldbBackend.Core.Has(key, nil)
+	return db.ldbBackend.Has(string(getKey(key)))
 }
 
 func (db *EthDatabase) Get(key []byte) ([]byte, error) {
-	dat, err := db.ldbBackend.Core.Get(key, nil)
+	dat, err := db.ldbBackend.GetRaw(string(getKey(key)))
 	if err != nil {
 		return nil, err
 	}
@@ -35,43 +43,57 @@ func (db *EthDatabase) Get(key []byte) ([]byte, error) {
 }
 
 func (db *EthDatabase) Delete(key []byte) error {
-	return db.ldbBackend.Core.Delete(key, nil)
+	return deleteKey(db.ldbBackend, getKey(key))
 }
 
 func (db *EthDatabase) Close() {
 	db.quitLock.Lock()
 	defer db.quitLock.Unlock()
-	db.ldbBackend.DB.Close()
+	db.ldbBackend.Close()
+}
+
+// deleteKey ignores the missing key like `leveldb.DB.Delete`.
+func deleteKey(st storage.Backend, key []byte) error {
+	if err := st.Remove(string(key)); err != nil && err != errors.StorageRecordDoesNotExist {
+		return err
+	}
+	return nil
 }
 
 func (db *EthDatabase) NewBatch() ethdb.Batch {
 	return &ldbBatch{db: db.ldbBackend, b: new(leveldb.Batch)}
 }
 
-func (db *EthDatabase) BackEnd() *storage.LevelDBBackend {
+func (db *EthDatabase) BackEnd() storage.Backend {
 	return db.ldbBackend
 }
 
 type ldbBatch struct {
-	db   *storage.LevelDBBackend
+	db   storage.Backend
 	b    *leveldb.Batch
 	size int
 }
 
 func (b *ldbBatch) Put(key, value []byte) error {
-	b.b.Put(key, value)
+	b.b.Put(getKey(key), value)
 	b.size += len(value)
 	return nil
 }
 
 func (b *ldbBatch) Delete(key []byte) error {
-	b.b.Delete(key)
+	b.b.Delete(getKey(key))
 	b.size += 1
 	return nil
 }
 
+// Write puts the items one by one, so if the storage is batch, the items are
+// committed with the batch.
 func (b *ldbBatch) Write() error {
-	return b.db.Core.Write(b.b, nil)
+	r := &batchReplay{st: b.db}
+	if err := b.b.Replay(r); err != nil {
+		return err
+	}
+	return r.err
 }
 
 func (b *ldbBatch) ValueSize() int {
@@ -82,3 +104,20 @@ func (b *ldbBatch) Reset() {
 	b.b.Reset()
 	b.size = 0
 }
+
+type batchReplay struct {
+	st  storage.Backend
+	err error
+}
+
+func (r *batchReplay) Put(key, value []byte) {
+	if r.err == nil {
+		r.err = r.st.PutRaw(string(key), value)
+	}
+}
+
+func (r *batchReplay) Delete(key []byte) {
+	if r.err == nil {
+		r.err = deleteKey(r.st, key)
+	}
+}
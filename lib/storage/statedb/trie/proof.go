@@ -0,0 +1,26 @@
+package trie
+
+import (
+	ethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// MakeProof returns the encoded trie nodes on the path from the root to the
+// key. If the key is not in the trie, the nodes prove the absence of key. The
+// nodes are verified by `trieproof.VerifyProof`.
+func (t *Trie) MakeProof(key []byte) (nodes [][]byte, err error) {
+	p := &proofNodes{}
+	if err = t.Prove(key, 0, p); err != nil {
+		return
+	}
+
+	return p.nodes, nil
+}
+
+type proofNodes struct {
+	nodes [][]byte
+}
+
+func (p *proofNodes) Put(key []byte, value []byte) error {
+	p.nodes = append(p.nodes, ethcommon.CopyBytes(value))
+	return nil
+}
@@ -4,7 +4,7 @@ import (
 	"testing"
 )
 
-func newTestStateDB(t *testing.T) (*LevelDBBackend, *LevelDBBackend, *StateDB) {
+func newTestStateDB(t *testing.T) (Backend, Backend, *StateDB) {
 	st := NewTestStorage()
 	ts, err := st.OpenTransaction()
 	if err != nil {
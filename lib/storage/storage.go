@@ -10,6 +10,32 @@ import (
 var SupportedStorageType []string = []string{
 	"memory",
 	"file",
+	"bolt",
+}
+
+// Backend is the interface of the storage; `LevelDBBackend` and
+// `BoltDBBackend` implement it.
+type Backend interface {
+	Has(string) (bool, error)
+	GetRaw(string) ([]byte, error)
+	Get(string, interface{}) error
+	New(string, interface{}) error
+	News(...Item) error
+	Set(string, interface{}) error
+	Sets(...Item) error
+	// PutRaw stores the raw value without serialization whether the key
+	// exists or not.
+	PutRaw(string, []byte) error
+	Remove(string) error
+	GetIterator(string, ListOptions) (func() (IterItem, bool), func())
+	Walk(string, *WalkOption, WalkFunc) error
+	OpenTransaction() (Backend, error)
+	OpenBatch() (Backend, error)
+	OpenSnapshot() (Backend, error)
+	Discard() error
+	Commit() error
+	Release() error
+	Close() error
 }
 
 type IterItem struct {
@@ -40,11 +66,23 @@ type Item struct {
 type Model struct {
 }
 
-func NewStorage(config *Config) (st *LevelDBBackend, err error) {
-	st = &LevelDBBackend{}
-	if err = st.Init(config); err != nil {
+// NewStorage opens the storage by the scheme of config; "memory" and "file"
+// are `LevelDBBackend`, "bolt" is `BoltDBBackend`.
+func NewStorage(config *Config) (st Backend, err error) {
+	if config.Scheme == "bolt" {
+		bst := &BoltDBBackend{}
+		if err = bst.Init(config); err != nil {
+			return
+		}
+		st = bst
+		return
+	}
+
+	lst := &LevelDBBackend{}
+	if err = lst.Init(config); err != nil {
 		return
 	}
+	st = lst
 
 	return
 }
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/errors"
+)
+
+func newTestBoltStorage(t *testing.T) (*BoltDBBackend, func()) {
+	dir, err := ioutil.TempDir("", "sebak-storage")
+	require.NoError(t, err)
+
+	config, err := NewConfigFromString("bolt://" + filepath.Join(dir, "db"))
+	require.NoError(t, err)
+
+	st := &BoltDBBackend{}
+	require.NoError(t, st.Init(config))
+
+	return st, func() {
+		st.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// testBackends runs the same test with the every storage backend.
+func testBackends(t *testing.T, f func(*testing.T, Backend)) {
+	t.Run("leveldb", func(t *testing.T) {
+		st := NewTestStorage()
+		defer st.Close()
+
+		f(t, st)
+	})
+
+	t.Run("bolt", func(t *testing.T) {
+		st, closeFunc := newTestBoltStorage(t)
+		defer closeFunc()
+
+		f(t, st)
+	})
+}
+
+func TestNewStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sebak-storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	{
+		config, err := NewConfigFromString("bolt://" + filepath.Join(dir, "bolt"))
+		require.NoError(t, err)
+
+		st, err := NewStorage(config)
+		require.NoError(t, err)
+		defer st.Close()
+
+		_, ok := st.(*BoltDBBackend)
+		require.True(t, ok)
+	}
+
+	{
+		config, err := NewConfigFromString("file://" + filepath.Join(dir, "leveldb"))
+		require.NoError(t, err)
+
+		st, err := NewStorage(config)
+		require.NoError(t, err)
+		defer st.Close()
+
+		_, ok := st.(*LevelDBBackend)
+		require.True(t, ok)
+	}
+
+	{
+		_, err := NewConfigFromString("unknown://" + filepath.Join(dir, "unknown"))
+		require.Error(t, err)
+	}
+}
+
+func TestBackendSnapshot(t *testing.T) {
+	testBackends(t, func(t *testing.T, st Backend) {
+		require.NoError(t, st.New("showme", "findme"))
+
+		snapshot, err := st.OpenSnapshot()
+		require.NoError(t, err)
+		defer snapshot.Release()
+
+		require.NoError(t, st.Set("showme", "changed"))
+		require.NoError(t, st.New("killme", "new"))
+
+		var fetched string
+		require.NoError(t, snapshot.Get("showme", &fetched))
+		require.Equal(t, "findme", fetched)
+
+		exists, err := snapshot.Has("killme")
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		require.Error(t, snapshot.New("vacuum", "new"))
+	})
+}
+
+// TestBoltSnapshotLimit checks the snapshots of BoltDB share the database
+// and they are limited by `BoltSnapshotLimit`.
+func TestBoltSnapshotLimit(t *testing.T) {
+	st, closeFunc := newTestBoltStorage(t)
+	defer closeFunc()
+
+	require.NoError(t, st.New("showme", "findme"))
+
+	var snapshots []Backend
+	for i := 0; i < BoltSnapshotLimit; i++ {
+		snapshot, err := st.OpenSnapshot()
+		require.NoError(t, err)
+		snapshots = append(snapshots, snapshot)
+	}
+
+	_, err := st.OpenSnapshot()
+	require.Equal(t, errors.SnapshotLimitReached, err)
+
+	// the writes are not blocked by the opened snapshots
+	require.NoError(t, st.Set("showme", "changed"))
+
+	// the released snapshot can not be used and new snapshot can be opened
+	require.NoError(t, snapshots[0].Release())
+	require.NoError(t, snapshots[0].Release())
+	_, err = snapshots[0].Has("showme")
+	require.Error(t, err)
+
+	snapshot, err := st.OpenSnapshot()
+	require.NoError(t, err)
+
+	var fetched string
+	require.NoError(t, snapshot.Get("showme", &fetched))
+	require.Equal(t, "changed", fetched)
+
+	// closing snapshot does not close the database
+	for _, s := range append(snapshots[1:], snapshot) {
+		require.NoError(t, s.Close())
+	}
+	require.NoError(t, st.Set("showme", "changed again"))
+}
+
+func TestBackendPutRaw(t *testing.T) {
+	testBackends(t, func(t *testing.T, st Backend) {
+		require.NoError(t, st.PutRaw("showme", []byte("findme")))
+		require.NoError(t, st.PutRaw("showme", []byte("findme again")))
+
+		b, err := st.GetRaw("showme")
+		require.NoError(t, err)
+		require.Equal(t, []byte("findme again"), b)
+	})
+}
+
+// TestBoltIteratorBatch checks the iterator of BoltDB merges the changes of
+// batch with the items, which are read by pages.
+func TestBoltIteratorBatch(t *testing.T) {
+	st, closeFunc := newTestBoltStorage(t)
+	defer closeFunc()
+
+	total := boltIteratorPageSize*2 + 10
+	for i := 0; i < total; i++ {
+		require.NoError(t, st.New(fmt.Sprintf("item-%04d", i*2), i))
+	}
+	require.NoError(t, st.New("itemz", 0))
+
+	batch, err := st.OpenBatch()
+	require.NoError(t, err)
+
+	expected := map[string]bool{}
+	for i := 0; i < total; i++ {
+		expected[fmt.Sprintf("item-%04d", i*2)] = true
+	}
+	for i := 0; i < total; i += 3 {
+		key := fmt.Sprintf("item-%04d", i*2)
+		require.NoError(t, batch.Remove(key))
+		delete(expected, key)
+	}
+	for i := 0; i < total; i += 5 {
+		key := fmt.Sprintf("item-%04d", i*2+1)
+		require.NoError(t, batch.New(key, i))
+		expected[key] = true
+	}
+	require.NoError(t, batch.Set("item-0002", 100))
+
+	var keys []string
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	collect := func(options ListOptions) (collected []string) {
+		it, closeIt := batch.GetIterator("item-", options)
+		defer closeIt()
+		for {
+			item, hasNext := it()
+			if !hasNext {
+				break
+			}
+			collected = append(collected, string(item.Key))
+		}
+		return
+	}
+
+	require.Equal(t, keys, collect(nil))
+
+	var reversed []string
+	for i := len(keys) - 1; i >= 0; i-- {
+		reversed = append(reversed, keys[i])
+	}
+	require.Equal(t, reversed, collect(NewDefaultListOptions(true, nil, 0)))
+	require.Equal(t, reversed[:1], collect(NewDefaultListOptions(true, nil, 1)))
+	require.Equal(t, keys[11:21], collect(NewDefaultListOptions(false, []byte(keys[10]), 10)))
+	require.Equal(t, reversed[11:21], collect(NewDefaultListOptions(true, []byte(reversed[10]), 10)))
+
+	{ // the changed value of batch
+		it, closeIt := batch.GetIterator("item-0002", nil)
+		item, hasNext := it()
+		closeIt()
+		require.True(t, hasNext)
+
+		var v int
+		require.NoError(t, deserialize(item.Value, &v))
+		require.Equal(t, 100, v)
+	}
+
+	var walked []string
+	err = batch.Walk("item-", NewWalkOption(keys[5], uint64(len(keys)), true), func(k, v []byte) (bool, error) {
+		walked = append(walked, string(k))
+		return true, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, reversed[len(keys)-6:], walked)
+}
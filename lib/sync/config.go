@@ -9,6 +9,7 @@ import (
 	"boscoin.io/sebak/lib/node/runner"
 	"boscoin.io/sebak/lib/storage"
 	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/voting"
 	"github.com/inconshreveable/log15"
 )
 
@@ -22,7 +23,7 @@ const (
 )
 
 type Config struct {
-	storage           *storage.LevelDBBackend
+	storage           storage.Backend
 	connectionManager network.ConnectionManager
 	tp                *transaction.Pool
 	localNode         *node.LocalNode
@@ -30,16 +31,24 @@ type Config struct {
 	logger            log15.Logger
 	commonCfg         common.Config
 
+	// initialValidators is the validators at startup, the validators of the
+	// block are calculated from them.
+	initialValidators []string
+
 	SyncPoolSize             uint64
 	FetchTimeout             time.Duration
 	RetryInterval            time.Duration
 	CheckBlockHeightInterval time.Duration
 	CheckPrevBlockInterval   time.Duration
 	WatchInterval            time.Duration
+
+	// Policy is used to get the threshold of the block certificate; without
+	// it, the certificate is not checked.
+	Policy voting.ThresholdPolicy
 }
 
 func NewConfig(localNode *node.LocalNode,
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	cm network.ConnectionManager,
 	tp *transaction.Pool,
 	cfg common.Config) (*Config, error) {
@@ -57,6 +66,9 @@ func NewConfig(localNode *node.LocalNode,
 		RetryInterval:            RetryInterval,
 		CheckBlockHeightInterval: CheckBlockHeightInterval,
 	}
+	for address := range localNode.GetValidators() {
+		c.initialValidators = append(c.initialValidators, address)
+	}
 	commonAccountAddress, err := c.commonAccountAddress()
 	if err != nil {
 		return nil, err
@@ -101,6 +113,7 @@ func (c *Config) NewValidator() Validator {
 		c.storage,
 		c.tp,
 		c.commonCfg,
+		WithCertificate(c.initialValidators, c.Policy),
 		func(v *BlockValidator) {
 			v.prevBlockWaitTimeout = c.CheckPrevBlockInterval
 			v.logger = c.logger.New("submodule", "validator")
@@ -27,7 +27,7 @@ import (
 type BlockFetcher struct {
 	connectionManager network.ConnectionManager
 	apiClient         Doer
-	storage           *storage.LevelDBBackend
+	storage           storage.Backend
 	localNode         *node.LocalNode
 
 	fetchTimeout  time.Duration
@@ -41,7 +41,7 @@ type BlockFetcherOption = func(f *BlockFetcher)
 func NewBlockFetcher(
 	cm network.ConnectionManager,
 	client Doer,
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	localNode *node.LocalNode,
 	opts ...BlockFetcherOption) *BlockFetcher {
 
@@ -162,6 +162,12 @@ func (f *BlockFetcher) fetch(ctx context.Context, si *SyncInfo) error {
 	blk := blocks[0].(block.Block)
 	si.Block = &blk
 
+	if certificates, ok := items[api.NodeItemBlockCertificate]; ok && len(certificates) > 0 {
+		if c, ok := certificates[0].(ballot.Certificate); ok {
+			si.Certificate = &c
+		}
+	}
+
 	{
 		btmap := make(map[string]*block.BlockTransaction) // For ordering txs by block.Transactions
 
@@ -47,6 +47,8 @@ func (m *mockConnectionManager) Discovery(network.DiscoveryMessage) error {
 	return nil
 }
 
+func (m *mockConnectionManager) UpdateValidators([]*node.Validator, []string) {}
+
 type mockDoer struct {
 	handleFunc func(*http.Request) (*http.Response, error)
 }
@@ -19,7 +19,7 @@ type requestHighestBlock struct {
 }
 
 type Syncer struct {
-	storage *storage.LevelDBBackend
+	storage storage.Backend
 
 	fetcher   Fetcher
 	validator Validator
@@ -46,7 +46,7 @@ type SyncerOption func(s *Syncer)
 func NewSyncer(
 	f Fetcher,
 	v Validator,
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	opts ...SyncerOption) *Syncer {
 	ctx, cancelFunc := context.WithCancel(context.Background())
 
@@ -13,7 +13,7 @@ import (
 
 type SyncerTestContext struct {
 	t         *testing.T
-	st        *storage.LevelDBBackend
+	st        storage.Backend
 	syncer    *Syncer
 	tickC     chan time.Time
 	syncInfoC chan *SyncInfo
@@ -25,6 +25,9 @@ type SyncInfo struct {
 	Bts    []*block.BlockTransaction
 	Ptx    *ballot.ProposerTransaction
 
+	// Certificate is the ACCEPT ballots, which agreed the block
+	Certificate *ballot.Certificate
+
 	// Fetching target node addresses, NodeList is  the validators which
 	// participated and confirmed the consensus of latest ballot.
 	NodeList *NodeList
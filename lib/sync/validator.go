@@ -20,17 +20,32 @@ import (
 //TODO(anarcher) another name is Finisher
 
 type BlockValidator struct {
-	storage   *storage.LevelDBBackend
+	storage   storage.Backend
 	txpool    *transaction.Pool
 	commonCfg common.Config
 
+	// validators and policy are used to check the certificate of block; if
+	// policy is nil, the certificate is not checked.
+	validators []string
+	policy     voting.ThresholdPolicy
+
 	prevBlockWaitTimeout time.Duration // Waiting prev block if is doesn't exist
 	logger               log15.Logger
 }
 
 type BlockValidatorOption func(*BlockValidator)
 
-func NewBlockValidator(ldb *storage.LevelDBBackend, tp *transaction.Pool, cfg common.Config, opts ...BlockValidatorOption) *BlockValidator {
+// WithCertificate makes the validator check the certificate of block by the
+// validators at the block, which are calculated from the initial validators,
+// and the threshold policy.
+func WithCertificate(validators []string, policy voting.ThresholdPolicy) BlockValidatorOption {
+	return func(v *BlockValidator) {
+		v.validators = validators
+		v.policy = policy
+	}
+}
+
+func NewBlockValidator(ldb storage.Backend, tp *transaction.Pool, cfg common.Config, opts ...BlockValidatorOption) *BlockValidator {
 	v := &BlockValidator{
 		storage:              ldb,
 		txpool:               tp,
@@ -89,6 +104,10 @@ func (v *BlockValidator) validate(ctx context.Context, syncInfo *SyncInfo) error
 		return err
 	}
 
+	if err := v.validateCertificate(ctx, syncInfo); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -141,23 +160,25 @@ func (v *BlockValidator) finishBlock(ctx context.Context, syncInfo *SyncInfo) er
 	// ProposerTx
 	{
 		ptx := syncInfo.Ptx
-		if err := runner.ProcessProposerTransaction(bs, blk, *ptx, v.logger); err != nil {
+		if err := runner.FinishProposerTransaction(bs, blk, *ptx, v.logger); err != nil {
 			bs.Discard()
 			return err
 		}
 
 		bt := block.NewBlockTransactionFromTransaction(blk.Hash, blk.Height, blk.ProposedTime, ptx.Transaction)
-		if err := bt.Save(bs); err != nil {
+		if err := bt.SaveBlockOperations(bs); err != nil {
 			bs.Discard()
 			return err
 		}
+	}
 
-		if _, err := block.SaveTransactionPool(bs, ptx.Transaction); err != nil {
-			bs.Discard()
-			return err
-		}
+	if err := v.validateStateRoot(bs, syncInfo, txs); err != nil {
+		bs.Discard()
+		return err
+	}
 
-		if err := bt.SaveBlockOperations(bs); err != nil {
+	if syncInfo.Certificate != nil {
+		if err := syncInfo.Certificate.Save(bs); err != nil {
 			bs.Discard()
 			return err
 		}
@@ -188,6 +209,10 @@ func (v *BlockValidator) finishBlock(ctx context.Context, syncInfo *SyncInfo) er
 
 func (v *BlockValidator) validateBlock(ctx context.Context, si *SyncInfo, prevBlk *block.Block) error {
 	v.logger.Debug("start validate block", "height", si.Height)
+	if si.Block.Version != block.GetBlockVersion(si.Height) {
+		return errors.InvalidBlockVersion
+	}
+
 	var txs []string
 	for _, bt := range si.Bts {
 		txs = append(txs, bt.Hash)
@@ -201,7 +226,7 @@ func (v *BlockValidator) validateBlock(ctx context.Context, si *SyncInfo, prevBl
 		TotalOps:  si.Block.TotalOps,
 	}
 
-	blk := block.NewBlock(si.Block.Proposer, r, si.Block.ProposerTransaction, txs, si.Block.ProposedTime)
+	blk := block.NewBlockWithVersion(si.Block.Version, si.Block.Proposer, r, si.Block.ProposerTransaction, txs, si.Block.ProposedTime, si.Block.StateRoot)
 
 	if blk.Hash != si.Block.Hash {
 		err := errors.HashDoesNotMatch
@@ -213,6 +238,58 @@ func (v *BlockValidator) validateBlock(ctx context.Context, si *SyncInfo, prevBl
 	return nil
 }
 
+// validateCertificate checks the block was agreed by the validators of the
+// block height. The certificate is required since `block.BlockVersionV1`; the
+// blocks of previous version were confirmed before the certificate was kept,
+// so they are checked only if the certificate is found.
+func (v *BlockValidator) validateCertificate(ctx context.Context, si *SyncInfo) error {
+	if v.policy == nil {
+		return nil
+	}
+
+	v.logger.Debug("start validate certificate", "height", si.Height)
+	if si.Certificate == nil {
+		if si.Block.Version < block.BlockVersionV1 {
+			return nil
+		}
+		return errors.InvalidCertificate.Clone().SetData("error", "certificate not found")
+	}
+
+	validators := block.GetValidatorsAt(v.storage, v.validators, si.Height)
+	threshold := v.policy.ThresholdOf(len(validators))
+	if err := si.Certificate.Verify(*si.Block, validators, threshold, v.commonCfg.NetworkID); err != nil {
+		return err
+	}
+
+	v.logger.Debug("end validate certificate", "height", si.Height)
+	return nil
+}
+
+// validateStateRoot commits the accounts changed by the block into the state
+// trie and checks the root is same with the `StateRoot` of block. The blocks
+// before `block.BlockVersionV2` do not have the state root.
+func (v *BlockValidator) validateStateRoot(st storage.Backend, si *SyncInfo, txs []*transaction.Transaction) error {
+	if si.Block.Version < block.BlockVersionV2 {
+		return nil
+	}
+
+	prevBlk, err := block.GetBlockByHeight(st, si.Height-1)
+	if err != nil {
+		return err
+	}
+
+	root, err := runner.CommitState(st, prevBlk.StateRoot, txs, *si.Ptx)
+	if err != nil {
+		return err
+	}
+	if root != si.Block.StateRoot {
+		v.logger.Error("state root does not match", "height", si.Height, "expected", si.Block.StateRoot, "root", root)
+		return errors.InvalidStateRoot
+	}
+
+	return nil
+}
+
 func (v *BlockValidator) validateTxs(ctx context.Context, si *SyncInfo) error {
 	v.logger.Debug("start validate txs", "height", si.Height)
 	// proposer transaction
@@ -243,7 +320,7 @@ func (v *BlockValidator) validateTxs(ctx context.Context, si *SyncInfo) error {
 	return nil
 }
 
-func (v *BlockValidator) existsBlock(ctx context.Context, st *storage.LevelDBBackend, height uint64) (bool, error) {
+func (v *BlockValidator) existsBlock(ctx context.Context, st storage.Backend, height uint64) (bool, error) {
 	select {
 	case <-ctx.Done():
 		return false, ctx.Err()
@@ -4,9 +4,15 @@ import (
 	"context"
 	"testing"
 
+	"boscoin.io/sebak/lib/ballot"
 	"boscoin.io/sebak/lib/block"
 	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/consensus"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/node/runner"
 	"boscoin.io/sebak/lib/transaction"
+	"boscoin.io/sebak/lib/voting"
 	"github.com/stretchr/testify/require"
 )
 
@@ -33,3 +39,187 @@ func TestValidator(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestValidatorCertificate(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+	tp := transaction.NewPool(conf)
+
+	var kps []*keypair.Full
+	var validators []string
+	for i := 0; i < 4; i++ {
+		kp := keypair.Random()
+		kps = append(kps, kp)
+		validators = append(validators, kp.Address())
+	}
+
+	policy, err := consensus.NewDefaultVotingThresholdPolicy(67)
+	require.NoError(t, err)
+
+	bk := block.GetLatestBlock(st)
+	basis := voting.Basis{Height: bk.Height, BlockHash: bk.Hash, TotalTxs: bk.TotalTxs}
+
+	var ptx ballot.ProposerTransaction
+	ptx.H.Hash = ptx.B.MakeHashString()
+
+	var ballots []ballot.Ballot
+	for _, kp := range kps {
+		b := ballot.NewBallot(kp.Address(), validators[0], basis, []string{})
+		b.SetProposerTransaction(ptx)
+		b.SetVote(ballot.StateACCEPT, voting.YES)
+		b.Sign(kp, conf.NetworkID)
+		ballots = append(ballots, *b)
+	}
+
+	next := basis
+	next.Height++
+	blk := *block.NewBlock(validators[0], next, ptx.GetHash(), []string{}, common.NowISO8601())
+
+	ctx := context.Background()
+
+	{ // without policy, certificate is not checked
+		v := NewBlockValidator(st, tp, conf)
+		require.NoError(t, v.validateCertificate(ctx, &SyncInfo{Height: blk.Height, Block: &blk}))
+	}
+
+	v := NewBlockValidator(st, tp, conf, func(v *BlockValidator) {
+		v.validators = validators
+		v.policy = policy
+	})
+
+	{ // certificate not found
+		err := v.validateCertificate(ctx, &SyncInfo{Height: blk.Height, Block: &blk})
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // under threshold
+		c := ballot.NewCertificate(blk, ballots[:2])
+		err := v.validateCertificate(ctx, &SyncInfo{Height: blk.Height, Block: &blk, Certificate: &c})
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // valid
+		c := ballot.NewCertificate(blk, ballots[:3])
+		require.NoError(t, v.validateCertificate(ctx, &SyncInfo{Height: blk.Height, Block: &blk, Certificate: &c}))
+	}
+
+	{ // the block of previous version does not need the certificate
+		old := *block.NewBlockWithVersion(block.BlockVersionV0, validators[0], next, ptx.GetHash(), []string{}, blk.ProposedTime, "")
+		require.NoError(t, v.validateCertificate(ctx, &SyncInfo{Height: old.Height, Block: &old}))
+
+		// but the certificate is checked if found
+		c := ballot.NewCertificate(old, ballots[:2])
+		err := v.validateCertificate(ctx, &SyncInfo{Height: old.Height, Block: &old, Certificate: &c})
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+	}
+
+	{ // the removed validator can not sign the block
+		change := block.NewValidatorSetChange("change", blk.Height, nil, nil, []string{validators[2]})
+		require.NoError(t, change.Save(st))
+
+		c := ballot.NewCertificate(blk, ballots[:3])
+		err := v.validateCertificate(ctx, &SyncInfo{Height: blk.Height, Block: &blk, Certificate: &c})
+		require.Equal(t, errors.InvalidCertificate.Code, err.(*errors.Error).Code)
+
+		// threshold of 3 validators is 3
+		c = ballot.NewCertificate(blk, []ballot.Ballot{ballots[0], ballots[1], ballots[3]})
+		require.NoError(t, v.validateCertificate(ctx, &SyncInfo{Height: blk.Height, Block: &blk, Certificate: &c}))
+	}
+}
+
+func TestValidatorStateRoot(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+	tp := transaction.NewPool(conf)
+
+	v := NewBlockValidator(st, tp, conf)
+
+	bk := block.GetLatestBlock(st)
+	next := voting.Basis{Height: bk.Height + 1, BlockHash: bk.Hash}
+
+	var ptx ballot.ProposerTransaction
+	root, err := runner.CommitState(st, bk.StateRoot, nil, ptx)
+	require.NoError(t, err)
+
+	{ // same state
+		blk := *block.NewBlockWithVersion(block.BlockVersionV2, "proposer", next, "", nil, common.NowISO8601(), root)
+		require.NoError(t, v.validateStateRoot(st, &SyncInfo{Height: blk.Height, Block: &blk, Ptx: &ptx}, nil))
+	}
+
+	{ // different state
+		blk := *block.NewBlockWithVersion(block.BlockVersionV2, "proposer", next, "", nil, common.NowISO8601(), "findme")
+		err := v.validateStateRoot(st, &SyncInfo{Height: blk.Height, Block: &blk, Ptx: &ptx}, nil)
+		require.Equal(t, errors.InvalidStateRoot, err)
+	}
+
+	{ // the block of previous version does not have the state root
+		blk := *block.NewBlockWithVersion(block.BlockVersionV1, "proposer", next, "", nil, common.NowISO8601(), "")
+		require.NoError(t, v.validateStateRoot(st, &SyncInfo{Height: blk.Height, Block: &blk, Ptx: &ptx}, nil))
+	}
+}
+
+func TestValidatorBlockVersion(t *testing.T) {
+	defer block.SetTestBlockVersionHeights(common.FirstProposedBlockHeight, common.FirstProposedBlockHeight)()
+
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+	tp := transaction.NewPool(conf)
+
+	v := NewBlockValidator(st, tp, conf)
+	ctx := context.Background()
+
+	bk := block.GetLatestBlock(st)
+	next := voting.Basis{Height: bk.Height + 1, BlockHash: bk.Hash}
+
+	{ // version of the height
+		blk := *block.NewBlock("proposer", next, "", nil, common.NowISO8601())
+		require.NoError(t, v.validateBlock(ctx, &SyncInfo{Height: blk.Height, Block: &blk}, &bk))
+	}
+
+	{ // the block of previous version after the activation height
+		blk := *block.NewBlockWithVersion(block.BlockVersionV0, "proposer", next, "", nil, common.NowISO8601(), "")
+		err := v.validateBlock(ctx, &SyncInfo{Height: blk.Height, Block: &blk}, &bk)
+		require.Equal(t, errors.InvalidBlockVersion, err)
+	}
+}
+
+func TestValidatorPreviousBlockVersion(t *testing.T) {
+	conf := common.NewTestConfig()
+	st := block.InitTestBlockchain()
+	defer st.Close()
+	tp := transaction.NewPool(conf)
+
+	policy, err := consensus.NewDefaultVotingThresholdPolicy(67)
+	require.NoError(t, err)
+
+	v := NewBlockValidator(st, tp, conf, func(v *BlockValidator) {
+		v.validators = []string{keypair.Random().Address()}
+		v.policy = policy
+	})
+	ctx := context.Background()
+
+	// without the activation heights, the chain keeps `BlockVersionV0`, which
+	// does not have the certificate
+	bk := block.GetLatestBlock(st)
+	for i := 0; i < 3; i++ {
+		blk := block.TestMakeNewBlockWithPrevBlock(bk, nil)
+		require.Equal(t, block.BlockVersionV0, blk.Version)
+		require.NoError(t, v.validate(ctx, &SyncInfo{Height: blk.Height, Block: &blk}))
+		require.NoError(t, blk.Save(st))
+
+		bk = blk
+	}
+	require.True(t, bk.Height > common.FirstProposedBlockHeight)
+
+	{ // the block of next version before the activation height
+		next := voting.Basis{Height: bk.Height + 1, BlockHash: bk.Hash}
+		blk := *block.NewBlockWithVersion(block.BlockVersionV2, "proposer", next, "", nil, common.NowISO8601(), "")
+		err := v.validateBlock(ctx, &SyncInfo{Height: blk.Height, Block: &blk}, &bk)
+		require.Equal(t, errors.InvalidBlockVersion, err)
+	}
+}
@@ -27,7 +27,7 @@ type WatcherOption func(*Watcher)
 type Watcher struct {
 	syncer    SyncController
 	cm        network.ConnectionManager
-	st        *storage.LevelDBBackend
+	st        storage.Backend
 	localNode *node.LocalNode
 	client    Doer
 	after     AfterFunc
@@ -42,7 +42,7 @@ func NewWatcher(
 	syncer SyncController,
 	client Doer,
 	cm network.ConnectionManager,
-	st *storage.LevelDBBackend,
+	st storage.Backend,
 	ln *node.LocalNode,
 	opts ...WatcherOption) *Watcher {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -222,11 +222,18 @@ func (w *Watcher) bestHeightFromNodes(ctx context.Context, nodes []*node.NodeInf
 }
 
 func (w *Watcher) bestNodeAddrs(ctx context.Context, height uint64, nodes []*node.NodeInfo) ([]string, error) {
+	next := w.latestHeight() + 1
+
 	var addrs []string
 	for _, n := range nodes {
 		if n.Node.State != node.StateCONSENSUS && n.Node.State != node.StateWATCH {
 			continue
 		}
+		// the pruned node can not serve the old blocks in full
+		if n.Block.LowestFullHeight > next {
+			w.logger.Info("node is pruned", "node", n.Node.Address, "lowest-full-height", n.Block.LowestFullHeight)
+			continue
+		}
 		addrs = append(addrs, n.Node.Address)
 	}
 	return addrs, nil
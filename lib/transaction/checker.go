@@ -50,6 +50,30 @@ func CheckBaseFee(c common.Checker, args ...interface{}) (err error) {
 	return
 }
 
+func CheckTimeBounds(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*Checker)
+
+	b := checker.Transaction.B
+	if b.MinHeight > 0 && b.MaxHeight > 0 && b.MinHeight > b.MaxHeight {
+		err = errors.TransactionInvalidTimeBounds
+		return
+	}
+
+	return
+}
+
+func CheckMemo(c common.Checker, args ...interface{}) (err error) {
+	checker := c.(*Checker)
+
+	if checker.Transaction.B.Memo == nil {
+		return
+	}
+
+	err = checker.Transaction.B.Memo.IsWellFormed()
+
+	return
+}
+
 func CheckOperationTypes(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*Checker)
 
@@ -71,7 +95,31 @@ func CheckOperations(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*Checker)
 
 	var hashes []string
-	for _, op := range checker.Transaction.B.Operations {
+	ops := checker.Transaction.B.Operations
+	for i, op := range ops {
+		// `AccountMerge` removes the source account, so it should be the last
+		// operation.
+		if mop, ok := op.B.(operation.AccountMerge); ok {
+			if i != len(ops)-1 {
+				err = errors.AccountMergeNotLastOperation
+				return
+			}
+			if checker.Transaction.B.Source == mop.TargetAddress() {
+				err = errors.InvalidOperation
+				return
+			}
+			if err = op.IsWellFormed(checker.Conf); err != nil {
+				return
+			}
+			// the frozen account can not be linked to the merged account
+			for _, prev := range ops[:i] {
+				if cop, ok := prev.B.(operation.CreateAccount); ok && cop.Linked == checker.Transaction.B.Source {
+					err = errors.BlockAccountHasFrozenAccounts
+					return
+				}
+			}
+		}
+
 		if pop, ok := op.B.(operation.Payable); ok {
 			if checker.Transaction.B.Source == pop.TargetAddress() {
 				err = errors.InvalidOperation
@@ -98,16 +146,42 @@ func CheckOperations(c common.Checker, args ...interface{}) (err error) {
 func CheckVerifySignature(c common.Checker, args ...interface{}) (err error) {
 	checker := c.(*Checker)
 
-	var kp keypair.KP
-	if kp, err = keypair.Parse(checker.Transaction.B.Source); err != nil {
+	if len(checker.Transaction.H.Signature) < 1 && len(checker.Transaction.H.Signatures) < 1 {
+		err = errors.TransactionUnknownSigner
 		return
 	}
-	err = kp.Verify(
-		append(checker.NetworkID, []byte(checker.Transaction.H.Hash)...),
-		base58.Decode(checker.Transaction.H.Signature),
-	)
-	if err != nil {
-		return
+
+	message := append(checker.NetworkID, []byte(checker.Transaction.H.Hash)...)
+
+	if len(checker.Transaction.H.Signature) > 0 {
+		var kp keypair.KP
+		if kp, err = keypair.Parse(checker.Transaction.B.Source); err != nil {
+			return
+		}
+		err = kp.Verify(message, base58.Decode(checker.Transaction.H.Signature))
+		if err != nil {
+			return
+		}
+	}
+
+	// the weight of signers will be checked with the source account by
+	// `ValidateTx`
+	signers := map[string]bool{checker.Transaction.B.Source: true}
+	for _, s := range checker.Transaction.H.Signatures {
+		if _, found := signers[s.Signer]; found {
+			err = errors.InvalidSigners.Clone().SetData("error", "duplicated signer")
+			return
+		}
+		signers[s.Signer] = true
+
+		var kp keypair.KP
+		if kp, err = keypair.Parse(s.Signer); err != nil {
+			return
+		}
+		if err = kp.Verify(message, base58.Decode(s.Signature)); err != nil {
+			return
+		}
 	}
+
 	return
 }
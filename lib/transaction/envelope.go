@@ -0,0 +1,75 @@
+package transaction
+
+import (
+	"encoding/json"
+
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+const EnvelopeVersionV1 = "1"
+
+// Envelope is the file format to sign the transaction offline. The
+// transaction is built with the network access and the envelope is signed
+// without it. `NetworkID` prevents the envelope from being signed for the
+// wrong network.
+type Envelope struct {
+	Version     string      `json:"version"`
+	NetworkID   string      `json:"network_id"`
+	Transaction Transaction `json:"transaction"`
+}
+
+func NewEnvelope(networkID []byte, tx Transaction) Envelope {
+	return Envelope{
+		Version:     EnvelopeVersionV1,
+		NetworkID:   string(networkID),
+		Transaction: tx,
+	}
+}
+
+func NewEnvelopeFromJSON(b []byte) (e Envelope, err error) {
+	if err = json.Unmarshal(b, &e); err != nil {
+		err = errors.InvalidEnvelope.Clone().SetData("error", err.Error())
+		return
+	}
+
+	if e.Version != EnvelopeVersionV1 {
+		err = errors.InvalidEnvelope.Clone().SetData("error", "unknown version")
+		return
+	}
+	if len(e.NetworkID) < 1 {
+		err = errors.InvalidEnvelope.Clone().SetData("error", "empty network id")
+		return
+	}
+	if len(e.Transaction.B.Source) < 1 || len(e.Transaction.B.Operations) < 1 {
+		err = errors.InvalidEnvelope.Clone().SetData("error", "empty transaction")
+		return
+	}
+
+	return
+}
+
+func (e Envelope) Serialize() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// Sign signs the transaction in the envelope. If `kp` is the source of
+// transaction, it is signed by `Transaction.Sign`, otherwise it is added as
+// the signature of signer by `Transaction.AddSignature`.
+func (e *Envelope) Sign(kp keypair.KP, networkID []byte) error {
+	if e.NetworkID != string(networkID) {
+		return errors.EnvelopeNetworkIDMismatch
+	}
+
+	if kp.Address() == e.Transaction.Source() {
+		e.Transaction.Sign(kp, networkID)
+	} else {
+		e.Transaction.AddSignature(kp, networkID)
+	}
+
+	return nil
+}
+
+func (e Envelope) IsSigned() bool {
+	return len(e.Transaction.H.Signature) > 0 || len(e.Transaction.H.Signatures) > 0
+}
@@ -0,0 +1,70 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestEnvelope(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	kp, tx := TestMakeTransaction(conf.NetworkID, 1)
+	tx.H.Signature = ""
+
+	e := NewEnvelope(conf.NetworkID, tx)
+	require.False(t, e.IsSigned())
+
+	b, err := e.Serialize()
+	require.NoError(t, err)
+
+	loaded, err := NewEnvelopeFromJSON(b)
+	require.NoError(t, err)
+	require.Equal(t, EnvelopeVersionV1, loaded.Version)
+	require.Equal(t, string(conf.NetworkID), loaded.NetworkID)
+	require.Equal(t, tx.GetHash(), loaded.Transaction.GetHash())
+
+	{ // wrong network id
+		require.Equal(t, errors.EnvelopeNetworkIDMismatch, loaded.Sign(kp, []byte("showme")))
+		require.False(t, loaded.IsSigned())
+	}
+
+	require.NoError(t, loaded.Sign(kp, conf.NetworkID))
+	require.True(t, loaded.IsSigned())
+	require.NoError(t, loaded.Transaction.IsWellFormed(conf))
+
+	{ // signed by the signer of source account
+		signer := keypair.Random()
+		require.NoError(t, loaded.Sign(signer, conf.NetworkID))
+		require.Equal(t, kp.Address(), loaded.Transaction.Source())
+		require.Equal(t, 1, len(loaded.Transaction.H.Signatures))
+		require.Equal(t, signer.Address(), loaded.Transaction.H.Signatures[0].Signer)
+	}
+}
+
+func TestEnvelopeFromJSON(t *testing.T) {
+	conf := common.NewTestConfig()
+	_, tx := TestMakeTransaction(conf.NetworkID, 1)
+
+	_, err := NewEnvelopeFromJSON([]byte("showme"))
+	require.Error(t, err)
+	require.Equal(t, errors.InvalidEnvelope.Code, err.(*errors.Error).Code)
+
+	cases := []Envelope{
+		{Version: "0", NetworkID: string(conf.NetworkID), Transaction: tx},
+		{Version: EnvelopeVersionV1, NetworkID: "", Transaction: tx},
+		{Version: EnvelopeVersionV1, NetworkID: string(conf.NetworkID)},
+	}
+	for _, e := range cases {
+		b, err := e.Serialize()
+		require.NoError(t, err)
+
+		_, err = NewEnvelopeFromJSON(b)
+		require.Error(t, err)
+		require.Equal(t, errors.InvalidEnvelope.Code, err.(*errors.Error).Code)
+	}
+}
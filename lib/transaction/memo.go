@@ -0,0 +1,99 @@
+package transaction
+
+import (
+	"strconv"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+type MemoType byte
+
+const (
+	MemoNone MemoType = iota
+	MemoText
+	MemoID
+	MemoHash
+)
+
+var (
+	memoTypeName []string = []string{
+		"none",
+		"text",
+		"id",
+		"hash",
+	}
+)
+
+// Implement `fmt.Stringer`
+func (mt MemoType) String() string {
+	if int(mt) >= len(memoTypeName) {
+		return ""
+	}
+	return memoTypeName[mt]
+}
+
+// Implement encoding.TextMarshaler
+func (mt MemoType) MarshalText() (text []byte, err error) {
+	return []byte(mt.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler
+func (mt *MemoType) UnmarshalText(text []byte) (err error) {
+	if idx, found := common.InStringArray(memoTypeName, string(text)); !found {
+		return errors.TransactionInvalidMemo
+	} else {
+		*mt = MemoType(idx)
+		return nil
+	}
+}
+
+// Memo is the bounded note of transaction, which is included in the hash of
+// transaction. `Value` depends on `Type`,
+//   - `MemoText`: utf-8 text, not longer than `common.MemoTextLimit` bytes
+//   - `MemoID`: unsigned 64 bit integer in decimal
+//   - `MemoHash`: 32 bytes hash encoded in base58
+type Memo struct {
+	Type  MemoType `json:"type"`
+	Value string   `json:"value"`
+}
+
+func NewMemo(t MemoType, value string) Memo {
+	return Memo{Type: t, Value: value}
+}
+
+func (m Memo) IsEmpty() bool {
+	return m.Type == MemoNone && len(m.Value) < 1
+}
+
+func (m Memo) IsWellFormed() (err error) {
+	switch m.Type {
+	case MemoNone:
+		if len(m.Value) > 0 {
+			return errors.TransactionInvalidMemo
+		}
+	case MemoText:
+		if len(m.Value) < 1 || len(m.Value) > common.MemoTextLimit {
+			return errors.TransactionInvalidMemo
+		}
+	case MemoID:
+		var id uint64
+		if id, err = strconv.ParseUint(m.Value, 10, 64); err != nil {
+			return errors.TransactionInvalidMemo
+		}
+		// without leading zeros, same id has same `Value`
+		if strconv.FormatUint(id, 10) != m.Value {
+			return errors.TransactionInvalidMemo
+		}
+	case MemoHash:
+		if len(base58.Decode(m.Value)) != 32 {
+			return errors.TransactionInvalidMemo
+		}
+	default:
+		return errors.TransactionInvalidMemo
+	}
+
+	return
+}
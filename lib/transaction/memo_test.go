@@ -0,0 +1,58 @@
+package transaction
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestMemoIsWellFormed(t *testing.T) {
+	cases := []struct {
+		memo  Memo
+		valid bool
+	}{
+		{NewMemo(MemoNone, ""), true},
+		{NewMemo(MemoNone, "showme"), false},
+		{NewMemo(MemoText, "showme"), true},
+		{NewMemo(MemoText, ""), false},
+		{NewMemo(MemoText, strings.Repeat("a", common.MemoTextLimit)), true},
+		{NewMemo(MemoText, strings.Repeat("a", common.MemoTextLimit+1)), false},
+		{NewMemo(MemoID, "18446744073709551615"), true},
+		{NewMemo(MemoID, "18446744073709551616"), false},
+		{NewMemo(MemoID, "0010"), false},
+		{NewMemo(MemoID, "-1"), false},
+		{NewMemo(MemoHash, base58.Encode(common.MakeHash([]byte("showme")))), true},
+		{NewMemo(MemoHash, base58.Encode([]byte("showme"))), false},
+		{NewMemo(MemoType(9), "showme"), false},
+	}
+
+	for i, c := range cases {
+		err := c.memo.IsWellFormed()
+		if c.valid {
+			require.NoError(t, err, "case %d", i)
+		} else {
+			require.Equal(t, errors.TransactionInvalidMemo, err, "case %d", i)
+		}
+	}
+}
+
+func TestMemoJSON(t *testing.T) {
+	memo := NewMemo(MemoID, "100")
+
+	b, err := json.Marshal(memo)
+	require.NoError(t, err)
+	require.Equal(t, `{"type":"id","value":"100"}`, string(b))
+
+	var unmarshaled Memo
+	require.NoError(t, json.Unmarshal(b, &unmarshaled))
+	require.Equal(t, memo, unmarshaled)
+
+	err = json.Unmarshal([]byte(`{"type":"unknown","value":"100"}`), &unmarshaled)
+	require.Error(t, err)
+}
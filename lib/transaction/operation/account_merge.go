@@ -0,0 +1,36 @@
+package operation
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+)
+
+// AccountMerge closes the source account; the whole balance of the source
+// account is transferred to `Target` and the source account is removed. It
+// must be the last operation of transaction.
+type AccountMerge struct {
+	Target string `json:"target"`
+}
+
+func NewAccountMerge(target string) AccountMerge {
+	return AccountMerge{
+		Target: target,
+	}
+}
+
+// Implement transaction/operation : IsWellFormed
+func (o AccountMerge) IsWellFormed(common.Config) (err error) {
+	if _, err = keypair.Parse(o.Target); err != nil {
+		return
+	}
+
+	return
+}
+
+func (o AccountMerge) TargetAddress() string {
+	return o.Target
+}
+
+func (o AccountMerge) HasFee() bool {
+	return true
+}
@@ -0,0 +1,33 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+)
+
+func TestAccountMergeOperation(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	o := NewAccountMerge(keypair.Random().Address())
+	require.NoError(t, o.IsWellFormed(conf))
+	require.True(t, o.HasFee())
+
+	o = NewAccountMerge("invalid-address")
+	require.Error(t, o.IsWellFormed(conf))
+}
+
+func TestAccountMergeSerialize(t *testing.T) {
+	op, err := NewOperation(NewAccountMerge(keypair.Random().Address()))
+	require.NoError(t, err)
+	require.Equal(t, TypeAccountMerge, op.H.Type)
+
+	var decoded Operation
+	common.MustUnmarshalJSON(common.MustMarshalJSON(op), &decoded)
+	require.Equal(t, op, decoded)
+
+	common.CheckRoundTripRLP(t, op)
+}
@@ -0,0 +1,47 @@
+package operation
+
+import (
+	"unicode/utf8"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// ManageData sets the data entry of the source account by `Key`. If `Value`
+// is empty, the existing entry will be deleted. Every entry locks the
+// `common.BaseReserve` of the account balance.
+type ManageData struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func NewManageData(key, value string) ManageData {
+	return ManageData{
+		Key:   key,
+		Value: value,
+	}
+}
+
+// IsDelete returns true if the operation deletes the entry.
+func (o ManageData) IsDelete() bool {
+	return len(o.Value) < 1
+}
+
+// Implement transaction/operation : IsWellFormed
+func (o ManageData) IsWellFormed(common.Config) (err error) {
+	if len(o.Key) < 1 || len(o.Key) > common.DataEntryKeyLimit {
+		return errors.InvalidDataEntry.Clone().SetData("error", "invalid key length")
+	}
+	if !utf8.ValidString(o.Key) {
+		return errors.InvalidDataEntry.Clone().SetData("error", "key is not valid utf-8")
+	}
+	if len(o.Value) > common.DataEntryValueLimit {
+		return errors.InvalidDataEntry.Clone().SetData("error", "invalid value length")
+	}
+
+	return
+}
+
+func (o ManageData) HasFee() bool {
+	return true
+}
@@ -0,0 +1,59 @@
+package operation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestManageDataOperation(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	{ // set
+		o := NewManageData("kyc", "showme")
+		require.NoError(t, o.IsWellFormed(conf))
+		require.False(t, o.IsDelete())
+	}
+
+	{ // delete
+		o := NewManageData("kyc", "")
+		require.NoError(t, o.IsWellFormed(conf))
+		require.True(t, o.IsDelete())
+	}
+
+	{ // empty key
+		o := NewManageData("", "showme")
+		require.Equal(t, errors.InvalidDataEntry.Code, o.IsWellFormed(conf).(*errors.Error).Code)
+	}
+
+	{ // too long key
+		o := NewManageData(strings.Repeat("k", common.DataEntryKeyLimit+1), "showme")
+		require.Equal(t, errors.InvalidDataEntry.Code, o.IsWellFormed(conf).(*errors.Error).Code)
+	}
+
+	{ // invalid utf-8 key
+		o := NewManageData(string([]byte{0xff, 0xfe}), "showme")
+		require.Equal(t, errors.InvalidDataEntry.Code, o.IsWellFormed(conf).(*errors.Error).Code)
+	}
+
+	{ // too long value
+		o := NewManageData("kyc", strings.Repeat("v", common.DataEntryValueLimit+1))
+		require.Equal(t, errors.InvalidDataEntry.Code, o.IsWellFormed(conf).(*errors.Error).Code)
+	}
+}
+
+func TestManageDataSerialize(t *testing.T) {
+	op, err := NewOperation(NewManageData("kyc", "showme"))
+	require.NoError(t, err)
+	require.Equal(t, TypeManageData, op.H.Type)
+
+	var decoded Operation
+	common.MustUnmarshalJSON(common.MustMarshalJSON(op), &decoded)
+	require.Equal(t, op, decoded)
+
+	common.CheckRoundTripRLP(t, op)
+}
@@ -0,0 +1,98 @@
+package operation
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// ManageSigners replaces the signers and the thresholds of the source account.
+// If `Signers` is empty, the account is controlled only by the secret seed of
+// it's own address like the normal account.
+type ManageSigners struct {
+	Signers    []Signer   `json:"signers"`
+	Thresholds Thresholds `json:"thresholds"`
+}
+
+// Signer is the address which can sign the transaction of account with it's
+// `Weight`.
+type Signer struct {
+	Address string `json:"address"`
+	Weight  uint32 `json:"weight"`
+}
+
+// Thresholds is the minimum sum of signer weights to authorize the
+// operations. Each operation type requires one of `Low`, `Medium` and `High`,
+// see `RequiredThreshold`.
+type Thresholds struct {
+	Low    uint32 `json:"low"`
+	Medium uint32 `json:"medium"`
+	High   uint32 `json:"high"`
+}
+
+func NewManageSigners(signers []Signer, thresholds Thresholds) ManageSigners {
+	return ManageSigners{
+		Signers:    signers,
+		Thresholds: thresholds,
+	}
+}
+
+// Implement transaction/operation : IsWellFormed
+func (o ManageSigners) IsWellFormed(common.Config) (err error) {
+	if len(o.Signers) > common.MaxSignersInAccount {
+		return errors.InvalidSigners
+	}
+
+	var totalWeight uint64
+	addresses := map[string]bool{}
+	for _, s := range o.Signers {
+		if _, err = keypair.Parse(s.Address); err != nil {
+			return errors.InvalidSigners.Clone().SetData("error", err.Error())
+		}
+		if _, found := addresses[s.Address]; found {
+			return errors.InvalidSigners.Clone().SetData("error", "duplicated signer")
+		}
+		if s.Weight < 1 {
+			return errors.InvalidSigners.Clone().SetData("error", "weight must be greater than 0")
+		}
+		addresses[s.Address] = true
+		totalWeight += uint64(s.Weight)
+	}
+
+	t := o.Thresholds
+	if t.Low > t.Medium || t.Medium > t.High {
+		return errors.InvalidThresholds
+	}
+
+	if len(o.Signers) < 1 {
+		if t.High > 0 {
+			return errors.InvalidThresholds
+		}
+		return
+	}
+
+	// the signers must be able to authorize every operations, otherwise the
+	// account will be locked forever.
+	if t.High < 1 || totalWeight < uint64(t.High) {
+		return errors.InvalidThresholds
+	}
+
+	return
+}
+
+func (o ManageSigners) HasFee() bool {
+	return true
+}
+
+// RequiredThreshold returns the threshold, which the signatures of
+// transaction should satisfy for the given operation type.
+func (t Thresholds) RequiredThreshold(ot OperationType) uint32 {
+	switch ot {
+	case TypeManageSigners, TypeAccountMerge, TypeUpdateValidators:
+		return t.High
+	case TypeCongressVoting, TypeCongressVotingResult, TypeUnfreezingRequest:
+		return t.Low
+	default:
+		return t.Medium
+	}
+}
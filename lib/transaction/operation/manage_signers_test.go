@@ -0,0 +1,103 @@
+package operation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestManageSignersOperation(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	signers := []Signer{
+		Signer{Address: keypair.Random().Address(), Weight: 1},
+		Signer{Address: keypair.Random().Address(), Weight: 1},
+		Signer{Address: keypair.Random().Address(), Weight: 1},
+	}
+
+	{ // 2 of 3
+		o := NewManageSigners(signers, Thresholds{Low: 1, Medium: 2, High: 2})
+		require.NoError(t, o.IsWellFormed(conf))
+	}
+
+	{ // reset to normal account
+		o := NewManageSigners(nil, Thresholds{})
+		require.NoError(t, o.IsWellFormed(conf))
+	}
+
+	{ // thresholds without signers
+		o := NewManageSigners(nil, Thresholds{Low: 1, Medium: 1, High: 1})
+		require.Equal(t, errors.InvalidThresholds, o.IsWellFormed(conf))
+	}
+
+	{ // High is over total weight
+		o := NewManageSigners(signers, Thresholds{Low: 1, Medium: 2, High: 4})
+		require.Equal(t, errors.InvalidThresholds, o.IsWellFormed(conf))
+	}
+
+	{ // the total weight does not wrap around
+		overflow := append([]Signer{}, signers...)
+		overflow[0].Weight = math.MaxUint32
+		o := NewManageSigners(overflow, Thresholds{Low: 1, Medium: 2, High: 3})
+		require.NoError(t, o.IsWellFormed(conf))
+	}
+
+	{ // Medium is over High
+		o := NewManageSigners(signers, Thresholds{Low: 1, Medium: 3, High: 2})
+		require.Equal(t, errors.InvalidThresholds, o.IsWellFormed(conf))
+	}
+
+	{ // zero weight
+		invalid := append([]Signer{}, signers...)
+		invalid[0].Weight = 0
+		o := NewManageSigners(invalid, Thresholds{Low: 1, Medium: 2, High: 2})
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidSigners.Code, err.(*errors.Error).Code)
+	}
+
+	{ // duplicated signer
+		invalid := append([]Signer{}, signers...)
+		invalid[1].Address = invalid[0].Address
+		o := NewManageSigners(invalid, Thresholds{Low: 1, Medium: 2, High: 2})
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidSigners.Code, err.(*errors.Error).Code)
+	}
+
+	{ // invalid address
+		invalid := append([]Signer{}, signers...)
+		invalid[2].Address = "invalid-address"
+		o := NewManageSigners(invalid, Thresholds{Low: 1, Medium: 2, High: 2})
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidSigners.Code, err.(*errors.Error).Code)
+	}
+}
+
+func TestManageSignersRequiredThreshold(t *testing.T) {
+	th := Thresholds{Low: 1, Medium: 2, High: 3}
+
+	require.Equal(t, uint32(3), th.RequiredThreshold(TypeManageSigners))
+	require.Equal(t, uint32(2), th.RequiredThreshold(TypePayment))
+	require.Equal(t, uint32(2), th.RequiredThreshold(TypeCreateAccount))
+	require.Equal(t, uint32(1), th.RequiredThreshold(TypeUnfreezingRequest))
+}
+
+func TestManageSignersSerialize(t *testing.T) {
+	opb := NewManageSigners(
+		[]Signer{Signer{Address: keypair.Random().Address(), Weight: 2}},
+		Thresholds{Low: 1, Medium: 2, High: 2},
+	)
+	op, err := NewOperation(opb)
+	require.NoError(t, err)
+	require.Equal(t, TypeManageSigners, op.H.Type)
+
+	var decoded Operation
+	common.MustUnmarshalJSON(common.MustMarshalJSON(op), &decoded)
+	require.Equal(t, op, decoded)
+
+	common.CheckRoundTripRLP(t, op)
+}
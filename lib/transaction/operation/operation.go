@@ -20,6 +20,10 @@ const (
 	TypeInflation
 	TypeUnfreezingRequest
 	TypeInflationPF
+	TypeManageSigners
+	TypeManageData
+	TypeAccountMerge
+	TypeUpdateValidators
 )
 
 var (
@@ -32,6 +36,10 @@ var (
 		"inflation",
 		"unfreezing-request",
 		"inflation-pf",
+		"manage-signers",
+		"manage-data",
+		"account-merge",
+		"update-validators",
 	}
 )
 
@@ -60,7 +68,9 @@ func IsNormalOperation(t OperationType) bool {
 	switch t {
 	case TypeCreateAccount, TypePayment,
 		TypeCongressVoting, TypeCongressVotingResult,
-		TypeUnfreezingRequest, TypeInflationPF:
+		TypeUnfreezingRequest, TypeInflationPF,
+		TypeManageSigners, TypeManageData, TypeAccountMerge,
+		TypeUpdateValidators:
 		return true
 	default:
 		return false
@@ -91,6 +101,14 @@ func NewOperation(opb Body) (op Operation, err error) {
 		t = TypeCongressVotingResult
 	case InflationPF:
 		t = TypeInflationPF
+	case ManageSigners:
+		t = TypeManageSigners
+	case ManageData:
+		t = TypeManageData
+	case AccountMerge:
+		t = TypeAccountMerge
+	case UpdateValidators:
+		t = TypeUpdateValidators
 	default:
 		err = errors.UnknownOperationType
 		return
@@ -263,6 +281,14 @@ func newBodyFromType(ty OperationType) (interface{}, error) {
 		return &UnfreezeRequest{}, nil
 	case TypeInflationPF:
 		return &InflationPF{}, nil
+	case TypeManageSigners:
+		return &ManageSigners{}, nil
+	case TypeManageData:
+		return &ManageData{}, nil
+	case TypeAccountMerge:
+		return &AccountMerge{}, nil
+	case TypeUpdateValidators:
+		return &UpdateValidators{}, nil
 	default:
 		return nil, errors.InvalidOperation
 	}
@@ -0,0 +1,63 @@
+package operation
+
+import (
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+// UpdateValidators adds and removes the validators from the block of `Height`;
+// the ballots for the block of `Height` are voted by the updated validators.
+// Only the congress account can update the validators. `Endpoints` are the
+// endpoints of the added validators in the same order of `Add`, so the nodes
+// can connect to the added validators.
+type UpdateValidators struct {
+	Height    uint64   `json:"height"`
+	Add       []string `json:"add"`
+	Endpoints []string `json:"endpoints"`
+	Remove    []string `json:"remove"`
+}
+
+func NewUpdateValidators(height uint64, add, endpoints, remove []string) UpdateValidators {
+	return UpdateValidators{
+		Height:    height,
+		Add:       add,
+		Endpoints: endpoints,
+		Remove:    remove,
+	}
+}
+
+// Implement transaction/operation : IsWellFormed
+func (o UpdateValidators) IsWellFormed(common.Config) (err error) {
+	if o.Height < 1 {
+		return errors.InvalidValidatorSetChange.Clone().SetData("error", "height must be greater than 0")
+	}
+	if len(o.Add) < 1 && len(o.Remove) < 1 {
+		return errors.OperationBodyInsufficient
+	}
+	if len(o.Add) != len(o.Endpoints) {
+		return errors.InvalidValidatorSetChange.Clone().SetData("error", "endpoints must be given for the added validators")
+	}
+	for _, endpoint := range o.Endpoints {
+		if _, err = common.ParseEndpoint(endpoint); err != nil {
+			return errors.InvalidValidatorSetChange.Clone().SetData("error", err.Error())
+		}
+	}
+
+	addresses := map[string]bool{}
+	for _, address := range append(append([]string{}, o.Add...), o.Remove...) {
+		if _, err = keypair.Parse(address); err != nil {
+			return errors.InvalidValidatorSetChange.Clone().SetData("error", err.Error())
+		}
+		if _, found := addresses[address]; found {
+			return errors.InvalidValidatorSetChange.Clone().SetData("error", "duplicated validator")
+		}
+		addresses[address] = true
+	}
+
+	return
+}
+
+func (o UpdateValidators) HasFee() bool {
+	return true
+}
@@ -0,0 +1,68 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/common/keypair"
+	"boscoin.io/sebak/lib/errors"
+)
+
+func TestUpdateValidatorsOperation(t *testing.T) {
+	conf := common.NewTestConfig()
+
+	added := keypair.Random().Address()
+	removed := keypair.Random().Address()
+	endpoints := []string{"https://localhost:12345"}
+
+	{
+		o := NewUpdateValidators(10, []string{added}, endpoints, []string{removed})
+		require.NoError(t, o.IsWellFormed(conf))
+
+		op, err := NewOperation(o)
+		require.NoError(t, err)
+		require.Equal(t, TypeUpdateValidators, op.H.Type)
+	}
+
+	{ // only remove
+		o := NewUpdateValidators(10, nil, nil, []string{removed})
+		require.NoError(t, o.IsWellFormed(conf))
+	}
+
+	{ // zero height
+		o := NewUpdateValidators(0, []string{added}, endpoints, nil)
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidValidatorSetChange.Code, err.(*errors.Error).Code)
+	}
+
+	{ // nothing to update
+		o := NewUpdateValidators(10, nil, nil, nil)
+		require.Equal(t, errors.OperationBodyInsufficient, o.IsWellFormed(conf))
+	}
+
+	{ // invalid address
+		o := NewUpdateValidators(10, []string{"showme"}, endpoints, nil)
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidValidatorSetChange.Code, err.(*errors.Error).Code)
+	}
+
+	{ // added and removed at once
+		o := NewUpdateValidators(10, []string{added}, endpoints, []string{added})
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidValidatorSetChange.Code, err.(*errors.Error).Code)
+	}
+
+	{ // endpoint of added validator is missing
+		o := NewUpdateValidators(10, []string{added}, nil, nil)
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidValidatorSetChange.Code, err.(*errors.Error).Code)
+	}
+
+	{ // invalid endpoint
+		o := NewUpdateValidators(10, []string{added}, []string{"showme"}, nil)
+		err := o.IsWellFormed(conf)
+		require.Equal(t, errors.InvalidValidatorSetChange.Code, err.(*errors.Error).Code)
+	}
+}
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"container/list"
+	"sort"
 	"sync"
 
 	"boscoin.io/sebak/lib/common"
@@ -15,12 +16,41 @@ type Pool struct {
 	Pool    map[ /* Transaction.GetHash() */ string]Transaction
 	sources map[ /* Transaction.Source() */ string] /* Transaction.GetHash() */ string
 
-	hashList *list.List // Transaction.GetHash()
+	hashList *list.List // poolItem
 	hashMap  map[ /* Transaction.GetHash() */ string]*list.Element
 
+	// priority is ordered by the fee per operation; the higher fee comes first
+	// and the same fee follows the arrival order.
+	priority []poolItem
+	arrived  uint64
+
 	cfg common.Config
 }
 
+// poolItem is the element of `Pool.hashList` and `Pool.priority`.
+type poolItem struct {
+	hash     string
+	feePerOp common.Amount
+	arrived  uint64
+}
+
+func newPoolItem(tx Transaction, arrived uint64) poolItem {
+	return poolItem{
+		hash:     tx.GetHash(),
+		feePerOp: tx.FeePerOperation(),
+		arrived:  arrived,
+	}
+}
+
+// higher checks `a` should be proposed before `b`.
+func (a poolItem) higher(b poolItem) bool {
+	if a.feePerOp != b.feePerOp {
+		return a.feePerOp > b.feePerOp
+	}
+
+	return a.arrived < b.arrived
+}
+
 func NewPool(cfg common.Config) *Pool {
 	return &Pool{
 		Pool:     map[string]Transaction{},
@@ -61,42 +91,142 @@ func (tp *Pool) GetFromSource(source string) (Transaction, bool) {
 	if !found {
 		return Transaction{}, false
 	}
-	return tp.Get(hash)
+	tx, found := tp.Pool[hash]
+	return tx, found
 }
 
-func (tp *Pool) add(tx Transaction, limit int) error {
+// add adds the transaction and returns the hash of the transaction, which is
+// evicted by the replacement or by the limit of pool.
+func (tp *Pool) add(tx Transaction, limit int) (evicted string, err error) {
+	tp.Lock()
+	defer tp.Unlock()
+
 	txHash := tx.GetHash()
-	if tp.Has(txHash) {
-		return errors.TransactionAlreadyExistsInPool
+	if _, found := tp.Pool[txHash]; found {
+		err = errors.TransactionAlreadyExistsInPool
+		return
 	}
 
-	if limit > 0 && tp.Len() >= limit {
-		return errors.TransactionPoolFull
+	// the replacement does not increase the size of pool
+	var replaced bool
+	if evicted, replaced = tp.replace(tx); replaced {
+		return
+	}
+
+	if limit > 0 && len(tp.Pool) >= limit {
+		// the lowest fee transaction is evicted for the higher fee one
+		if len(tp.priority) < 1 {
+			err = errors.TransactionPoolFull
+			return
+		}
+		lowest := tp.priority[len(tp.priority)-1]
+		if tx.FeePerOperation() <= lowest.feePerOp {
+			err = errors.TransactionPoolFull
+			return
+		}
+		tp.remove(lowest.hash)
+		metrics.TxPool.AddSize(-1)
+		evicted = lowest.hash
 	}
 
 	metrics.TxPool.AddSize(1)
 
-	tp.Lock()
-	defer tp.Unlock()
+	tp.Pool[txHash] = tx
+	tp.sources[tx.Source()] = txHash
+
+	tp.arrived++
+	item := newPoolItem(tx, tp.arrived)
+	tp.hashMap[txHash] = tp.hashList.PushBack(item)
+	tp.insertPriority(item)
+
+	return
+}
+
+// replace evicts the pending transaction of the same source if `tx` can
+// replace it, see `Transaction.CanReplace`. The replacement takes the arrival
+// order of the evicted one. `replace` must be called with the lock.
+func (tp *Pool) replace(tx Transaction) (evicted string, replaced bool) {
+	oldHash, found := tp.sources[tx.Source()]
+	if !found {
+		return
+	}
+	old, found := tp.Pool[oldHash]
+	if !found || !tx.CanReplace(old) {
+		return
+	}
 
+	txHash := tx.GetHash()
+	delete(tp.Pool, oldHash)
 	tp.Pool[txHash] = tx
 	tp.sources[tx.Source()] = txHash
 
-	e := tp.hashList.PushBack(txHash)
-	tp.hashMap[txHash] = e
+	if e, ok := tp.hashMap[oldHash]; ok {
+		oldItem := e.Value.(poolItem)
+		tp.removePriority(oldItem)
+
+		item := newPoolItem(tx, oldItem.arrived)
+		delete(tp.hashMap, oldHash)
+		e.Value = item
+		tp.hashMap[txHash] = e
+		tp.insertPriority(item)
+	} else {
+		tp.arrived++
+		item := newPoolItem(tx, tp.arrived)
+		tp.hashMap[txHash] = tp.hashList.PushBack(item)
+		tp.insertPriority(item)
+	}
+
+	return oldHash, true
+}
+
+// remove removes the transaction from the indices. `remove` must be called
+// with the lock.
+func (tp *Pool) remove(hash string) bool {
+	tx, found := tp.Pool[hash]
+	if !found {
+		return false
+	}
+
+	delete(tp.sources, tx.Source())
+	delete(tp.Pool, hash)
+	if e, ok := tp.hashMap[hash]; ok {
+		tp.removePriority(e.Value.(poolItem))
+		tp.hashList.Remove(e)
+		delete(tp.hashMap, hash)
+	}
+
+	return true
+}
+
+func (tp *Pool) searchPriority(item poolItem) int {
+	return sort.Search(len(tp.priority), func(i int) bool {
+		return !tp.priority[i].higher(item)
+	})
+}
+
+func (tp *Pool) insertPriority(item poolItem) {
+	i := tp.searchPriority(item)
+	tp.priority = append(tp.priority, poolItem{})
+	copy(tp.priority[i+1:], tp.priority[i:])
+	tp.priority[i] = item
+}
 
-	return nil
+func (tp *Pool) removePriority(item poolItem) {
+	i := tp.searchPriority(item)
+	if i < len(tp.priority) && tp.priority[i].hash == item.hash {
+		tp.priority = append(tp.priority[:i], tp.priority[i+1:]...)
+	}
 }
 
-func (tp *Pool) AddFromClient(tx Transaction) error {
+func (tp *Pool) AddFromClient(tx Transaction) (string, error) {
 	return tp.add(tx, tp.cfg.TxPoolClientLimit)
 }
 
-func (tp *Pool) AddFromNode(tx Transaction) error {
+func (tp *Pool) AddFromNode(tx Transaction) (string, error) {
 	return tp.add(tx, tp.cfg.TxPoolNodeLimit)
 }
 
-func (tp *Pool) Add(tx Transaction) error {
+func (tp *Pool) Add(tx Transaction) (string, error) {
 	return tp.add(tx, 0)
 }
 
@@ -110,13 +240,7 @@ func (tp *Pool) Remove(hashes ...string) {
 
 	var num int
 	for _, hash := range hashes {
-		if tx, found := tp.Pool[hash]; found {
-			delete(tp.sources, tx.Source())
-			delete(tp.Pool, hash)
-			if e, ok := tp.hashMap[hash]; ok {
-				tp.hashList.Remove(e)
-				delete(tp.hashMap, hash)
-			}
+		if tp.remove(hash) {
 			num++
 		}
 	}
@@ -136,13 +260,7 @@ func (tp *Pool) RemoveFromSources(sources ...string) {
 	var num int
 	for _, source := range sources {
 		if hash, found := tp.sources[source]; found {
-			if _, found := tp.Pool[hash]; found {
-				delete(tp.sources, source)
-				delete(tp.Pool, hash)
-				if e, ok := tp.hashMap[hash]; ok {
-					tp.hashList.Remove(e)
-					delete(tp.hashMap, hash)
-				}
+			if tp.remove(hash) {
 				num++
 			}
 		}
@@ -151,6 +269,26 @@ func (tp *Pool) RemoveFromSources(sources ...string) {
 	metrics.TxPool.AddSize(-num)
 }
 
+// RemoveExpired removes the transactions, which can not be included in the
+// block of the given height by their time bounds.
+func (tp *Pool) RemoveExpired(height uint64) (removed []string) {
+	tp.RLock()
+	for e := tp.hashList.Front(); e != nil; e = e.Next() {
+		hash := e.Value.(poolItem).hash
+		if tx := tp.Pool[hash]; tx.IsExpired(height) {
+			removed = append(removed, hash)
+		}
+	}
+	tp.RUnlock()
+
+	tp.Remove(removed...)
+
+	return
+}
+
+// AvailableTransactions returns the transaction hashes in the order of the
+// fee per operation; the transactions of same fee are ordered by their
+// arrival.
 func (tp *Pool) AvailableTransactions(transactionLimit int) []string {
 	if transactionLimit < 1 {
 		return nil
@@ -160,17 +298,11 @@ func (tp *Pool) AvailableTransactions(transactionLimit int) []string {
 	defer tp.RUnlock()
 
 	var ret []string
-	var cnt int
-	// first ouput by order older hash
-	for e := tp.hashList.Front(); e != nil; e = e.Next() {
-		if cnt >= transactionLimit {
-			return ret
-		}
-		hash, ok := e.Value.(string)
-		if ok {
-			ret = append(ret, hash)
-			cnt++
+	for _, item := range tp.priority {
+		if len(ret) >= transactionLimit {
+			break
 		}
+		ret = append(ret, item.hash)
 	}
 
 	return ret
@@ -0,0 +1,195 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestPoolRemoveExpired(t *testing.T) {
+	conf := common.NewTestConfig()
+	tp := NewPool(conf)
+
+	_, tx0 := TestMakeTransaction(conf.NetworkID, 1) // without time bounds
+	_, err := tp.Add(tx0)
+	require.NoError(t, err)
+
+	kp1, tx1 := TestMakeTransaction(conf.NetworkID, 1)
+	tx1.B.MaxHeight = 10
+	tx1.Sign(kp1, conf.NetworkID)
+	_, err = tp.Add(tx1)
+	require.NoError(t, err)
+
+	kp2, tx2 := TestMakeTransaction(conf.NetworkID, 1)
+	tx2.B.MaxHeight = 11
+	tx2.Sign(kp2, conf.NetworkID)
+	_, err = tp.Add(tx2)
+	require.NoError(t, err)
+
+	require.Equal(t, 0, len(tp.RemoveExpired(10)))
+	require.Equal(t, 3, tp.Len())
+
+	removed := tp.RemoveExpired(11)
+	require.Equal(t, []string{tx1.GetHash()}, removed)
+	require.Equal(t, 2, tp.Len())
+	require.False(t, tp.Has(tx1.GetHash()))
+	require.False(t, tp.IsSameSource(tx1.Source()))
+	require.Equal(t, []string{tx0.GetHash(), tx2.GetHash()}, tp.AvailableTransactions(10))
+
+	removed = tp.RemoveExpired(100)
+	require.Equal(t, []string{tx2.GetHash()}, removed)
+	require.Equal(t, []string{tx0.GetHash()}, tp.AvailableTransactions(10))
+}
+
+func TestPoolReplace(t *testing.T) {
+	conf := common.NewTestConfig()
+	tp := NewPool(conf)
+
+	kp0, tx0 := TestMakeTransaction(conf.NetworkID, 1)
+	_, err := tp.Add(tx0)
+	require.NoError(t, err)
+	_, tx1 := TestMakeTransaction(conf.NetworkID, 1)
+	_, err = tp.Add(tx1)
+	require.NoError(t, err)
+
+	{ // same fee can not replace
+		tx := tx0
+		tx.B.Operations = []operation.Operation{operation.MakeTestPayment(-1)}
+		tx.Sign(kp0, conf.NetworkID)
+		require.False(t, tx.CanReplace(tx0))
+	}
+
+	{ // different sequence id can not replace
+		tx := tx0
+		tx.B.SequenceID = tx0.B.SequenceID + 1
+		tx.B.Fee = tx0.B.Fee.MustAdd(1)
+		tx.Sign(kp0, conf.NetworkID)
+		require.False(t, tx.CanReplace(tx0))
+	}
+
+	replacement := tx0
+	replacement.B.Fee = tx0.B.Fee.MustAdd(1)
+	replacement.Sign(kp0, conf.NetworkID)
+	require.True(t, replacement.CanReplace(tx0))
+	require.False(t, tx0.CanReplace(replacement))
+
+	evicted, err := tp.Add(replacement)
+	require.NoError(t, err)
+	require.Equal(t, tx0.GetHash(), evicted)
+	require.Equal(t, 2, tp.Len())
+	require.False(t, tp.Has(tx0.GetHash()))
+	require.True(t, tp.Has(replacement.GetHash()))
+
+	fromSource, found := tp.GetFromSource(kp0.Address())
+	require.True(t, found)
+	require.Equal(t, replacement.GetHash(), fromSource.GetHash())
+
+	// the replacement keeps the order of the evicted one
+	require.Equal(t, []string{replacement.GetHash(), tx1.GetHash()}, tp.AvailableTransactions(10))
+
+	tp.Remove(replacement.GetHash())
+	require.Equal(t, 1, tp.Len())
+	require.False(t, tp.IsSameSource(kp0.Address()))
+}
+
+func TestPoolReplaceFull(t *testing.T) {
+	conf := common.NewTestConfig()
+	conf.TxPoolClientLimit = 1
+	tp := NewPool(conf)
+
+	kp0, tx0 := TestMakeTransaction(conf.NetworkID, 1)
+	_, err := tp.AddFromClient(tx0)
+	require.NoError(t, err)
+
+	_, tx1 := TestMakeTransaction(conf.NetworkID, 1)
+	_, err = tp.AddFromClient(tx1)
+	require.Equal(t, errors.TransactionPoolFull, err)
+
+	replacement := tx0
+	replacement.B.Fee = tx0.B.Fee.MustAdd(1)
+	replacement.Sign(kp0, conf.NetworkID)
+	evicted, err := tp.AddFromClient(replacement)
+	require.NoError(t, err)
+	require.Equal(t, tx0.GetHash(), evicted)
+	require.Equal(t, 1, tp.Len())
+	require.True(t, tp.Has(replacement.GetHash()))
+}
+
+func TestPoolFeePriority(t *testing.T) {
+	conf := common.NewTestConfig()
+	tp := NewPool(conf)
+
+	withFee := func(n int, extra common.Amount) Transaction {
+		kp, tx := TestMakeTransaction(conf.NetworkID, n)
+		tx.B.Fee = tx.B.Fee.MustAdd(extra)
+		tx.Sign(kp, conf.NetworkID)
+		return tx
+	}
+
+	tx0 := withFee(1, 0)
+	tx1 := withFee(1, 100)
+	tx2 := withFee(1, 0)
+	tx3 := withFee(4, 100) // 25 per operation
+	for _, tx := range []Transaction{tx0, tx1, tx2, tx3} {
+		evicted, err := tp.Add(tx)
+		require.NoError(t, err)
+		require.Empty(t, evicted)
+	}
+
+	require.Equal(
+		t,
+		[]string{tx1.GetHash(), tx3.GetHash(), tx0.GetHash(), tx2.GetHash()},
+		tp.AvailableTransactions(10),
+	)
+	require.Equal(t, []string{tx1.GetHash(), tx3.GetHash()}, tp.AvailableTransactions(2))
+
+	tp.Remove(tx3.GetHash())
+	require.Equal(t, []string{tx1.GetHash(), tx0.GetHash(), tx2.GetHash()}, tp.AvailableTransactions(10))
+
+	tp.RemoveFromSources(tx1.Source())
+	require.Equal(t, []string{tx0.GetHash(), tx2.GetHash()}, tp.AvailableTransactions(10))
+}
+
+func TestPoolEvictLowestFee(t *testing.T) {
+	conf := common.NewTestConfig()
+	conf.TxPoolClientLimit = 2
+	tp := NewPool(conf)
+
+	withFee := func(extra common.Amount) Transaction {
+		kp, tx := TestMakeTransaction(conf.NetworkID, 1)
+		tx.B.Fee = tx.B.Fee.MustAdd(extra)
+		tx.Sign(kp, conf.NetworkID)
+		return tx
+	}
+
+	tx0 := withFee(10)
+	tx1 := withFee(0)
+	_, err := tp.AddFromClient(tx0)
+	require.NoError(t, err)
+	_, err = tp.AddFromClient(tx1)
+	require.NoError(t, err)
+
+	// same fee with the lowest can not evict it
+	_, err = tp.AddFromClient(withFee(0))
+	require.Equal(t, errors.TransactionPoolFull, err)
+
+	tx2 := withFee(5)
+	evicted, err := tp.AddFromClient(tx2)
+	require.NoError(t, err)
+	require.Equal(t, tx1.GetHash(), evicted)
+	require.Equal(t, 2, tp.Len())
+	require.False(t, tp.Has(tx1.GetHash()))
+	require.False(t, tp.IsSameSource(tx1.Source()))
+	require.Equal(t, []string{tx0.GetHash(), tx2.GetHash()}, tp.AvailableTransactions(10))
+
+	// without limit, nothing is evicted
+	tx3 := withFee(0)
+	evicted, err = tp.Add(tx3)
+	require.NoError(t, err)
+	require.Empty(t, evicted)
+	require.Equal(t, 3, tp.Len())
+}
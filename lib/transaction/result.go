@@ -0,0 +1,40 @@
+package transaction
+
+import (
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+// OperationResult is the validation result of each operation of transaction.
+// If the operation is valid, `Code` is 0, otherwise `Code` and `Message` come
+// from the `errors.Error`.
+type OperationResult struct {
+	Index   int                     `json:"index"`
+	Type    operation.OperationType `json:"type"`
+	Code    uint                    `json:"code"`
+	Message string                  `json:"message,omitempty"`
+}
+
+func NewOperationResult(index int, op operation.Operation, err error) OperationResult {
+	r := OperationResult{
+		Index: index,
+		Type:  op.H.Type,
+	}
+	if err == nil {
+		return r
+	}
+
+	if e, ok := err.(*errors.Error); ok {
+		r.Code = e.Code
+		r.Message = e.Message
+	} else {
+		r.Code = errors.InvalidOperation.Code
+		r.Message = err.Error()
+	}
+
+	return r
+}
+
+func (r OperationResult) IsValid() bool {
+	return r.Code == 0
+}
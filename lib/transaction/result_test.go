@@ -0,0 +1,40 @@
+package transaction
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"boscoin.io/sebak/lib/common"
+	"boscoin.io/sebak/lib/errors"
+	"boscoin.io/sebak/lib/transaction/operation"
+)
+
+func TestNewOperationResult(t *testing.T) {
+	op, _ := operation.NewOperation(operation.NewPayment("GDIRF4UWPACXPPI4GW7CMTACTCNDIKJEHZK44RITZB4TD3YUM6CCVNGJ", common.Amount(1)))
+
+	{ // valid
+		r := NewOperationResult(0, op, nil)
+		require.True(t, r.IsValid())
+		require.Equal(t, 0, r.Index)
+		require.Equal(t, operation.TypePayment, r.Type)
+		require.Equal(t, uint(0), r.Code)
+		require.Empty(t, r.Message)
+	}
+
+	{ // errors.Error
+		r := NewOperationResult(1, op, errors.BlockAccountDoesNotExists)
+		require.False(t, r.IsValid())
+		require.Equal(t, 1, r.Index)
+		require.Equal(t, errors.BlockAccountDoesNotExists.Code, r.Code)
+		require.Equal(t, errors.BlockAccountDoesNotExists.Message, r.Message)
+	}
+
+	{ // other error
+		r := NewOperationResult(2, op, fmt.Errorf("showme"))
+		require.False(t, r.IsValid())
+		require.Equal(t, errors.InvalidOperation.Code, r.Code)
+		require.Equal(t, "showme", r.Message)
+	}
+}
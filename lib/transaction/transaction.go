@@ -2,8 +2,10 @@ package transaction
 
 import (
 	"encoding/json"
+	"io"
 
 	"github.com/btcsuite/btcutil/base58"
+	"github.com/ethereum/go-ethereum/rlp"
 
 	"boscoin.io/sebak/lib/common"
 	"boscoin.io/sebak/lib/common/keypair"
@@ -32,6 +34,15 @@ type Header struct {
 	// has to validate it anyway.
 	Hash      string `json:"-"`
 	Signature string `json:"signature"`
+	// Signatures are the additional signatures for the account, which has
+	// multiple signers; `Signature` is still the signature of `Body.Source`.
+	Signatures []Signature `json:"signatures,omitempty"`
+}
+
+// Signature is the signature of transaction hash by `Signer`.
+type Signature struct {
+	Signer    string `json:"signer"`
+	Signature string `json:"signature"`
 }
 
 type Body struct {
@@ -39,6 +50,103 @@ type Body struct {
 	Fee        common.Amount         `json:"fee"`
 	SequenceID uint64                `json:"sequence_id"`
 	Operations []operation.Operation `json:"operations"`
+	// MinHeight and MaxHeight are the optional time bounds; the transaction
+	// can be included only in the block, whose height is between them. 0 means
+	// unbounded.
+	MinHeight uint64 `json:"min_height,omitempty"`
+	MaxHeight uint64 `json:"max_height,omitempty"`
+	Memo      *Memo  `json:"memo,omitempty"`
+}
+
+// bodyRLPV1 is the RLP layout of `Body` without the optional fields. `Body`
+// without the optional fields is encoded by `bodyRLPV1`, so the hash of the
+// existing transactions is not changed.
+type bodyRLPV1 struct {
+	Source     string
+	Fee        common.Amount
+	SequenceID uint64
+	Operations []operation.Operation
+}
+
+type bodyRLP struct {
+	Source     string
+	Fee        common.Amount
+	SequenceID uint64
+	Operations []operation.Operation
+	MinHeight  uint64
+	MaxHeight  uint64
+	Memo       Memo
+}
+
+func (tb Body) hasOptionalFields() bool {
+	return tb.MinHeight > 0 || tb.MaxHeight > 0 || !tb.GetMemo().IsEmpty()
+}
+
+// GetMemo returns `Memo`; without memo, it returns the empty `Memo`.
+func (tb Body) GetMemo() Memo {
+	if tb.Memo == nil {
+		return Memo{}
+	}
+	return *tb.Memo
+}
+
+// Implement `common.Encoder`
+func (tb Body) EncodeRLP(w io.Writer) error {
+	if !tb.hasOptionalFields() {
+		return common.Encode(w, bodyRLPV1{
+			Source:     tb.Source,
+			Fee:        tb.Fee,
+			SequenceID: tb.SequenceID,
+			Operations: tb.Operations,
+		})
+	}
+
+	return common.Encode(w, bodyRLP{
+		Source:     tb.Source,
+		Fee:        tb.Fee,
+		SequenceID: tb.SequenceID,
+		Operations: tb.Operations,
+		MinHeight:  tb.MinHeight,
+		MaxHeight:  tb.MaxHeight,
+		Memo:       tb.GetMemo(),
+	})
+}
+
+// Implement `common.Decoder`
+func (tb *Body) DecodeRLP(s *common.RLPStream) error {
+	raw, err := s.Raw()
+	if err != nil {
+		return err
+	}
+
+	var b bodyRLP
+	if err = rlp.DecodeBytes(raw, &b); err != nil {
+		var v1 bodyRLPV1
+		if err = rlp.DecodeBytes(raw, &v1); err != nil {
+			return err
+		}
+		b = bodyRLP{
+			Source:     v1.Source,
+			Fee:        v1.Fee,
+			SequenceID: v1.SequenceID,
+			Operations: v1.Operations,
+		}
+	}
+
+	*tb = Body{
+		Source:     b.Source,
+		Fee:        b.Fee,
+		SequenceID: b.SequenceID,
+		Operations: b.Operations,
+		MinHeight:  b.MinHeight,
+		MaxHeight:  b.MaxHeight,
+	}
+	if !b.Memo.IsEmpty() {
+		memo := b.Memo
+		tb.Memo = &memo
+	}
+
+	return nil
 }
 
 func (tb Body) MakeHash() []byte {
@@ -100,6 +208,8 @@ func NewTransaction(source string, sequenceID uint64, ops ...operation.Operation
 var TransactionWellFormedCheckerFuncs = []common.CheckerFunc{
 	CheckOverOperationsLimit,
 	CheckSource,
+	CheckTimeBounds,
+	CheckMemo,
 	CheckBaseFee,
 	CheckOperationTypes,
 	CheckOperations,
@@ -137,6 +247,28 @@ func (tx Transaction) IsValidSequenceID(sequenceID uint64) bool {
 	return tx.B.SequenceID == sequenceID
 }
 
+// HasTimeBounds returns true if the transaction has `MinHeight` or
+// `MaxHeight`.
+func (tx Transaction) HasTimeBounds() bool {
+	return tx.B.MinHeight > 0 || tx.B.MaxHeight > 0
+}
+
+// IsValidBlockHeight checks the transaction can be included in the block of
+// the given height by it's time bounds.
+func (tx Transaction) IsValidBlockHeight(height uint64) bool {
+	if tx.B.MinHeight > 0 && height < tx.B.MinHeight {
+		return false
+	}
+
+	return !tx.IsExpired(height)
+}
+
+// IsExpired returns true if the transaction can not be included in the block
+// of the given height or the later blocks.
+func (tx Transaction) IsExpired(height uint64) bool {
+	return tx.B.MaxHeight > 0 && height > tx.B.MaxHeight
+}
+
 func (tx Transaction) GetHash() string {
 	return tx.H.Hash
 }
@@ -178,6 +310,12 @@ func (tx Transaction) TotalAmount(withFee bool) common.Amount {
 
 // TotalBaseFee returns the minimum fee of transaction.
 func (tx Transaction) TotalBaseFee() common.Amount {
+	return tx.MinimumFee(common.BaseFee)
+}
+
+// MinimumFee returns the minimum fee of transaction by the given base fee of
+// operation.
+func (tx Transaction) MinimumFee(baseFee common.Amount) common.Amount {
 	var opsHaveFee int
 	for _, op := range tx.B.Operations {
 		if op.HasFee() {
@@ -188,7 +326,28 @@ func (tx Transaction) TotalBaseFee() common.Amount {
 		return common.Amount(0)
 	}
 
-	return common.BaseFee.MustMult(opsHaveFee)
+	return baseFee.MustMult(opsHaveFee)
+}
+
+// FeePerOperation returns the `Fee` divided by the number of operations, it
+// decides the priority of transaction in `Pool`.
+func (tx Transaction) FeePerOperation() common.Amount {
+	if len(tx.B.Operations) < 1 {
+		return tx.B.Fee
+	}
+
+	return common.Amount(uint64(tx.B.Fee) / uint64(len(tx.B.Operations)))
+}
+
+// CanReplace checks this transaction can replace the pending transaction,
+// `old`; they must have the same source and `SequenceID`, and the `Fee` of
+// this transaction must be strictly higher than the `Fee` of `old`.
+func (tx Transaction) CanReplace(old Transaction) bool {
+	if tx.B.Source != old.B.Source || tx.B.SequenceID != old.B.SequenceID {
+		return false
+	}
+
+	return tx.B.Fee > old.B.Fee
 }
 
 func (tx Transaction) Serialize() (encoded []byte, err error) {
@@ -211,6 +370,27 @@ func (tx *Transaction) Sign(kp keypair.KP, networkID []byte) {
 	return
 }
 
+// AddSignature signs the transaction by the signer of the source account.
+// Unlike `Sign`, `Body.Source` is not changed.
+func (tx *Transaction) AddSignature(kp keypair.KP, networkID []byte) {
+	tx.H.Hash = tx.B.MakeHashString()
+	signature, _ := keypair.MakeSignature(kp, networkID, tx.H.Hash)
+
+	for i, s := range tx.H.Signatures {
+		if s.Signer == kp.Address() {
+			tx.H.Signatures[i].Signature = base58.Encode(signature)
+			return
+		}
+	}
+
+	tx.H.Signatures = append(tx.H.Signatures, Signature{
+		Signer:    kp.Address(),
+		Signature: base58.Encode(signature),
+	})
+
+	return
+}
+
 func (tx Transaction) IsEmpty() bool {
 	return len(tx.GetHash()) < 1
 }
@@ -178,6 +178,63 @@ func (suite *TestSuite) TestIsWellFormedTransactionWithInvalidSignatureSuite() {
 	require.NotNil(suite.T(), err)
 }
 
+func (suite *TestSuite) TestIsWellFormedTransactionWithSignaturesSuite() {
+	var err error
+
+	signer0 := keypair.Random()
+	signer1 := keypair.Random()
+
+	{ // signed by signers only
+		kp, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.H.Signature = ""
+		tx.AddSignature(signer0, suite.conf.NetworkID)
+		tx.AddSignature(signer1, suite.conf.NetworkID)
+		require.Equal(suite.T(), kp.Address(), tx.B.Source)
+		require.Equal(suite.T(), 2, len(tx.H.Signatures))
+
+		err = tx.IsWellFormed(suite.conf)
+		require.NoError(suite.T(), err)
+
+		// signing again by same signer replaces the previous signature
+		tx.AddSignature(signer1, suite.conf.NetworkID)
+		require.Equal(suite.T(), 2, len(tx.H.Signatures))
+	}
+
+	{ // signed by source and signers
+		_, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.AddSignature(signer0, suite.conf.NetworkID)
+
+		err = tx.IsWellFormed(suite.conf)
+		require.NoError(suite.T(), err)
+	}
+
+	{ // no signature
+		_, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.H.Signature = ""
+
+		err = tx.IsWellFormed(suite.conf)
+		require.Equal(suite.T(), errors.TransactionUnknownSigner, err)
+	}
+
+	{ // invalid signature of signer
+		_, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.AddSignature(signer0, suite.conf.NetworkID)
+		tx.H.Signatures[0].Signer = signer1.Address()
+
+		err = tx.IsWellFormed(suite.conf)
+		require.Error(suite.T(), err)
+	}
+
+	{ // duplicated signer
+		_, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.AddSignature(signer0, suite.conf.NetworkID)
+		tx.H.Signatures = append(tx.H.Signatures, tx.H.Signatures[0])
+
+		err = tx.IsWellFormed(suite.conf)
+		require.Equal(suite.T(), errors.InvalidSigners.Code, err.(*errors.Error).Code)
+	}
+}
+
 func (suite *TestSuite) TestIsWellFormedTransactionMaxOperationsInTransactionSuite() {
 	var err error
 
@@ -194,6 +251,144 @@ func (suite *TestSuite) TestIsWellFormedTransactionMaxOperationsInTransactionSui
 	}
 }
 
+func (suite *TestSuite) TestIsWellFormedTransactionWithTimeBoundsSuite() {
+	var err error
+
+	{ // valid bounds
+		kp, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.B.MinHeight = 10
+		tx.B.MaxHeight = 10
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.NoError(suite.T(), err)
+	}
+
+	{ // only upper bound
+		kp, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.B.MaxHeight = 10
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.NoError(suite.T(), err)
+	}
+
+	{ // MinHeight is over MaxHeight
+		kp, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		tx.B.MinHeight = 11
+		tx.B.MaxHeight = 10
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.Equal(suite.T(), errors.TransactionInvalidTimeBounds, err)
+	}
+}
+
+func (suite *TestSuite) TestIsWellFormedTransactionWithMemoSuite() {
+	var err error
+
+	{ // valid memo
+		kp, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		memo := NewMemo(MemoText, "showme")
+		tx.B.Memo = &memo
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.NoError(suite.T(), err)
+	}
+
+	{ // invalid memo
+		kp, tx := TestMakeTransaction(suite.conf.NetworkID, 1)
+		memo := NewMemo(MemoID, "showme")
+		tx.B.Memo = &memo
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.Equal(suite.T(), errors.TransactionInvalidMemo, err)
+	}
+}
+
+func (suite *TestSuite) TestIsWellFormedTransactionWithAccountMergeSuite() {
+	var err error
+
+	kp := keypair.Random()
+	merge, _ := operation.NewOperation(operation.NewAccountMerge(keypair.Random().Address()))
+	payment := operation.MakeTestPayment(10000)
+
+	{ // valid
+		tx, _ := NewTransaction(kp.Address(), 0, payment, merge)
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.NoError(suite.T(), err)
+	}
+
+	{ // not the last operation
+		tx, _ := NewTransaction(kp.Address(), 0, merge, payment)
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.Equal(suite.T(), errors.AccountMergeNotLastOperation, err)
+	}
+
+	{ // merge to itself
+		self, _ := operation.NewOperation(operation.NewAccountMerge(kp.Address()))
+		tx, _ := NewTransaction(kp.Address(), 0, self)
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.Equal(suite.T(), errors.InvalidOperation, err)
+	}
+
+	{ // creating frozen account, which is linked to the merged account
+		create, _ := operation.NewOperation(operation.NewCreateAccount(keypair.Random().Address(), common.BaseReserve, kp.Address()))
+		tx, _ := NewTransaction(kp.Address(), 0, create, merge)
+		tx.Sign(kp, suite.conf.NetworkID)
+		err = tx.IsWellFormed(suite.conf)
+		require.Equal(suite.T(), errors.BlockAccountHasFrozenAccounts, err)
+	}
+}
+
+func TestTransactionIsValidBlockHeight(t *testing.T) {
+	_, tx := TestMakeTransaction(common.NewTestConfig().NetworkID, 1)
+	require.False(t, tx.HasTimeBounds())
+	require.True(t, tx.IsValidBlockHeight(1))
+	require.False(t, tx.IsExpired(1000))
+
+	tx.B.MinHeight = 10
+	tx.B.MaxHeight = 20
+	require.True(t, tx.HasTimeBounds())
+	require.False(t, tx.IsValidBlockHeight(9))
+	require.True(t, tx.IsValidBlockHeight(10))
+	require.True(t, tx.IsValidBlockHeight(20))
+	require.False(t, tx.IsValidBlockHeight(21))
+	require.False(t, tx.IsExpired(20))
+	require.True(t, tx.IsExpired(21))
+}
+
+func TestTransactionBodyRLP(t *testing.T) {
+	_, tx := TestMakeTransaction(common.NewTestConfig().NetworkID, 2)
+
+	{ // without time bounds, the hash is same with the previous layout
+		legacy := bodyRLPV1{
+			Source:     tx.B.Source,
+			Fee:        tx.B.Fee,
+			SequenceID: tx.B.SequenceID,
+			Operations: tx.B.Operations,
+		}
+		require.Equal(t, common.MustMakeObjectHash(legacy), tx.B.MakeHash())
+		common.CheckRoundTripRLP(t, tx.B)
+	}
+
+	{ // with time bounds
+		hash := tx.B.MakeHashString()
+		tx.B.MaxHeight = 100
+		require.NotEqual(t, hash, tx.B.MakeHashString())
+		common.CheckRoundTripRLP(t, tx.B)
+	}
+
+	{ // with memo
+		tx.B.MaxHeight = 0
+		hash := tx.B.MakeHashString()
+		memo := NewMemo(MemoText, "showme")
+		tx.B.Memo = &memo
+		require.NotEqual(t, hash, tx.B.MakeHashString())
+		common.CheckRoundTripRLP(t, tx.B)
+	}
+}
+
 func TestTransaction(t *testing.T) {
 	suite.Run(t, new(TestSuite))
 }
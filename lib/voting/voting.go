@@ -11,6 +11,8 @@ const (
 
 type ThresholdPolicy interface {
 	Threshold() int
+	// ThresholdOf returns the threshold for the given number of validators
+	ThresholdOf(int) int
 	Validators() int
 	// Set the number of validators required for consensus
 	// The parameter must be a strictly positive integer